@@ -97,6 +97,8 @@ fi
 # OVN_NORTHD_BACKOFF_INTERVAL - ovn northd backoff interval in ms (default 300)
 # OVN_ENABLE_SVC_TEMPLATE_SUPPORT - enable svc template support
 # OVN_ENABLE_DNSNAMERESOLVER - enable dns name resolver support
+# OVN_EGRESSFIREWALL_DNS_LEARNING_ADDRESS - address receiving the cluster DNS dnstap messages for wildcard EgressFirewall DNS names
+# OVN_EGRESSFIREWALL_DNS_LEARNING_PEERS - namespace/label-selector of the cluster DNS pods allowed to send the dnstap messages
 # OVN_ALLOW_ICMP_NETPOL - allow ICMP and ICMPv6 regardless of network policy
 # OVN_OBSERV_ENABLE - enable observability for ovnkube
 
@@ -329,6 +331,12 @@ ovn_enable_svc_template_support=${OVN_ENABLE_SVC_TEMPLATE_SUPPORT:-true}
 ovn_network_qos_enable=${OVN_NETWORK_QOS_ENABLE:-false}
 # OVN_ENABLE_DNSNAMERESOLVER - enable dns name resolver support
 ovn_enable_dnsnameresolver=${OVN_ENABLE_DNSNAMERESOLVER:-false}
+# OVN_EGRESSFIREWALL_DNS_LEARNING_ADDRESS - tcp://host:port or unix:///path address receiving the dnstap messages
+# of the cluster DNS, to learn the names matching wildcard EgressFirewall DNS names without DNSNameResolver
+ovn_egressfirewall_dns_learning_address=${OVN_EGRESSFIREWALL_DNS_LEARNING_ADDRESS:-}
+# OVN_EGRESSFIREWALL_DNS_LEARNING_PEERS - namespace/label-selector of the cluster DNS pods allowed to send the dnstap
+# messages to a tcp OVN_EGRESSFIREWALL_DNS_LEARNING_ADDRESS
+ovn_egressfirewall_dns_learning_peers=${OVN_EGRESSFIREWALL_DNS_LEARNING_PEERS:-}
 # OVN_ALLOW_ICMP_NETPOL - allow ICMP/ICMPv6 with network policy
 ovn_allow_icmp_netpol=${OVN_ALLOW_ICMP_NETPOL:-false}
 # OVN_OBSERV_ENABLE - enable observability for ovnkube
@@ -1509,6 +1517,15 @@ ovnkube-controller() {
   fi
   echo "ovn_enable_dnsnameresolver_flag=${ovn_enable_dnsnameresolver_flag}"
 
+  ovn_egressfirewall_dns_learning_address_flag=
+  if [[ -n ${ovn_egressfirewall_dns_learning_address} ]]; then
+	  ovn_egressfirewall_dns_learning_address_flag="--egress-firewall-dns-learning-address=${ovn_egressfirewall_dns_learning_address}"
+  fi
+  if [[ -n ${ovn_egressfirewall_dns_learning_peers} ]]; then
+	  ovn_egressfirewall_dns_learning_address_flag="${ovn_egressfirewall_dns_learning_address_flag} --egress-firewall-dns-learning-peers=${ovn_egressfirewall_dns_learning_peers}"
+  fi
+  echo "ovn_egressfirewall_dns_learning_address_flag=${ovn_egressfirewall_dns_learning_address_flag}"
+
   ovn_allow_icmp_netpol_flag=
   if [[ ${ovn_allow_icmp_netpol} == "true" ]]; then
 	  ovn_allow_icmp_netpol_flag="--allow-icmp-network-policy"
@@ -1566,6 +1583,7 @@ ovnkube-controller() {
     ${ovn_v6_masquerade_subnet_opt} \
     ${network_qos_enabled_flag} \
     ${ovn_enable_dnsnameresolver_flag} \
+    ${ovn_egressfirewall_dns_learning_address_flag} \
     ${dynamic_udn_allocation_flag} \
     ${dynamic_udn_grace_period} \
     ${ovn_allow_icmp_netpol_flag} \
@@ -2010,6 +2028,15 @@ ovnkube-controller-with-node() {
   fi
   echo "ovn_enable_dnsnameresolver_flag=${ovn_enable_dnsnameresolver_flag}"
 
+  ovn_egressfirewall_dns_learning_address_flag=
+  if [[ -n ${ovn_egressfirewall_dns_learning_address} ]]; then
+	  ovn_egressfirewall_dns_learning_address_flag="--egress-firewall-dns-learning-address=${ovn_egressfirewall_dns_learning_address}"
+  fi
+  if [[ -n ${ovn_egressfirewall_dns_learning_peers} ]]; then
+	  ovn_egressfirewall_dns_learning_address_flag="${ovn_egressfirewall_dns_learning_address_flag} --egress-firewall-dns-learning-peers=${ovn_egressfirewall_dns_learning_peers}"
+  fi
+  echo "ovn_egressfirewall_dns_learning_address_flag=${ovn_egressfirewall_dns_learning_address_flag}"
+
   ovn_allow_icmp_netpol_flag=
   if [[ ${ovn_allow_icmp_netpol} == "true" ]]; then
 	  ovn_allow_icmp_netpol_flag="--allow-icmp-network-policy"
@@ -2114,6 +2141,7 @@ ovnkube-controller-with-node() {
     ${dynamic_udn_grace_period} \
     ${network_qos_enabled_flag} \
     ${ovn_enable_dnsnameresolver_flag} \
+    ${ovn_egressfirewall_dns_learning_address_flag} \
     ${ovn_disable_requestedchassis_flag} \
     ${cluster_access_opts} \
     ${ovn_allow_icmp_netpol_flag} \
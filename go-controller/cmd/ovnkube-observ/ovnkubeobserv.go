@@ -7,11 +7,17 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	observ "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/exporter"
 )
 
 func main() {
@@ -34,9 +40,72 @@ func main() {
 	outputFile := flag.String("output-file", "", "Output file to write the samples to.")
 	filterSrcIP := flag.String("filter-src-ip", "", "Filter in only packets from a given source ip.")
 	filterDstIP := flag.String("filter-dst-ip", "", "Filter in only packets to a given destination ip.")
+	filterNamespace := flag.String("filter-namespace", "", "Filter in only packets from or to pods in a given namespace, or generated by objects in a given namespace.")
+	filterPod := flag.String("filter-pod", "", "Filter in only packets from or to a given pod, as name or namespace/name.")
+	filterNetwork := flag.String("filter-network", "", fmt.Sprintf("Filter in only packets from or to pods on a given network: UDN as namespace/name, CUDN as name, or %q.", observ.DefaultNetworkName))
+	filterAction := flag.String("filter-action", "", "Filter in only packets matched by ACLs with a given action, e.g. allow or drop.")
+	filterOwnerType := flag.String("filter-owner-type", "", "Filter in only packets matched by ACLs of a given owner type, e.g. NetworkPolicy or AdminNetworkPolicy.")
+	filterProtocol := flag.String("filter-protocol", "", "Filter in only packets of a given IP protocol, e.g. tcp.")
+	filterPort := flag.Uint("filter-port", 0, "Filter in only packets from or to a given port.")
+	aggregateWindow := flag.Duration("aggregate-window", 0, "Print the top talkers and denied flows over a given sliding window instead of every sample. Disabled when 0.")
+	aggregateInterval := flag.Duration("aggregate-interval", 10*time.Second, "How often to print the aggregated flows with -aggregate-window.")
+	topN := flag.Int("top", 10, "Number of flows to print with -aggregate-window.")
+	ipfixCollector := flag.String("ipfix-collector", "", "Export samples as IPFIX records to the given host:port UDP collector.")
+	ipfixEnterpriseID := flag.Uint("ipfix-enterprise-id", 0, "Private enterprise number used to export the decoded OVN-K message in enterprise-specific IPFIX fields. Disabled when 0.")
+	otlpEndpoint := flag.String("otlp-endpoint", "", fmt.Sprintf("Export samples as OpenTelemetry logs to the given OTLP/HTTP logs endpoint, e.g. %s.", exporter.DefaultOTLPLogsEndpoint))
+	metricsBindAddress := flag.String("metrics-bind-address", "", "Serve Prometheus sample counters on the given address, e.g. :9410.")
 	flag.Parse()
 
 	reader := observ.NewSampleReader(*enableDecoder, *logCookie, *printPacket, *addOVSCollector, *filterSrcIP, *filterDstIP, *outputFile)
+	if *filterPort > 65535 {
+		fmt.Printf("invalid -filter-port %d\n", *filterPort)
+		os.Exit(1)
+	}
+	reader.SetFilter(&observ.SampleFilter{
+		Namespace: *filterNamespace,
+		Pod:       *filterPod,
+		Network:   *filterNetwork,
+		Action:    *filterAction,
+		OwnerType: *filterOwnerType,
+		Protocol:  *filterProtocol,
+		Port:      uint16(*filterPort),
+	})
+	if *aggregateWindow > 0 {
+		if *aggregateInterval <= 0 || *topN <= 0 {
+			fmt.Println("-aggregate-interval and -top must be positive")
+			os.Exit(1)
+		}
+		reader.SetAggregation(*aggregateWindow, *aggregateInterval, *topN)
+	}
+	if *ipfixCollector != "" {
+		ipfixExporter, err := exporter.NewIPFIXExporter(*ipfixCollector, uint32(*ipfixEnterpriseID))
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		reader.AddExporter(ipfixExporter)
+	}
+	if *otlpEndpoint != "" {
+		reader.AddExporter(exporter.NewOTLPLogsExporter(*otlpEndpoint))
+	}
+	if *metricsBindAddress != "" {
+		registry := prometheus.NewRegistry()
+		promExporter, err := exporter.NewPrometheusExporter(registry)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		reader.AddExporter(promExporter)
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		server := &http.Server{Addr: *metricsBindAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fmt.Printf("ERROR: metrics server failed: %v\n", err)
+			}
+		}()
+		defer server.Close()
+	}
 	err := reader.ReadSamples(ctx)
 	if err != nil {
 		fmt.Println(err.Error())
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	anpcontroller "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/ovn/controller/admin_network_policy"
+)
+
+// describeACLOwner decodes the libovsdbops.DbObjectIDs stored in the ACL's external_ids and returns a description
+// of the Kubernetes object that owns the ACL, e.g. "NetworkPolicy ns/foo rule ingress[2]". Returns "" if the ACL
+// was not created by ovn-kubernetes.
+func describeACLOwner(aclHit *ACLHit) string {
+	ownerType := aclHit.ExternalIDs[libovsdbops.OwnerTypeKey.String()]
+	if ownerType == "" {
+		return ""
+	}
+	name := aclHit.ExternalIDs[libovsdbops.ObjectNameKey.String()]
+	direction := strings.ToLower(aclHit.ExternalIDs[libovsdbops.PolicyDirectionKey.String()])
+	// rule returns the rule of the owner that the ACL was created for, e.g. "rule ingress[2]".
+	rule := func(idxKey libovsdbops.ExternalIDKey) string {
+		if idx, ok := aclHit.ExternalIDs[idxKey.String()]; ok {
+			return fmt.Sprintf("rule %s[%s]", direction, idx)
+		}
+		if direction != "" {
+			return "direction " + direction
+		}
+		return ""
+	}
+
+	switch ownerType {
+	case libovsdbops.NetworkPolicyOwnerType, libovsdbops.NetworkPolicyPortIndexOwnerType:
+		if namespace, policyName, err := libovsdbops.ParseNamespaceNameKey(name); err == nil {
+			name = namespace + "/" + policyName
+		}
+		return strings.TrimSpace("NetworkPolicy " + name + " " + rule(libovsdbops.GressIdxKey))
+	case libovsdbops.AdminNetworkPolicyOwnerType:
+		// ANP ACL priorities are derived from the ANP priority, see newAdminNetworkPolicyState.
+		anpPriority := (anpcontroller.ANPFlowStartPriority - aclHit.Priority) / anpcontroller.ANPMaxRulesPerObject
+		return strings.TrimSuffix(fmt.Sprintf("AdminNetworkPolicy %s, priority %d, %s", name, anpPriority, rule(libovsdbops.GressIdxKey)), ", ")
+	case libovsdbops.BaselineAdminNetworkPolicyOwnerType:
+		return strings.TrimSuffix(fmt.Sprintf("BaselineAdminNetworkPolicy %s, %s", name, rule(libovsdbops.GressIdxKey)), ", ")
+	case libovsdbops.EgressFirewallOwnerType:
+		// There can only be one EgressFirewall per namespace, named "default".
+		direction = "egress"
+		return strings.TrimSpace(fmt.Sprintf("EgressFirewall %s/default %s", name, rule(libovsdbops.RuleIndex)))
+	case libovsdbops.NetpolNamespaceOwnerType:
+		return fmt.Sprintf("NetworkPolicy isolation (%s) of namespace %s, direction %s", aclHit.ExternalIDs[libovsdbops.TypeKey.String()], name, direction)
+	case libovsdbops.NetpolDefaultOwnerType:
+		return fmt.Sprintf("default NetworkPolicy ACL %s, direction %s", name, direction)
+	case libovsdbops.NetpolNodeOwnerType:
+		return "default allow from local node " + name
+	case libovsdbops.MulticastNamespaceOwnerType:
+		return fmt.Sprintf("multicast policy of namespace %s, direction %s", name, direction)
+	case libovsdbops.MulticastClusterOwnerType:
+		return fmt.Sprintf("cluster multicast policy (%s), direction %s", aclHit.ExternalIDs[libovsdbops.TypeKey.String()], direction)
+	case libovsdbops.UDNIsolationOwnerType:
+		return "UDN isolation of type " + name
+	case libovsdbops.AdvertisedNetworkOwnerType:
+		return "advertised network isolation " + name
+	case libovsdbops.ClusterNetworkConnectOwnerType:
+		return fmt.Sprintf("ClusterNetworkConnect %s (%s)", name, aclHit.ExternalIDs[libovsdbops.TypeKey.String()])
+	}
+	if name != "" {
+		return ownerType + " " + name
+	}
+	return ownerType
+}
+
+// describeACLAction returns the verdict that the given ACL action stands for.
+func describeACLAction(action string) string {
+	switch action {
+	case nbdb.ACLActionAllow, nbdb.ACLActionAllowRelated, nbdb.ACLActionAllowStateless:
+		return "Allowed"
+	case nbdb.ACLActionDrop:
+		return "Dropped"
+	case nbdb.ACLActionReject:
+		return "Rejected"
+	case nbdb.ACLActionPass:
+		return "Passed to the next tier"
+	}
+	return "Action " + action
+}
+
+// isDenyACLAction returns true if the given ACL action drops traffic.
+func isDenyACLAction(action string) bool {
+	return action == nbdb.ACLActionDrop || action == nbdb.ACLActionReject
+}
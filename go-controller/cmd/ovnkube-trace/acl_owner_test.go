@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	anpcontroller "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/ovn/controller/admin_network_policy"
+)
+
+// aclExternalIDs returns the external IDs of an ACL owned by the given object type and name, with the given extra IDs.
+func aclExternalIDs(ownerType, name string, ids map[libovsdbops.ExternalIDKey]string) map[string]string {
+	externalIDs := map[string]string{
+		libovsdbops.OwnerControllerKey.String(): "default-network-controller",
+		libovsdbops.OwnerTypeKey.String():       ownerType,
+		libovsdbops.ObjectNameKey.String():      name,
+	}
+	for key, value := range ids {
+		externalIDs[key.String()] = value
+	}
+	return externalIDs
+}
+
+func TestDescribeACLOwner(t *testing.T) {
+	tests := []struct {
+		name     string
+		aclHit   ACLHit
+		expected string
+	}{
+		{
+			name:     "ACL not created by ovn-kubernetes",
+			aclHit:   ACLHit{ExternalIDs: map[string]string{"foo": "bar"}},
+			expected: "",
+		},
+		{
+			name: "NetworkPolicy rule",
+			aclHit: ACLHit{ExternalIDs: aclExternalIDs(libovsdbops.NetworkPolicyOwnerType, libovsdbops.BuildNamespaceNameKey("ns", "foo"),
+				map[libovsdbops.ExternalIDKey]string{
+					libovsdbops.PolicyDirectionKey: "Ingress",
+					libovsdbops.GressIdxKey:        "2",
+				})},
+			expected: "NetworkPolicy ns/foo rule ingress[2]",
+		},
+		{
+			name:     "NetworkPolicy with an unparsable name",
+			aclHit:   ACLHit{ExternalIDs: aclExternalIDs(libovsdbops.NetworkPolicyOwnerType, "foo", nil)},
+			expected: "NetworkPolicy foo",
+		},
+		{
+			name: "NetworkPolicy port index",
+			aclHit: ACLHit{ExternalIDs: aclExternalIDs(libovsdbops.NetworkPolicyPortIndexOwnerType, libovsdbops.BuildNamespaceNameKey("ns", "foo"),
+				map[libovsdbops.ExternalIDKey]string{
+					libovsdbops.PolicyDirectionKey: "Egress",
+				})},
+			expected: "NetworkPolicy ns/foo direction egress",
+		},
+		{
+			name: "AdminNetworkPolicy first rule",
+			aclHit: ACLHit{
+				Priority: int(anpcontroller.ANPFlowStartPriority - 5*anpcontroller.ANPMaxRulesPerObject),
+				ExternalIDs: aclExternalIDs(libovsdbops.AdminNetworkPolicyOwnerType, "allow-monitoring",
+					map[libovsdbops.ExternalIDKey]string{
+						libovsdbops.PolicyDirectionKey: "Ingress",
+						libovsdbops.GressIdxKey:        "0",
+					}),
+			},
+			expected: "AdminNetworkPolicy allow-monitoring, priority 5, rule ingress[0]",
+		},
+		{
+			name: "AdminNetworkPolicy last rule",
+			aclHit: ACLHit{
+				Priority: int(anpcontroller.ANPFlowStartPriority - 5*anpcontroller.ANPMaxRulesPerObject - (anpcontroller.ANPMaxRulesPerObject - 1)),
+				ExternalIDs: aclExternalIDs(libovsdbops.AdminNetworkPolicyOwnerType, "allow-monitoring",
+					map[libovsdbops.ExternalIDKey]string{
+						libovsdbops.PolicyDirectionKey: "Egress",
+						libovsdbops.GressIdxKey:        "99",
+					}),
+			},
+			expected: "AdminNetworkPolicy allow-monitoring, priority 5, rule egress[99]",
+		},
+		{
+			name: "AdminNetworkPolicy with priority 0",
+			aclHit: ACLHit{
+				Priority: int(anpcontroller.ANPFlowStartPriority - 3),
+				ExternalIDs: aclExternalIDs(libovsdbops.AdminNetworkPolicyOwnerType, "deny-all",
+					map[libovsdbops.ExternalIDKey]string{
+						libovsdbops.PolicyDirectionKey: "Egress",
+						libovsdbops.GressIdxKey:        "3",
+					}),
+			},
+			expected: "AdminNetworkPolicy deny-all, priority 0, rule egress[3]",
+		},
+		{
+			name: "AdminNetworkPolicy without rule",
+			aclHit: ACLHit{
+				Priority:    int(anpcontroller.ANPFlowStartPriority - 10*anpcontroller.ANPMaxRulesPerObject),
+				ExternalIDs: aclExternalIDs(libovsdbops.AdminNetworkPolicyOwnerType, "deny-all", nil),
+			},
+			expected: "AdminNetworkPolicy deny-all, priority 10",
+		},
+		{
+			name: "BaselineAdminNetworkPolicy rule",
+			aclHit: ACLHit{
+				Priority: int(anpcontroller.BANPFlowPriority - 4),
+				ExternalIDs: aclExternalIDs(libovsdbops.BaselineAdminNetworkPolicyOwnerType, "default",
+					map[libovsdbops.ExternalIDKey]string{
+						libovsdbops.PolicyDirectionKey: "Ingress",
+						libovsdbops.GressIdxKey:        "4",
+					}),
+			},
+			expected: "BaselineAdminNetworkPolicy default, rule ingress[4]",
+		},
+		{
+			name: "EgressFirewall rule",
+			aclHit: ACLHit{ExternalIDs: aclExternalIDs(libovsdbops.EgressFirewallOwnerType, "ns",
+				map[libovsdbops.ExternalIDKey]string{
+					libovsdbops.RuleIndex: "1",
+				})},
+			expected: "EgressFirewall ns/default rule egress[1]",
+		},
+		{
+			name: "NetworkPolicy namespace isolation",
+			aclHit: ACLHit{ExternalIDs: aclExternalIDs(libovsdbops.NetpolNamespaceOwnerType, "ns",
+				map[libovsdbops.ExternalIDKey]string{
+					libovsdbops.PolicyDirectionKey: "Egress",
+					libovsdbops.TypeKey:            "defaultDeny",
+				})},
+			expected: "NetworkPolicy isolation (defaultDeny) of namespace ns, direction egress",
+		},
+		{
+			name: "multicast namespace policy",
+			aclHit: ACLHit{ExternalIDs: aclExternalIDs(libovsdbops.MulticastNamespaceOwnerType, "ns",
+				map[libovsdbops.ExternalIDKey]string{
+					libovsdbops.PolicyDirectionKey: "Ingress",
+				})},
+			expected: "multicast policy of namespace ns, direction ingress",
+		},
+		{
+			name:     "unknown owner type",
+			aclHit:   ACLHit{ExternalIDs: aclExternalIDs("FutureOwner", "foo", nil)},
+			expected: "FutureOwner foo",
+		},
+		{
+			name:     "unknown owner type without name",
+			aclHit:   ACLHit{ExternalIDs: aclExternalIDs("FutureOwner", "", nil)},
+			expected: "FutureOwner",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeACLOwner(&tt.aclHit); got != tt.expected {
+				t.Errorf("describeACLOwner() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetDropReasons(t *testing.T) {
+	allowACL := ACLHit{Action: nbdb.ACLActionAllowRelated, Owner: "NetworkPolicy ns/allow rule ingress[0]"}
+	dropACL := ACLHit{Action: nbdb.ACLActionDrop, Owner: "NetworkPolicy isolation (defaultDeny) of namespace ns, direction ingress"}
+	rejectACL := ACLHit{Action: nbdb.ACLActionReject, Owner: "AdminNetworkPolicy deny-all, priority 0, rule ingress[3]"}
+	unknownDropACL := ACLHit{Action: nbdb.ACLActionDrop}
+
+	tests := []struct {
+		name     string
+		hops     []DatapathHop
+		expected []string
+	}{
+		{
+			name: "forwarded hops have no drop reason",
+			hops: []DatapathHop{
+				{Verdict: hopVerdictForward, ACLs: []ACLHit{dropACL}},
+				{Verdict: hopVerdictOutput, ACLs: []ACLHit{allowACL}},
+			},
+			expected: []string{"", ""},
+		},
+		{
+			name:     "drop is attributed to the last deny ACL",
+			hops:     []DatapathHop{{Verdict: hopVerdictDrop, ACLs: []ACLHit{dropACL, rejectACL, allowACL}}},
+			expected: []string{"Rejected by AdminNetworkPolicy deny-all, priority 0, rule ingress[3]"},
+		},
+		{
+			name:     "deny ACLs without owner are skipped",
+			hops:     []DatapathHop{{Verdict: hopVerdictDrop, ACLs: []ACLHit{dropACL, unknownDropACL}}},
+			expected: []string{"Dropped by NetworkPolicy isolation (defaultDeny) of namespace ns, direction ingress"},
+		},
+		{
+			name:     "drop without deny ACL",
+			hops:     []DatapathHop{{Verdict: hopVerdictDrop, ACLs: []ACLHit{allowACL}}},
+			expected: []string{""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDropReasons(tt.hops)
+			reasons := make([]string, 0, len(tt.hops))
+			for _, hop := range tt.hops {
+				reasons = append(reasons, hop.Reason)
+			}
+			if !reflect.DeepEqual(reasons, tt.expected) {
+				t.Errorf("setDropReasons() reasons = %q, expected %q", reasons, tt.expected)
+			}
+		})
+	}
+}
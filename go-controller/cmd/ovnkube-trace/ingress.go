@@ -0,0 +1,477 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
+
+	types "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
+	util "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+)
+
+const (
+	// Kinds of service ingress that can be traced from outside the cluster.
+	ingressKindNodePort     = "NodePort"
+	ingressKindLoadBalancer = "LoadBalancer"
+	ingressKindExternalIP   = "ExternalIP"
+
+	// Default external client addresses (RFC 5737 / RFC 3849 documentation ranges) used as the
+	// source of ingress traces when no -src-ip is provided.
+	defaultExternalClientIPv4 = "192.0.2.10"
+	defaultExternalClientIPv6 = "2001:db8::10"
+)
+
+var ovnTraceSNATRe = regexp.MustCompile(`ct_snat\(([^)\s]+)\)`)
+
+// IngressInfo contains information about traffic entering the cluster from an external client
+// through a node's external gateway.
+type IngressInfo struct {
+	*PodInfo               // Describes the external client as seen on the ingress node
+	Kind            string // NodePort, LoadBalancer or ExternalIP
+	IngressIP       string // The destination IP of the traffic as sent by the external client
+	IngressPort     string // The destination port of the traffic as sent by the external client
+	SvcPort         string // The service port that IngressPort maps to
+	GatewayPortName string // The localnet port of the node's external switch, e.g. breth0_ovn-worker
+	GatewayMAC      string // The MAC address of the node's gateway router external port
+	PhysPortName    string // The name of the physical interface attached to the external bridge
+	PhysOfportNum   string // ofport number of the physical interface on the external bridge
+}
+
+// String returns a JSON representation of the IngressInfo object, or "" on failure.
+func (ii *IngressInfo) String() string {
+	b, err := json.Marshal(*ii)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// getIngressInfo returns a pointer to a populated IngressInfo struct describing traffic from
+// clientIP that enters the cluster through node ingressNodeName towards service dstSvcInfo.
+func getIngressInfo(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, ingressNodeName, clientIP string, dstSvcInfo *SvcInfo,
+	ovnNamespace, addressFamily, dstIP, dstPort string) (*IngressInfo, error) {
+	node, err := coreclient.Nodes().Get(context.TODO(), ingressNodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("ingress node %s not found, err: %v", ingressNodeName, err)
+	}
+	l3GwConfig, err := util.ParseNodeL3GatewayAnnotation(node)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse gateway config of node %s: %w", ingressNodeName, err)
+	}
+	if l3GwConfig.MACAddress == nil || l3GwConfig.InterfaceID == "" {
+		return nil, fmt.Errorf("node %s does not have an external gateway configured", ingressNodeName)
+	}
+
+	if clientIP == "" {
+		clientIP = defaultExternalClientIPv4
+		if addressFamily == ip6 {
+			clientIP = defaultExternalClientIPv6
+		}
+	}
+	parsedClientIP := utilnet.ParseIPSloppy(clientIP)
+	if parsedClientIP == nil {
+		return nil, fmt.Errorf("cannot parse external client IP address %q", clientIP)
+	}
+	if getIPVer(parsedClientIP) != addressFamily {
+		return nil, fmt.Errorf("external client IP address %s does not match address family %s", clientIP, addressFamily)
+	}
+
+	ingressInfo := &IngressInfo{
+		PodInfo: &PodInfo{
+			IP:          parsedClientIP.String(),
+			IPVer:       addressFamily,
+			PodName:     "external client " + parsedClientIP.String(),
+			HostNetwork: true,
+		},
+		GatewayPortName: l3GwConfig.InterfaceID,
+		GatewayMAC:      l3GwConfig.MACAddress.String(),
+	}
+	clientInfo := ingressInfo.PodInfo
+	clientInfo.NodeName = ingressNodeName
+	clientInfo.K8sNodeNamePort = types.K8sPrefix + ingressNodeName
+	clientInfo.RoutingViaHost = l3GwConfig.Mode == "local"
+
+	clientInfo.OvnKubePodName, err = getOvnKubePodOnNode(coreclient, ovnNamespace, ingressNodeName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = getDatabaseURIs(coreclient, restconfig, ovnNamespace, clientInfo); err != nil {
+		return nil, fmt.Errorf("failed to get database URIs: %w", err)
+	}
+	clientInfo.RtosMAC, err = getRouterPortMacAddress(coreclient, restconfig, clientInfo, ovnNamespace, types.RouterToSwitchPrefix)
+	if err != nil {
+		return nil, err
+	}
+	clientInfo.RtotsMAC, err = getRouterPortMacAddress(coreclient, restconfig, clientInfo, ovnNamespace, types.RouterToTransitSwitchPrefix)
+	if err != nil {
+		return nil, err
+	}
+	clientInfo.NodeExternalBridgeName, err = getNodeExternalBridgeName(coreclient, restconfig, ovnNamespace, clientInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if clientInfo.RoutingViaHost {
+		// In routingViaHost gateway mode, ingress traffic is handed to the host and enters OVN through the
+		// management port, so the trace starts there with the management port's MAC address.
+		clientInfo.OvnK8sMp0PortName = types.K8sMgmtIntfName
+		portCmd := fmt.Sprintf("ovs-vsctl get Interface %s mac_in_use", clientInfo.OvnK8sMp0PortName)
+		localOutput, localError, err := execInPod(coreclient, restconfig, ovnNamespace, clientInfo.OvnKubePodName, clientInfo.OvnKubeContainerName, portCmd, "")
+		if err != nil {
+			return nil, fmt.Errorf("execInPod() failed. err: %s, stderr: %s, stdout: %s, ingressInfo: %v", err, localError, localOutput, clientInfo)
+		}
+		clientInfo.MAC = strings.ReplaceAll(strings.TrimSpace(localOutput), "\"", "")
+	} else {
+		// In routingViaOVN gateway mode, traffic from the external client reaches the gateway router from
+		// the external next hop, so the trace starts with the next hop's MAC address.
+		clientInfo.MAC, err = getNextHopMAC(coreclient, restconfig, ovnNamespace, clientInfo, l3GwConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ingressInfo.PhysPortName, ingressInfo.PhysOfportNum, err = getBridgePhysicalPort(coreclient, restconfig, ovnNamespace, clientInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setServiceIngressTarget(coreclient, node, l3GwConfig, ingressInfo, dstSvcInfo, dstIP, dstPort); err != nil {
+		return nil, err
+	}
+	klog.V(5).Infof("==> Traffic enters node %s via %s %s:%s (service port %s)", ingressNodeName,
+		ingressInfo.Kind, ingressInfo.IngressIP, ingressInfo.IngressPort, ingressInfo.SvcPort)
+
+	return ingressInfo, nil
+}
+
+// setServiceIngressTarget determines how the external client reaches the service: either via one of the service's
+// load balancer ingress IPs, one of its external IPs, or via the NodePort of the ingress node.
+// dstPort is the service port for load balancer ingress IPs and external IPs, and either the NodePort or the
+// service port for NodePort access.
+func setServiceIngressTarget(coreclient *corev1client.CoreV1Client, node *corev1.Node, l3GwConfig *util.L3GatewayConfig,
+	ingressInfo *IngressInfo, dstSvcInfo *SvcInfo, dstIP, dstPort string) error {
+	svc, err := coreclient.Services(dstSvcInfo.SvcNamespace).Get(context.TODO(), dstSvcInfo.SvcName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("service %s in namespace %s not found, err: %v", dstSvcInfo.SvcName, dstSvcInfo.SvcNamespace, err)
+	}
+	port, err := strconv.Atoi(dstPort)
+	if err != nil {
+		return fmt.Errorf("invalid destination port %q: %w", dstPort, err)
+	}
+
+	if dstIP == "" {
+		dstIP, err = getNodeIngressIP(node, l3GwConfig, ingressInfo.IPVer)
+		if err != nil {
+			return err
+		}
+	}
+	parsedDstIP := utilnet.ParseIPSloppy(dstIP)
+	if parsedDstIP == nil {
+		return fmt.Errorf("cannot parse ingress IP address %q", dstIP)
+	}
+	if getIPVer(parsedDstIP) != ingressInfo.IPVer {
+		return fmt.Errorf("ingress IP address %s does not match address family %s", dstIP, ingressInfo.IPVer)
+	}
+	ingressInfo.IngressIP = parsedDstIP.String()
+
+	isLBIngressIP := slices.ContainsFunc(svc.Status.LoadBalancer.Ingress, func(ing corev1.LoadBalancerIngress) bool {
+		return ing.IP != "" && utilnet.ParseIPSloppy(ing.IP).Equal(parsedDstIP)
+	})
+	isExternalIP := slices.ContainsFunc(svc.Spec.ExternalIPs, func(ip string) bool {
+		return utilnet.ParseIPSloppy(ip).Equal(parsedDstIP)
+	})
+	switch {
+	case isLBIngressIP, isExternalIP:
+		ingressInfo.Kind = ingressKindExternalIP
+		if isLBIngressIP {
+			ingressInfo.Kind = ingressKindLoadBalancer
+		}
+		for _, svcPort := range svc.Spec.Ports {
+			if int(svcPort.Port) == port {
+				ingressInfo.IngressPort = dstPort
+				ingressInfo.SvcPort = dstPort
+				return nil
+			}
+		}
+		return fmt.Errorf("service %s in namespace %s does not expose port %d on %s %s",
+			svc.Name, svc.Namespace, port, ingressInfo.Kind, ingressInfo.IngressIP)
+	case isNodeIngressIP(node, l3GwConfig, parsedDstIP):
+		ingressInfo.Kind = ingressKindNodePort
+		for _, svcPort := range svc.Spec.Ports {
+			if svcPort.NodePort > 0 && (int(svcPort.NodePort) == port || int(svcPort.Port) == port) {
+				ingressInfo.IngressPort = strconv.Itoa(int(svcPort.NodePort))
+				ingressInfo.SvcPort = strconv.Itoa(int(svcPort.Port))
+				return nil
+			}
+		}
+		return fmt.Errorf("service %s in namespace %s does not have a NodePort matching port %d", svc.Name, svc.Namespace, port)
+	}
+	return fmt.Errorf("IP address %s is neither a load balancer ingress IP or external IP of service %s in namespace %s "+
+		"nor an IP address of node %s", ingressInfo.IngressIP, svc.Name, svc.Namespace, node.Name)
+}
+
+// getNodeIngressIP returns the node's gateway IP address of the desired address family.
+func getNodeIngressIP(node *corev1.Node, l3GwConfig *util.L3GatewayConfig, addressFamily string) (string, error) {
+	for _, ipNet := range l3GwConfig.IPAddresses {
+		if getIPVer(ipNet.IP) == addressFamily {
+			return ipNet.IP.String(), nil
+		}
+	}
+	return "", fmt.Errorf("could not find a gateway IP address of family %s on node %s", addressFamily, node.Name)
+}
+
+// isNodeIngressIP returns true if the given IP is one of the node's gateway or internal addresses.
+func isNodeIngressIP(node *corev1.Node, l3GwConfig *util.L3GatewayConfig, ip net.IP) bool {
+	for _, ipNet := range l3GwConfig.IPAddresses {
+		if ipNet.IP.Equal(ip) {
+			return true
+		}
+	}
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP && utilnet.ParseIPSloppy(address.Address).Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// getNextHopMAC returns the MAC address of the external next hop of the node's gateway router for the address
+// family of podInfo, as learnt by the gateway router or, if it wasn't learnt yet, by the host.
+func getNextHopMAC(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, ovnNamespace string, podInfo *PodInfo,
+	l3GwConfig *util.L3GatewayConfig) (string, error) {
+	var nextHop net.IP
+	for _, ip := range l3GwConfig.NextHops {
+		if getIPVer(ip) == podInfo.IPVer {
+			nextHop = ip
+			break
+		}
+	}
+	if nextHop == nil {
+		return "", fmt.Errorf("could not find a gateway next hop of family %s on node %s", podInfo.IPVer, podInfo.NodeName)
+	}
+
+	cmd := fmt.Sprintf(`ovn-sbctl --no-leader-only %s --bare --no-heading --column=mac find MAC_Binding logical_port=%s ip="%s"`,
+		podInfo.SbCommand, types.GWRouterToExtSwitchPrefix+types.GWRouterPrefix+podInfo.NodeName, nextHop)
+	stdout, stderr, err := execInPod(coreclient, restconfig, ovnNamespace, podInfo.OvnKubePodName, podInfo.OvnKubeContainerName, cmd, "")
+	if err != nil {
+		return "", fmt.Errorf("execInPod() failed with %s stderr %s stdout %s", err, stderr, stdout)
+	}
+	if mac := parseNextHopMAC(stdout); mac != "" {
+		return mac, nil
+	}
+
+	cmd = "ip neigh show " + nextHop.String()
+	stdout, stderr, err = execInPod(coreclient, restconfig, ovnNamespace, podInfo.OvnKubePodName, podInfo.OvnKubeContainerName, cmd, "")
+	if err != nil {
+		return "", fmt.Errorf("execInPod() failed with %s stderr %s stdout %s", err, stderr, stdout)
+	}
+	if mac := parseNextHopMAC(stdout); mac != "" {
+		return mac, nil
+	}
+	return "", fmt.Errorf("could not find the MAC address of next hop %s of node %s", nextHop, podInfo.NodeName)
+}
+
+// parseNextHopMAC returns the first MAC address in the ovn-sbctl MAC_Binding or "ip neigh" output, or "" if there is none.
+func parseNextHopMAC(output string) string {
+	for _, field := range strings.Fields(output) {
+		if mac, err := net.ParseMAC(strings.Trim(field, "\"")); err == nil && len(mac) == 6 {
+			return mac.String()
+		}
+	}
+	return ""
+}
+
+// getBridgePhysicalPort returns the name and the ofport number of the physical interface that is attached to the
+// node's external bridge.
+func getBridgePhysicalPort(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, ovnNamespace string, podInfo *PodInfo) (string, string, error) {
+	cmd := "ovs-vsctl list-ports " + podInfo.NodeExternalBridgeName
+	stdout, stderr, err := execInPod(coreclient, restconfig, ovnNamespace, podInfo.OvnKubePodName, podInfo.OvnKubeContainerName, cmd, "")
+	if err != nil {
+		return "", "", fmt.Errorf("execInPod() failed with %s stderr %s stdout %s", err, stderr, stdout)
+	}
+	var physPortName string
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		port := strings.TrimSpace(scanner.Text())
+		if port != "" && !strings.HasPrefix(port, "patch-") {
+			physPortName = port
+			break
+		}
+	}
+	if physPortName == "" {
+		return "", "", fmt.Errorf("could not find physical port of bridge %s on node %s", podInfo.NodeExternalBridgeName, podInfo.NodeName)
+	}
+
+	cmd = fmt.Sprintf("ovs-vsctl get Interface %s ofport", physPortName)
+	stdout, stderr, err = execInPod(coreclient, restconfig, ovnNamespace, podInfo.OvnKubePodName, podInfo.OvnKubeContainerName, cmd, "")
+	if err != nil {
+		return "", "", fmt.Errorf("execInPod() failed with %s stderr %s stdout %s", err, stderr, stdout)
+	}
+	return physPortName, strings.TrimSpace(stdout), nil
+}
+
+// runOvnTraceIngressToService runs an ovn-trace for traffic from an external client to the service. For routingViaOVN
+// gateway mode the trace starts at the localnet port of the node's external switch, for routingViaHost gateway mode the
+// host forwards the traffic to the service's ClusterIP through the management port, so the trace starts there.
+func runOvnTraceIngressToService(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, ingressInfo *IngressInfo, dstSvcInfo *SvcInfo,
+	ovnNamespace, protocol string) {
+	clientInfo := ingressInfo.PodInfo
+	var cmd string
+	if clientInfo.RoutingViaHost {
+		cmd = fmt.Sprintf(`ovn-trace --no-leader-only %[1]s %[2]s --ct=new `+
+			`'inport=="%[3]s" && eth.src==%[4]s && eth.dst==%[5]s && %[6]s.src==%[7]s && %[6]s.dst==%[8]s && ip.ttl==64 && %[9]s.dst==%[10]s && %[9]s.src==52888' --lb-dst %[11]s:%[12]s`,
+			clientInfo.SbCommand,       // 1
+			clientInfo.NodeName,        // 2
+			clientInfo.K8sNodeNamePort, // 3
+			clientInfo.MAC,             // 4
+			clientInfo.RtosMAC,         // 5
+			clientInfo.IPVer,           // 6
+			clientInfo.IP,              // 7
+			dstSvcInfo.ClusterIP,       // 8
+			protocol,                   // 9
+			ingressInfo.SvcPort,        // 10
+			dstSvcInfo.PodInfo.IP,      // 11
+			dstSvcInfo.PodPort,         // 12
+		)
+	} else {
+		cmd = fmt.Sprintf(`ovn-trace --no-leader-only %[1]s %[2]s --ct=new `+
+			`'inport=="%[3]s" && eth.src==%[4]s && eth.dst==%[5]s && %[6]s.src==%[7]s && %[6]s.dst==%[8]s && ip.ttl==64 && %[9]s.dst==%[10]s && %[9]s.src==52888' --lb-dst %[11]s:%[12]s`,
+			clientInfo.SbCommand,                           // 1
+			types.ExternalSwitchPrefix+clientInfo.NodeName, // 2
+			ingressInfo.GatewayPortName,                    // 3
+			clientInfo.MAC,                                 // 4
+			ingressInfo.GatewayMAC,                         // 5
+			clientInfo.IPVer,                               // 6
+			clientInfo.IP,                                  // 7
+			ingressInfo.IngressIP,                          // 8
+			protocol,                                       // 9
+			ingressInfo.IngressPort,                        // 10
+			dstSvcInfo.PodInfo.IP,                          // 11
+			dstSvcInfo.PodPort,                             // 12
+		)
+	}
+	klog.V(4).Infof("ovn-trace command from external client to service %s is %s", ingressInfo.Kind, cmd)
+
+	ovnSrcDstOut, ovnSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, clientInfo.OvnKubePodName, clientInfo.OvnKubeContainerName, cmd, "")
+	var successString string
+	if podsInSameInterconnectZone(clientInfo, dstSvcInfo.PodInfo) {
+		successString = fmt.Sprintf(`output to "%s"`, dstSvcInfo.FullyQualifiedPodName())
+	} else {
+		successString = fmt.Sprintf(`output to "tstor-%s"`, dstSvcInfo.PodInfo.NodeName)
+	}
+	direction := fmt.Sprintf("external client to service %s", ingressInfo.Kind)
+	printSuccessOrFailure(clientInfo, "ovn-trace "+direction, clientInfo.PodName, dstSvcInfo.SvcName, ovnSrcDstOut, ovnSrcDstErr, err, successString)
+
+	// Traffic towards the backend may have been SNATed by the gateway router, in which case the remote zone sees
+	// the SNAT address as source.
+	if snatIP := getSNATFromTrace(ovnSrcDstOut); snatIP != "" {
+		klog.V(1).Infof("%singress traffic is SNATed to %s on node %s%s\n", green, snatIP, clientInfo.NodeName, reset)
+		snatClientInfo := *clientInfo
+		snatClientInfo.IP = snatIP
+		clientInfo = &snatClientInfo
+	}
+	runOvnTraceToRemotePod(coreclient, restconfig, direction, clientInfo, dstSvcInfo.PodInfo, ovnNamespace, protocol, dstSvcInfo.PodPort)
+}
+
+// getSNATFromTrace returns the last SNAT address found in ovn-trace output, or "" if there is none.
+func getSNATFromTrace(ovnTraceOut string) string {
+	subMatches := ovnTraceSNATRe.FindAllStringSubmatch(ovnTraceOut, -1)
+	if len(subMatches) == 0 {
+		return ""
+	}
+	return subMatches[len(subMatches)-1][1]
+}
+
+// runOfprotoTraceIngressToService runs an ofproto/trace command on the node's external bridge for traffic from an external
+// client arriving on the physical interface. The flows programmed for NodePort, LoadBalancer and ExternalIP services
+// either send the traffic into OVN through the patch port or to the host.
+func runOfprotoTraceIngressToService(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, ingressInfo *IngressInfo, dstSvcInfo *SvcInfo,
+	ovnNamespace, protocol string) string {
+	clientInfo := ingressInfo.PodInfo
+	protocolSelector, nwSrc, nwDst := getOfprotoIPFamilyArgs(protocol, net.ParseIP(ingressInfo.IngressIP))
+	cmd := fmt.Sprintf(`ovs-appctl ofproto/trace %[1]s `+
+		`"in_port=%[2]s, %[3]s, dl_dst=%[4]s, %[5]s=%[6]s, %[7]s=%[8]s, nw_ttl=64, %[9]s_dst=%[10]s, %[9]s_src=12345"`,
+		clientInfo.NodeExternalBridgeName, // 1
+		ingressInfo.PhysOfportNum,         // 2
+		protocolSelector,                  // 3
+		ingressInfo.GatewayMAC,            // 4
+		nwSrc,                             // 5
+		clientInfo.IP,                     // 6
+		nwDst,                             // 7
+		ingressInfo.IngressIP,             // 8
+		protocol,                          // 9
+		ingressInfo.IngressPort,           // 10
+	)
+	direction := fmt.Sprintf("external client to service %s", ingressInfo.Kind)
+	klog.V(4).Infof("ovs-appctl ofproto/trace command from %s is %s", direction, cmd)
+
+	// Traffic is either handed over to OVN through the patch port to br-int, or to the host for
+	// routingViaHost gateway mode and for ETP=local services with host networked endpoints.
+	successString := `bridge\("br-int"\)|output:LOCAL`
+	appSrcDstOut, appSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, clientInfo.OvnKubePodName, clientInfo.OvnKubeContainerName, cmd, "")
+	printSuccessOrFailure(clientInfo, "ovs-appctl ofproto/trace "+direction, clientInfo.PodName, dstSvcInfo.SvcName, appSrcDstOut, appSrcDstErr, err, successString)
+
+	return appSrcDstOut
+}
+
+// resolveDstHost resolves the given external host name and returns its first address of the desired address family.
+func resolveDstHost(host, addressFamily string) (net.IP, error) {
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve host %s: %w", host, err)
+	}
+	for _, ip := range ips {
+		if getIPVer(ip) == addressFamily {
+			klog.V(5).Infof("Resolved host %s to %s", host, ip)
+			return ip, nil
+		}
+	}
+	return nil, fmt.Errorf("host %s does not resolve to an address of family %s", host, addressFamily)
+}
+
+// runIngressTrace runs the ovn-trace and ofproto/trace commands for traffic from an external client that enters the
+// cluster through the given node towards a NodePort, LoadBalancer ingress IP or ExternalIP of the destination service.
+func runIngressTrace(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, ingressNodeName, srcIP, dstSvcName,
+	ovnNamespace, dstNamespace, addressFamily, dstIP, dstPort, protocol, loglevel string) {
+	if lvl, err := strconv.Atoi(loglevel); err == nil && lvl >= 5 {
+		displayNodeInfo(coreclient)
+	}
+
+	dstSvcInfo, err := getSvcInfo(coreclient, restconfig, dstSvcName, ovnNamespace, dstNamespace, addressFamily)
+	if err != nil {
+		klog.Exitf("Failed to get information from service %s: %v", dstSvcName, err)
+	}
+	klog.V(5).Infof("dstSvcInfo is %s\n", dstSvcInfo)
+	klog.V(1).Infof("Using pod %s in service %s to test against", dstSvcInfo.PodInfo.PodName, dstSvcName)
+
+	ingressInfo, err := getIngressInfo(coreclient, restconfig, ingressNodeName, srcIP, dstSvcInfo, ovnNamespace, addressFamily, dstIP, dstPort)
+	if err != nil {
+		klog.Exitf("Failed to get ingress information for node %s: %v", ingressNodeName, err)
+	}
+	klog.V(5).Infof("ingressInfo is %s\n", ingressInfo)
+	setSource(&TraceEndpoint{Kind: endpointKindExternalClient, Node: ingressNodeName, IP: ingressInfo.IP})
+	dstEndpoint := serviceEndpoint(dstSvcInfo, ingressInfo.SvcPort)
+	dstEndpoint.IP = ingressInfo.IngressIP
+	dstEndpoint.Port = ingressInfo.IngressPort
+	dstEndpoint.Via = ingressInfo.Kind
+	setDestination(dstEndpoint)
+
+	runOfprotoTraceIngressToService(coreclient, restconfig, ingressInfo, dstSvcInfo, ovnNamespace, protocol)
+	runOvnTraceIngressToService(coreclient, restconfig, ingressInfo, dstSvcInfo, ovnNamespace, protocol)
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+)
+
+func TestGetSNATFromTrace(t *testing.T) {
+	tests := []struct {
+		name     string
+		traceOut string
+		expected string
+	}{
+		{
+			name:     "no SNAT",
+			traceOut: "ct_dnat(10.244.1.3:8080);\noutput;",
+			expected: "",
+		},
+		{
+			name:     "last SNAT is returned",
+			traceOut: "ct_snat(169.254.0.2);\nct_lb_mark(backends=10.244.1.3:8080);\nct_snat(100.64.0.2);",
+			expected: "100.64.0.2",
+		},
+		{
+			name:     "IPv6 SNAT",
+			traceOut: "    ct_snat(fd98::2);",
+			expected: "fd98::2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getSNATFromTrace(tt.traceOut); got != tt.expected {
+				t.Errorf("getSNATFromTrace() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseNextHopMAC(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "ovn-sbctl MAC_Binding output",
+			output:   "\"02:42:ac:12:00:01\"\n",
+			expected: "02:42:ac:12:00:01",
+		},
+		{
+			name:     "ip neigh output",
+			output:   "172.18.0.1 dev breth0 lladdr 02:42:ac:12:00:01 REACHABLE\n",
+			expected: "02:42:ac:12:00:01",
+		},
+		{
+			name:     "incomplete neighbor",
+			output:   "172.18.0.1 dev breth0 INCOMPLETE\n",
+			expected: "",
+		},
+		{
+			name:     "empty output",
+			output:   "",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNextHopMAC(tt.output); got != tt.expected {
+				t.Errorf("parseNextHopMAC() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -700,33 +700,49 @@ func getDatabaseURIs(coreclient *corev1client.CoreV1Client, restconfig *rest.Con
 }
 
 // printSuccessOrFailure will print a success or failure message. If searchString is set, then we expect to find a match for the
-// regexp given in searchString.
-func printSuccessOrFailure(commandDescription, src, dst, commandStdout, commandStderr string, err error, searchString string) {
+// regexp given in searchString. executor is the pod whose ovnkube pod ran the command.
+// With a structured output format, the result is recorded instead of being printed.
+func printSuccessOrFailure(executor *PodInfo, commandDescription, src, dst, commandStdout, commandStderr string, err error, searchString string) {
 	if err != nil {
 		klog.Exitf("%s error %v stdOut: %s\n stdErr: %s", commandDescription, err, commandStdout, commandStderr)
 	}
 	klog.V(2).Infof("%s Output:\n%s%s%s\n", commandDescription, italic, commandStdout, reset)
 
+	success := true
 	if searchString != "" {
 		match, err := regexp.MatchString(searchString, commandStdout)
 		if err != nil {
 			klog.Exitf("Unexpected failure matching regex '%s' to commandStdout '%s', err: %s", searchString, commandStdout, err)
 		}
+		success = match
 		if match {
-			// Write the result to stdout.
-			fmt.Printf("%s%s%s indicates success from %s to %s%s\n", green, bold, commandDescription, src, dst, reset)
 			// Log further info on log level 1.
 			klog.V(1).Infof("%sSearch string matched:\n%s%s\n", green, searchString, reset)
 		} else {
-			// Write the result to stdout.
-			fmt.Printf("%s%s%s indicates failure from %s to %s%s\n", red, bold, commandDescription, src, dst, reset)
 			// Log further info on log level 1.
 			klog.V(1).Infof("%sSearch string not matched:\n%s%s\n", red, searchString, reset)
-			os.Exit(-1)
 		}
-	} else {
+	}
+
+	if structuredOutput() {
+		reporter.recordStep(executor, commandDescription, src, dst, commandStdout, success)
+		if !success {
+			exitWithTraceResult()
+		}
+		return
+	}
+	if success {
 		// Write the result to stdout.
 		fmt.Printf("%s%s%s indicates success from %s to %s%s\n", green, bold, commandDescription, src, dst, reset)
+	} else {
+		// Write the result to stdout.
+		fmt.Printf("%s%s%s indicates failure from %s to %s%s\n", red, bold, commandDescription, src, dst, reset)
+		if reporter != nil && strings.HasPrefix(commandDescription, "ovn-trace") {
+			for _, reason := range reporter.dropReasons(executor, commandStdout) {
+				fmt.Printf("%s%s%s\n", red, reason, reset)
+			}
+		}
+		os.Exit(-1)
 	}
 }
 
@@ -768,7 +784,7 @@ func runOvnTraceToService(coreclient *corev1client.CoreV1Client, restconfig *res
 		successString = fmt.Sprintf(`output to "tstor-%s"`, dstSvcInfo.PodInfo.NodeName)
 	}
 	direction := "source pod to service clusterIP"
-	printSuccessOrFailure("ovn-trace "+direction, srcPodInfo.PodName, dstSvcInfo.SvcName, ovnSrcDstOut, ovnSrcDstErr, err, successString)
+	printSuccessOrFailure(srcPodInfo, "ovn-trace "+direction, srcPodInfo.PodName, dstSvcInfo.SvcName, ovnSrcDstOut, ovnSrcDstErr, err, successString)
 	runOvnTraceToRemotePod(coreclient, restconfig, direction, srcPodInfo, dstSvcInfo.PodInfo, ovnNamespace, protocol, dstPort)
 
 }
@@ -810,7 +826,7 @@ func runOvnTraceToIP(coreclient *corev1client.CoreV1Client, restconfig *rest.Con
 	successString := fmt.Sprintf(`output to "(.*)_(.*)", type "localnet"|output to "k8s-%s"|remote`, srcPodInfo.NodeName)
 	// Run the command and check if succesString was found.
 	ovnSrcDstOut, ovnSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubePodName, srcPodInfo.OvnKubeContainerName, cmd, "")
-	printSuccessOrFailure("ovn-trace from pod to IP", srcPodInfo.PodName, parsedDstIP.String(), ovnSrcDstOut, ovnSrcDstErr, err, successString)
+	printSuccessOrFailure(srcPodInfo, "ovn-trace from pod to IP", srcPodInfo.PodName, parsedDstIP.String(), ovnSrcDstOut, ovnSrcDstErr, err, successString)
 
 	// Print some additional information about the node where this request leaves from as well
 	// as the SNAT IP address.
@@ -894,7 +910,7 @@ func runOvnTraceToPod(coreclient *corev1client.CoreV1Client, restconfig *rest.Co
 		successString = fmt.Sprintf(`output to "tstor-%s"`, dstPodInfo.NodeName)
 	}
 	ovnSrcDstOut, ovnSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubePodName, srcPodInfo.OvnKubeContainerName, cmd, "")
-	printSuccessOrFailure("ovn-trace "+direction, srcPodInfo.PodName, dstPodInfo.PodName, ovnSrcDstOut, ovnSrcDstErr, err, successString)
+	printSuccessOrFailure(srcPodInfo, "ovn-trace "+direction, srcPodInfo.PodName, dstPodInfo.PodName, ovnSrcDstOut, ovnSrcDstErr, err, successString)
 	runOvnTraceToRemotePod(coreclient, restconfig, direction, srcPodInfo, dstPodInfo, ovnNamespace, protocol, dstPort)
 }
 
@@ -918,7 +934,7 @@ func runOvnTraceToRemotePod(coreclient *corev1client.CoreV1Client, restconfig *r
 	klog.V(4).Infof("ovn-trace command on destination pod node is %s", cmd)
 	successString := fmt.Sprintf(`output to "%s"`, dstPodInfo.FullyQualifiedPodName())
 	ovnSrcDstOut, ovnSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, dstPodInfo.OvnKubePodName, srcPodInfo.OvnKubeContainerName, cmd, "")
-	printSuccessOrFailure("ovn-trace (remote) "+direction, srcPodInfo.PodName, dstPodInfo.PodName, ovnSrcDstOut, ovnSrcDstErr, err, successString)
+	printSuccessOrFailure(dstPodInfo, "ovn-trace (remote) "+direction, srcPodInfo.PodName, dstPodInfo.PodName, ovnSrcDstOut, ovnSrcDstErr, err, successString)
 }
 
 func podsInSameInterconnectZone(srcPodInfo, dstPodInfo *PodInfo) bool {
@@ -965,7 +981,7 @@ func runOfprotoTraceToPod(coreclient *corev1client.CoreV1Client, restconfig *res
 		successString = "-> output to kernel tunnel"
 	}
 	appSrcDstOut, appSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubePodName, srcPodInfo.OvnKubeContainerName, cmd, "")
-	printSuccessOrFailure("ovs-appctl ofproto/trace "+direction, srcPodInfo.PodName, dstPodInfo.PodName, appSrcDstOut, appSrcDstErr, err, successString)
+	printSuccessOrFailure(srcPodInfo, "ovs-appctl ofproto/trace "+direction, srcPodInfo.PodName, dstPodInfo.PodName, appSrcDstOut, appSrcDstErr, err, successString)
 
 	return appSrcDstOut
 }
@@ -1008,7 +1024,7 @@ func runOfprotoTraceToIP(coreclient *corev1client.CoreV1Client, restconfig *rest
 		}
 	}
 	appSrcDstOut, appSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubePodName, srcPodInfo.OvnKubeContainerName, cmd, "")
-	printSuccessOrFailure(fmt.Sprintf("ovs-appctl ofproto/trace %s", direction), srcPodInfo.PodName, dstIP.String(), appSrcDstOut, appSrcDstErr, err, successString)
+	printSuccessOrFailure(srcPodInfo, fmt.Sprintf("ovs-appctl ofproto/trace %s", direction), srcPodInfo.PodName, dstIP.String(), appSrcDstOut, appSrcDstErr, err, successString)
 
 	return appSrcDstOut
 }
@@ -1093,7 +1109,7 @@ func runOvnDetrace(coreclient *corev1client.CoreV1Client, restconfig *rest.Confi
 	klog.V(4).Infof("ovn-detrace command from %s is %s", direction, cmd)
 
 	dtraceSrcDstOut, dtraceSrcDstErr, err := execInPod(coreclient, restconfig, ovnNamespace, srcPodInfo.OvnKubePodName, srcPodInfo.OvnKubeContainerName, cmd, appSrcDstOut)
-	printSuccessOrFailure("ovn-detrace "+direction, srcPodInfo.PodName, dstName, dtraceSrcDstOut, dtraceSrcDstErr, err, "")
+	printSuccessOrFailure(srcPodInfo, "ovn-detrace "+direction, srcPodInfo.PodName, dstName, dtraceSrcDstOut, dtraceSrcDstErr, err, "")
 
 	return nil
 }
@@ -1184,7 +1200,10 @@ func main() {
 	srcPodName := flag.String("src", "", "src: source pod name")
 	dstPodName := flag.String("dst", "", "dest: destination pod name")
 	dstSvcName := flag.String("service", "", "service: destination service name")
-	dstIP := flag.String("dst-ip", "", "destination IP address (meant for tests to external targets)")
+	dstIP := flag.String("dst-ip", "", "destination IP address (meant for tests to external targets, or the service ingress IP with -ingress-node)")
+	dstHost := flag.String("dst-host", "", "destination host name, resolved locally (meant for tests to external targets)")
+	ingressNodeName := flag.String("ingress-node", "", "trace from an external client entering the cluster via this node's gateway to -service (NodePort, LoadBalancer or ExternalIP)")
+	srcIP := flag.String("src-ip", "", "external client IP address used as source with -ingress-node")
 	dstPort := flag.String("dst-port", "80", "dst-port: destination port")
 	tcp := flag.Bool("tcp", false, "use tcp transport protocol")
 	udp := flag.Bool("udp", false, "use udp transport protocol")
@@ -1192,6 +1211,7 @@ func main() {
 	skipOvnDetrace := flag.Bool("skip-detrace", false, "skip ovn-detrace command")
 	dumpVRFTableIDs := flag.Bool("dump-udn-vrf-table-ids", false, "Dump the VRF table ID per node for all the user defined networks")
 	loglevel := flag.String("loglevel", "0", "loglevel: klog level")
+	output := flag.String("output", outputText, "output format: text, json or yaml")
 	flag.Parse()
 
 	// Set the application's log level.
@@ -1244,9 +1264,20 @@ func main() {
 		return
 	}
 
+	if err := setupReporter(coreclient, restconfig, ovnNamespace, *output); err != nil {
+		klog.Exitf("Usage: %v", err)
+	}
+	defer printTraceResult()
+
 	// Verify CLI flags.
-	if *srcPodName == "" {
-		klog.Exitf("Usage: source pod must be specified")
+	if *srcPodName == "" && *ingressNodeName == "" {
+		klog.Exitf("Usage: either source pod or ingress node must be specified")
+	}
+	if *srcPodName != "" && *ingressNodeName != "" {
+		klog.Exitf("Usage: source pod and ingress node cannot be specified at the same time")
+	}
+	if *srcIP != "" && *ingressNodeName == "" {
+		klog.Exitf("Usage: -src-ip can only be used together with -ingress-node")
 	}
 	if !*tcp && !*udp {
 		klog.Exitf("Usage: either tcp or udp must be specified")
@@ -1263,6 +1294,14 @@ func main() {
 		}
 		protocol = "udp"
 	}
+	if *ingressNodeName != "" {
+		if *dstSvcName == "" || *dstPodName != "" || *dstHost != "" {
+			klog.Exitf("Usage: -ingress-node requires -service and is not compatible with -dst or -dst-host")
+		}
+		runIngressTrace(coreclient, restconfig, *ingressNodeName, *srcIP, *dstSvcName, ovnNamespace, *dstNamespace,
+			*addressFamily, *dstIP, *dstPort, protocol, *loglevel)
+		return
+	}
 	targetOptions := 0
 	if *dstPodName != "" {
 		targetOptions++
@@ -1277,8 +1316,15 @@ func main() {
 			klog.Exitf("Usage: cannot parse IP address provided in -dst-ip")
 		}
 	}
+	if *dstHost != "" {
+		targetOptions++
+		parsedDstIP, err = resolveDstHost(*dstHost, *addressFamily)
+		if err != nil {
+			klog.Exitf("Usage: %v", err)
+		}
+	}
 	if targetOptions != 1 {
-		klog.Exitf("Usage: exactly one of -dst, -service or -dst-ip must be set")
+		klog.Exitf("Usage: exactly one of -dst, -service, -dst-ip or -dst-host must be set")
 	}
 
 	// Show some information about the nodes in this cluster - only if log level 5 or higher.
@@ -1292,10 +1338,12 @@ func main() {
 		klog.Exitf("Failed to get information from pod %s: %v", *srcPodName, err)
 	}
 	klog.V(5).Infof("srcPodInfo is %s\n", srcPodInfo)
+	setSource(podEndpoint(srcPodInfo))
 
 	// 1) Either run a trace from source pod to destination IP and return ...
 	if parsedDstIP != nil {
 		klog.V(5).Infof("Running a trace to an IP address")
+		setDestination(&TraceEndpoint{Kind: endpointKindIP, Name: *dstHost, IP: parsedDstIP.String(), Port: *dstPort})
 		egressNodeName, egressBridgeName := runOvnTraceToIP(coreclient, restconfig, srcPodInfo, parsedDstIP, ovnNamespace, protocol, *dstPort)
 		appSrcDstOut := runOfprotoTraceToIP(coreclient, restconfig, srcPodInfo, parsedDstIP, ovnNamespace, protocol, *dstPort, egressNodeName, egressBridgeName)
 		if *skipOvnDetrace {
@@ -1330,6 +1378,13 @@ func main() {
 		klog.Exitf("Failed to get information from pod %s: %v", *dstPodName, err)
 	}
 	klog.V(5).Infof("dstPodInfo is %s\n", dstPodInfo)
+	if dstSvcInfo != nil {
+		setDestination(serviceEndpoint(dstSvcInfo, *dstPort))
+	} else {
+		dstEndpoint := podEndpoint(dstPodInfo)
+		dstEndpoint.Port = *dstPort
+		setDestination(dstEndpoint)
+	}
 
 	// At least one pod must not be on the Host Network
 	if srcPodInfo.HostNetwork && dstPodInfo.HostNetwork {
@@ -0,0 +1,528 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
+)
+
+const (
+	// Output formats supported by the -output flag.
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+
+	// Overall verdicts of a trace.
+	verdictReachable   = "reachable"
+	verdictUnreachable = "unreachable"
+
+	// Verdicts of a single logical datapath hop.
+	hopVerdictForward = "forward"
+	hopVerdictOutput  = "output"
+	hopVerdictDrop    = "drop"
+
+	// Kinds of trace endpoints.
+	endpointKindPod            = "Pod"
+	endpointKindService        = "Service"
+	endpointKindIP             = "IP"
+	endpointKindExternalClient = "ExternalClient"
+)
+
+var (
+	ovnTraceDatapathRe = regexp.MustCompile(`^(ingress|egress)\(dp="([^"]*)"(?:, inport="([^"]*)")?(?:, outport="([^"]*)")?\)`)
+	ovnTraceFlowRe     = regexp.MustCompile(`^\s*\d+\.\s+(\S+)\s+\([^)]*\):\s+(.*),\s+priority\s+(\d+),\s+uuid\s+([0-9a-f]+)\s*$`)
+	ovnTraceOutputRe   = regexp.MustCompile(`/\* output to "([^"]*)"`)
+	ovnTraceACLStageRe = regexp.MustCompile(`^l[sr]_(in|out)_acl(_after_lb)?(_eval)?$`)
+	ovnTraceLBRe       = regexp.MustCompile(`^(ct_lb(_mark)?)\((.*)\);$`)
+	ovnTraceNATRe      = regexp.MustCompile(`^(ct_(dnat|snat)(_in_czone)?)(\((.*)\))?;$`)
+)
+
+// TraceResult is the structured result of an ovnkube-trace run, printed with -output=json or -output=yaml.
+type TraceResult struct {
+	Source      *TraceEndpoint `json:"source,omitempty"`
+	Destination *TraceEndpoint `json:"destination,omitempty"`
+	Steps       []TraceStep    `json:"steps"`
+	Verdict     string         `json:"verdict"`
+}
+
+// TraceEndpoint describes the resolved source or destination of a trace.
+type TraceEndpoint struct {
+	Kind        string         `json:"kind"`
+	Name        string         `json:"name,omitempty"`
+	Namespace   string         `json:"namespace,omitempty"`
+	Node        string         `json:"node,omitempty"`
+	IP          string         `json:"ip,omitempty"`
+	MAC         string         `json:"mac,omitempty"`
+	Port        string         `json:"port,omitempty"`
+	HostNetwork bool           `json:"hostNetwork,omitempty"`
+	Via         string         `json:"via,omitempty"`
+	ClusterIP   string         `json:"clusterIP,omitempty"`
+	Backend     *TraceEndpoint `json:"backend,omitempty"`
+}
+
+// TraceStep is the result of a single ovn-trace, ofproto/trace or ovn-detrace command.
+type TraceStep struct {
+	Command         string        `json:"command"`
+	Direction       string        `json:"direction"`
+	From            string        `json:"from"`
+	To              string        `json:"to"`
+	Node            string        `json:"node,omitempty"`
+	Success         bool          `json:"success"`
+	Datapaths       []DatapathHop `json:"datapaths,omitempty"`
+	DatapathActions string        `json:"datapathActions,omitempty"`
+	Output          string        `json:"output,omitempty"`
+	executor        *PodInfo      // The pod whose ovnkube pod ran the command
+}
+
+// DatapathHop describes the traversal of one logical datapath pipeline in an ovn-trace.
+type DatapathHop struct {
+	Pipeline      string   `json:"pipeline"`
+	Datapath      string   `json:"datapath"`
+	InPort        string   `json:"inport,omitempty"`
+	OutPort       string   `json:"outport,omitempty"`
+	ACLs          []ACLHit `json:"acls,omitempty"`
+	NAT           []NATHit `json:"nat,omitempty"`
+	LoadBalancers []LBHit  `json:"loadBalancers,omitempty"`
+	Verdict       string   `json:"verdict"`
+	Reason        string   `json:"reason,omitempty"`
+}
+
+// ACLHit is an ACL that a logical datapath hop matched.
+type ACLHit struct {
+	Stage       string            `json:"stage"`
+	Priority    int               `json:"priority"`
+	LogicalFlow string            `json:"logicalFlow"`
+	UUID        string            `json:"uuid,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Action      string            `json:"action,omitempty"`
+	Direction   string            `json:"direction,omitempty"`
+	Match       string            `json:"match,omitempty"`
+	OwnerType   string            `json:"ownerType,omitempty"`
+	OwnerName   string            `json:"ownerName,omitempty"`
+	Owner       string            `json:"owner,omitempty"`
+	ExternalIDs map[string]string `json:"externalIDs,omitempty"`
+	stageHint   string            // NB ACL UUID prefix, from the logical flow's stage-hint
+}
+
+// NATHit is a NAT action that a logical datapath hop applied.
+type NATHit struct {
+	Stage  string `json:"stage"`
+	Type   string `json:"type"`
+	Action string `json:"action"`
+}
+
+// LBHit is a load balancer action that a logical datapath hop applied.
+type LBHit struct {
+	Stage  string `json:"stage"`
+	Action string `json:"action"`
+}
+
+// traceReporter collects the structured result of a trace when a structured output format is selected.
+type traceReporter struct {
+	coreclient   *corev1client.CoreV1Client
+	restconfig   *rest.Config
+	ovnNamespace string
+	format       string
+	result       TraceResult
+}
+
+// reporter is set up in main according to the -output flag.
+var reporter *traceReporter
+
+// setupReporter validates the output format and prepares the collection of a structured result.
+func setupReporter(coreclient *corev1client.CoreV1Client, restconfig *rest.Config, ovnNamespace, format string) error {
+	switch format {
+	case outputText, outputJSON, outputYAML:
+		reporter = &traceReporter{
+			coreclient:   coreclient,
+			restconfig:   restconfig,
+			ovnNamespace: ovnNamespace,
+			format:       format,
+			result:       TraceResult{Steps: []TraceStep{}},
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported output format %q, must be one of %s, %s or %s", format, outputText, outputJSON, outputYAML)
+}
+
+// structuredOutput returns true if the trace result is printed in a structured format.
+func structuredOutput() bool {
+	return reporter != nil && reporter.format != outputText
+}
+
+// podEndpoint returns the TraceEndpoint for the given pod.
+func podEndpoint(podInfo *PodInfo) *TraceEndpoint {
+	return &TraceEndpoint{
+		Kind:        endpointKindPod,
+		Name:        podInfo.PodName,
+		Namespace:   podInfo.PodNamespace,
+		Node:        podInfo.NodeName,
+		IP:          podInfo.IP,
+		MAC:         podInfo.MAC,
+		HostNetwork: podInfo.HostNetwork,
+	}
+}
+
+// serviceEndpoint returns the TraceEndpoint for the given service and its selected backend.
+func serviceEndpoint(svcInfo *SvcInfo, port string) *TraceEndpoint {
+	backend := podEndpoint(svcInfo.PodInfo)
+	backend.Port = svcInfo.PodPort
+	return &TraceEndpoint{
+		Kind:      endpointKindService,
+		Name:      svcInfo.SvcName,
+		Namespace: svcInfo.SvcNamespace,
+		IP:        svcInfo.ClusterIP,
+		Port:      port,
+		ClusterIP: svcInfo.ClusterIP,
+		Backend:   backend,
+	}
+}
+
+// setSource sets the source of the structured result, if any.
+func setSource(endpoint *TraceEndpoint) {
+	if structuredOutput() {
+		reporter.result.Source = endpoint
+	}
+}
+
+// setDestination sets the destination of the structured result, if any.
+func setDestination(endpoint *TraceEndpoint) {
+	if structuredOutput() {
+		reporter.result.Destination = endpoint
+	}
+}
+
+// recordStep adds the result of a command to the structured result. Output of ovn-trace commands is parsed into
+// the logical datapaths that were traversed, and the ACLs that were hit are resolved against the OVN databases.
+func (r *traceReporter) recordStep(executor *PodInfo, commandDescription, src, dst, commandStdout string, success bool) {
+	command, direction, _ := strings.Cut(commandDescription, " ")
+	if strings.HasPrefix(commandDescription, "ovs-appctl ofproto/trace") {
+		command = "ofproto/trace"
+		direction = strings.TrimPrefix(commandDescription, "ovs-appctl ofproto/trace ")
+	}
+	step := TraceStep{
+		Command:   command,
+		Direction: direction,
+		From:      src,
+		To:        dst,
+		Success:   success,
+		executor:  executor,
+	}
+	if executor != nil {
+		step.Node = executor.NodeName
+	}
+	switch command {
+	case "ovn-trace":
+		step.Datapaths = parseOvnTrace(commandStdout)
+		r.resolveACLs(&step)
+	case "ofproto/trace":
+		step.DatapathActions = parseDatapathActions(commandStdout)
+	}
+	if klog.V(2).Enabled() || command == "ovn-detrace" {
+		step.Output = commandStdout
+	}
+	r.result.Steps = append(r.result.Steps, step)
+}
+
+// print prints the structured result to stdout.
+func (r *traceReporter) print() {
+	r.result.Verdict = verdictReachable
+	for _, step := range r.result.Steps {
+		if !step.Success {
+			r.result.Verdict = verdictUnreachable
+			break
+		}
+	}
+	var out []byte
+	var err error
+	if r.format == outputYAML {
+		out, err = yaml.Marshal(r.result)
+	} else {
+		out, err = json.MarshalIndent(r.result, "", "  ")
+	}
+	if err != nil {
+		klog.Exitf("Failed to marshal trace result: %v", err)
+	}
+	fmt.Println(string(out))
+}
+
+// printTraceResult prints the structured result, if any.
+func printTraceResult() {
+	if structuredOutput() {
+		reporter.print()
+	}
+}
+
+// exitWithTraceResult prints the structured result, if any, and exits with a failure.
+func exitWithTraceResult() {
+	printTraceResult()
+	os.Exit(-1)
+}
+
+// parseOvnTrace parses the text output of ovn-trace into the logical datapath pipelines that the packet traversed.
+func parseOvnTrace(ovnTraceOut string) []DatapathHop {
+	var hops []DatapathHop
+	var hop *DatapathHop
+	var stage string
+	seenACLs := map[string]bool{}
+	scanner := bufio.NewScanner(strings.NewReader(ovnTraceOut))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if subMatches := ovnTraceDatapathRe.FindStringSubmatch(line); subMatches != nil {
+			hops = append(hops, DatapathHop{
+				Pipeline: subMatches[1],
+				Datapath: subMatches[2],
+				InPort:   subMatches[3],
+				OutPort:  subMatches[4],
+				Verdict:  hopVerdictForward,
+			})
+			hop = &hops[len(hops)-1]
+			seenACLs = map[string]bool{}
+			stage = ""
+			continue
+		}
+		if hop == nil {
+			continue
+		}
+		if subMatches := ovnTraceFlowRe.FindStringSubmatch(line); subMatches != nil {
+			stage = subMatches[1]
+			if ovnTraceACLStageRe.MatchString(stage) && !seenACLs[subMatches[4]] {
+				seenACLs[subMatches[4]] = true
+				priority, _ := strconv.Atoi(subMatches[3])
+				hop.ACLs = append(hop.ACLs, ACLHit{
+					Stage:       stage,
+					Priority:    priority,
+					LogicalFlow: subMatches[4],
+					Match:       subMatches[2],
+				})
+			}
+			continue
+		}
+		action := strings.TrimSpace(line)
+		switch {
+		case action == "drop;":
+			hop.Verdict = hopVerdictDrop
+		case ovnTraceOutputRe.MatchString(action):
+			hop.Verdict = hopVerdictOutput
+			if hop.OutPort == "" {
+				hop.OutPort = ovnTraceOutputRe.FindStringSubmatch(action)[1]
+			}
+		case ovnTraceLBRe.MatchString(action):
+			hop.LoadBalancers = append(hop.LoadBalancers, LBHit{Stage: stage, Action: strings.TrimSuffix(action, ";")})
+		case ovnTraceNATRe.MatchString(action):
+			hop.NAT = append(hop.NAT, NATHit{
+				Stage:  stage,
+				Type:   ovnTraceNATRe.FindStringSubmatch(action)[2],
+				Action: strings.TrimSuffix(action, ";"),
+			})
+		}
+	}
+	return hops
+}
+
+// parseDatapathActions returns the last "Datapath actions" line of an ofproto/trace output.
+func parseDatapathActions(ofprotoTraceOut string) string {
+	var actions string
+	scanner := bufio.NewScanner(strings.NewReader(ofprotoTraceOut))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if after, found := strings.CutPrefix(scanner.Text(), "Datapath actions: "); found {
+			actions = after
+		}
+	}
+	return actions
+}
+
+// resolveACLs looks up the NB ACLs that generated the logical flows hit in the given step, by means of the
+// stage-hint that northd stores in the logical flow's external_ids.
+func (r *traceReporter) resolveACLs(step *TraceStep) {
+	if step.executor == nil {
+		return
+	}
+	var lflows []string
+	for _, hop := range step.Datapaths {
+		for _, aclHit := range hop.ACLs {
+			lflows = append(lflows, aclHit.LogicalFlow)
+		}
+	}
+	if len(lflows) == 0 {
+		return
+	}
+
+	cmd := "ovn-sbctl --no-leader-only " + step.executor.SbCommand + " --format=json --columns=_uuid,external_ids list Logical_Flow " + strings.Join(lflows, " ")
+	lflowRows, err := r.listOvsdbRows(step.executor, cmd)
+	if err != nil {
+		klog.V(1).Infof("Could not look up logical flows %v: %v", lflows, err)
+		return
+	}
+	// Logical flows without a stage-hint are added by northd itself and do not belong to any NB ACL.
+	var hints []string
+	for i := range step.Datapaths {
+		acls := step.Datapaths[i].ACLs[:0]
+		for _, aclHit := range step.Datapaths[i].ACLs {
+			for _, row := range lflowRows {
+				if strings.HasPrefix(ovsdbString(row["_uuid"]), aclHit.LogicalFlow) {
+					aclHit.stageHint = ovsdbMap(row["external_ids"])["stage-hint"]
+					break
+				}
+			}
+			if aclHit.stageHint != "" {
+				acls = append(acls, aclHit)
+				hints = append(hints, aclHit.stageHint)
+			}
+		}
+		step.Datapaths[i].ACLs = acls
+	}
+	if len(hints) == 0 {
+		return
+	}
+
+	cmd = "ovn-nbctl --no-leader-only " + step.executor.NbCommand + " --format=json --columns=_uuid,name,action,direction,priority,match,external_ids list ACL " + strings.Join(hints, " ")
+	aclRows, err := r.listOvsdbRows(step.executor, cmd)
+	if err != nil {
+		klog.V(1).Infof("Could not look up ACLs %v: %v", hints, err)
+		return
+	}
+	for i := range step.Datapaths {
+		for j := range step.Datapaths[i].ACLs {
+			aclHit := &step.Datapaths[i].ACLs[j]
+			for _, row := range aclRows {
+				uuid := ovsdbString(row["_uuid"])
+				if !strings.HasPrefix(uuid, aclHit.stageHint) {
+					continue
+				}
+				aclHit.UUID = uuid
+				aclHit.Name = ovsdbString(row["name"])
+				aclHit.Action = ovsdbString(row["action"])
+				aclHit.Direction = ovsdbString(row["direction"])
+				aclHit.Match = ovsdbString(row["match"])
+				if priority, err := strconv.Atoi(ovsdbString(row["priority"])); err == nil {
+					aclHit.Priority = priority
+				}
+				aclHit.ExternalIDs = ovsdbMap(row["external_ids"])
+				aclHit.OwnerType = aclHit.ExternalIDs[libovsdbops.OwnerTypeKey.String()]
+				aclHit.OwnerName = aclHit.ExternalIDs[libovsdbops.ObjectNameKey.String()]
+				aclHit.Owner = describeACLOwner(aclHit)
+				break
+			}
+		}
+	}
+	setDropReasons(step.Datapaths)
+}
+
+// setDropReasons attributes drops to the last deny ACL that was hit in the dropping hop.
+func setDropReasons(hops []DatapathHop) {
+	for i := range hops {
+		if hops[i].Verdict != hopVerdictDrop {
+			continue
+		}
+		for j := len(hops[i].ACLs) - 1; j >= 0; j-- {
+			aclHit := hops[i].ACLs[j]
+			if isDenyACLAction(aclHit.Action) && aclHit.Owner != "" {
+				hops[i].Reason = fmt.Sprintf("%s by %s", describeACLAction(aclHit.Action), aclHit.Owner)
+				break
+			}
+		}
+	}
+}
+
+// dropReasons returns the reasons for drops in the given ovn-trace output, e.g. "Dropped by NetworkPolicy ns/foo rule
+// ingress[2]", as far as they can be attributed to ACLs.
+func (r *traceReporter) dropReasons(executor *PodInfo, ovnTraceOut string) []string {
+	step := TraceStep{Datapaths: parseOvnTrace(ovnTraceOut), executor: executor}
+	r.resolveACLs(&step)
+	var reasons []string
+	for _, hop := range step.Datapaths {
+		if hop.Reason != "" {
+			reasons = append(reasons, fmt.Sprintf("%s (datapath %s, %s pipeline)", hop.Reason, hop.Datapath, hop.Pipeline))
+		}
+	}
+	return reasons
+}
+
+// listOvsdbRows runs an ovn-nbctl/ovn-sbctl list command with --format=json and returns the rows as maps of column
+// name to the column's JSON value.
+func (r *traceReporter) listOvsdbRows(executor *PodInfo, cmd string) ([]map[string]interface{}, error) {
+	stdout, stderr, err := execInPod(r.coreclient, r.restconfig, r.ovnNamespace, executor.OvnKubePodName, executor.OvnKubeContainerName, cmd, "")
+	if err != nil {
+		return nil, fmt.Errorf("execInPod() failed with %s stderr %s stdout %s", err, stderr, stdout)
+	}
+	table := struct {
+		Data     [][]interface{} `json:"data"`
+		Headings []string        `json:"headings"`
+	}{}
+	if err := json.Unmarshal([]byte(stdout), &table); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", stdout, err)
+	}
+	rows := make([]map[string]interface{}, 0, len(table.Data))
+	for _, data := range table.Data {
+		row := map[string]interface{}{}
+		for i, heading := range table.Headings {
+			if i < len(data) {
+				row[heading] = data[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ovsdbString returns the string representation of an atomic OVSDB JSON value, such as a string, an integer,
+// a ["uuid", <uuid>] pair or an optional value ["set", [<value>]].
+func ovsdbString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatInt(int64(v), 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		if len(v) != 2 {
+			return ""
+		}
+		switch v[0] {
+		case "uuid":
+			return ovsdbString(v[1])
+		case "set":
+			if set, ok := v[1].([]interface{}); ok && len(set) == 1 {
+				return ovsdbString(set[0])
+			}
+		}
+	}
+	return ""
+}
+
+// ovsdbMap returns the string map representation of an OVSDB JSON ["map", [[<key>, <value>], ...]] value.
+func ovsdbMap(value interface{}) map[string]string {
+	m := map[string]string{}
+	v, ok := value.([]interface{})
+	if !ok || len(v) != 2 || v[0] != "map" {
+		return m
+	}
+	pairs, ok := v[1].([]interface{})
+	if !ok {
+		return m
+	}
+	for _, pair := range pairs {
+		if kv, ok := pair.([]interface{}); ok && len(kv) == 2 {
+			m[ovsdbString(kv[0])] = ovsdbString(kv[1])
+		}
+	}
+	return m
+}
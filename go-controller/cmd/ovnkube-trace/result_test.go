@@ -0,0 +1,329 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// podToServiceTrace is captured ovn-trace output of a pod reaching a service backend on the same node.
+const podToServiceTrace = `# tcp,reg14=0x3,vlan_tci=0x0000,dl_src=0a:58:0a:f4:01:03,dl_dst=0a:58:0a:f4:01:01,nw_src=10.244.1.3,nw_dst=10.96.12.7,nw_tos=0,nw_ecn=0,nw_ttl=64,nw_frag=no,tp_src=52888,tp_dst=80,tcp_flags=0
+
+ingress(dp="ovn-worker", inport="default_client")
+-------------------------------------------------
+ 0. ls_in_check_port_sec (northd.c:8691): 1, priority 50, uuid 1d1a8a7a
+    reg0[15] = check_in_port_sec();
+    next;
+ 4. ls_in_pre_acl (northd.c:5997): ip, priority 100, uuid 7c9a64d4
+    reg0[0] = 1;
+    next;
+ 8. ls_in_acl_eval (northd.c:6920): reg0[7] == 1 && (inport == @a1234 && ip4), priority 2001, uuid 4c6b6f77
+    reg8[16] = 1;
+    next;
+ 8. ls_in_acl_eval (northd.c:6920): reg0[7] == 1 && (inport == @a1234 && ip4), priority 2001, uuid 4c6b6f77
+    reg8[16] = 1;
+    next;
+14. ls_in_lb (northd.c:7502): ct.new && ip4.dst == 10.96.12.7 && tcp.dst == 80, priority 120, uuid ab2b4c12
+    reg0[1] = 0;
+    ct_lb_mark(backends=10.244.1.4:8080);
+
+ct_lb_mark /* default (use --ct to customize) */
+------------------------------------------------
+27. ls_in_l2_lkup (northd.c:9485): eth.dst == 0a:58:0a:f4:01:04, priority 50, uuid 95a6d1c0
+    outport = "default_server";
+    output;
+
+egress(dp="ovn-worker", inport="default_client", outport="default_server")
+--------------------------------------------------------------------------
+ 0. ls_out_pre_acl (northd.c:5997): ip, priority 100, uuid 3e4f5a6b
+    reg0[0] = 1;
+    next;
+ 4. ls_out_acl_eval (northd.c:6920): reg0[8] == 1 && (outport == @a5678 && ip4), priority 1000, uuid 5d7e8f90
+    reg8[17] = 1;
+    next;
+ 9. ls_out_check_port_sec (northd.c:6033): 1, priority 0, uuid 2f3a4b5c
+    reg0[15] = check_out_port_sec();
+    next;
+10. ls_out_apply_port_sec (northd.c:6038): 1, priority 0, uuid 6a7b8c9d
+    output;
+    /* output to "default_server", type "" */
+`
+
+// podToGatewayTrace is captured ovn-trace output of a pod reaching an external host through the gateway router.
+const podToGatewayTrace = `ingress(dp="GR_ovn-worker", inport="rtoj-GR_ovn-worker")
+-------------------------------------------------------
+ 0. lr_in_admission (northd.c:12089): eth.dst == 0a:58:64:40:00:02 && inport == "rtoj-GR_ovn-worker", priority 50, uuid 0a1b2c3d
+    xreg0[0..47] = 0a:58:64:40:00:02;
+    next;
+ 4. lr_in_dnat (northd.c:11102): ct.est && !ct.rel && ct_mark.natted == 1, priority 50, uuid 9e8d7c6b
+    ct_dnat;
+
+egress(dp="GR_ovn-worker", inport="rtoj-GR_ovn-worker", outport="rtoe-GR_ovn-worker")
+------------------------------------------------------------------------------------
+ 3. lr_out_snat (northd.c:11471): ip && ip4.src == 10.244.0.0/16, priority 153, uuid 7f6e5d4c
+    ct_snat_in_czone(172.18.0.3);
+ 6. lr_out_delivery (northd.c:13040): outport == "rtoe-GR_ovn-worker", priority 100, uuid 1b2c3d4e
+    output;
+    /* output to "rtoe-GR_ovn-worker", type "l3gateway" */
+`
+
+// droppedTrace is captured ovn-trace output of a packet dropped by an ACL.
+const droppedTrace = `egress(dp="ovn-worker", inport="default_client", outport="default_server")
+--------------------------------------------------------------------------
+ 4. ls_out_acl_eval (northd.c:6920): reg0[8] == 1 && (outport == @a5678 && ip4), priority 1000, uuid 5d7e8f90
+    reg8[18] = 1;
+    next;
+ 4. ls_out_acl_eval (northd.c:6920): reg0[10] == 1 && (outport == @a5678), priority 1001, uuid 6e8f9a01
+    reg8[18] = 1;
+    drop;
+`
+
+func TestParseOvnTrace(t *testing.T) {
+	tests := []struct {
+		name     string
+		traceOut string
+		expected []DatapathHop
+	}{
+		{
+			name:     "pod to service on the same node",
+			traceOut: podToServiceTrace,
+			expected: []DatapathHop{
+				{
+					Pipeline: "ingress",
+					Datapath: "ovn-worker",
+					InPort:   "default_client",
+					ACLs: []ACLHit{
+						{Stage: "ls_in_acl_eval", Priority: 2001, LogicalFlow: "4c6b6f77", Match: "reg0[7] == 1 && (inport == @a1234 && ip4)"},
+					},
+					LoadBalancers: []LBHit{{Stage: "ls_in_lb", Action: "ct_lb_mark(backends=10.244.1.4:8080)"}},
+					Verdict:       hopVerdictForward,
+				},
+				{
+					Pipeline: "egress",
+					Datapath: "ovn-worker",
+					InPort:   "default_client",
+					OutPort:  "default_server",
+					ACLs: []ACLHit{
+						{Stage: "ls_out_acl_eval", Priority: 1000, LogicalFlow: "5d7e8f90", Match: "reg0[8] == 1 && (outport == @a5678 && ip4)"},
+					},
+					Verdict: hopVerdictOutput,
+				},
+			},
+		},
+		{
+			name:     "pod to external host through the gateway router",
+			traceOut: podToGatewayTrace,
+			expected: []DatapathHop{
+				{
+					Pipeline: "ingress",
+					Datapath: "GR_ovn-worker",
+					InPort:   "rtoj-GR_ovn-worker",
+					NAT:      []NATHit{{Stage: "lr_in_dnat", Type: "dnat", Action: "ct_dnat"}},
+					Verdict:  hopVerdictForward,
+				},
+				{
+					Pipeline: "egress",
+					Datapath: "GR_ovn-worker",
+					InPort:   "rtoj-GR_ovn-worker",
+					OutPort:  "rtoe-GR_ovn-worker",
+					NAT:      []NATHit{{Stage: "lr_out_snat", Type: "snat", Action: "ct_snat_in_czone(172.18.0.3)"}},
+					Verdict:  hopVerdictOutput,
+				},
+			},
+		},
+		{
+			name:     "drop by ACL",
+			traceOut: droppedTrace,
+			expected: []DatapathHop{
+				{
+					Pipeline: "egress",
+					Datapath: "ovn-worker",
+					InPort:   "default_client",
+					OutPort:  "default_server",
+					ACLs: []ACLHit{
+						{Stage: "ls_out_acl_eval", Priority: 1000, LogicalFlow: "5d7e8f90", Match: "reg0[8] == 1 && (outport == @a5678 && ip4)"},
+						{Stage: "ls_out_acl_eval", Priority: 1001, LogicalFlow: "6e8f9a01", Match: "reg0[10] == 1 && (outport == @a5678)"},
+					},
+					Verdict: hopVerdictDrop,
+				},
+			},
+		},
+		{
+			name:     "empty output",
+			traceOut: "",
+			expected: nil,
+		},
+		{
+			name:     "error output",
+			traceOut: "ovn-trace: ovn-worker: unknown datapath\n",
+			expected: nil,
+		},
+		{
+			name: "actions before the first datapath are ignored",
+			traceOut: `    drop;
+    /* output to "default_server", type "" */
+ingress(dp="ovn-worker")
+------------------------
+ 0. ls_in_check_port_sec (northd.c:8691): 1, priority 50
+    drop;
+`,
+			expected: []DatapathHop{
+				{Pipeline: "ingress", Datapath: "ovn-worker", Verdict: hopVerdictDrop},
+			},
+		},
+		{
+			name: "malformed flow lines are not reported as ACLs",
+			traceOut: `ingress(dp="ovn-worker", inport="default_client")
+ 8. ls_in_acl_eval (northd.c:6920): reg0[7] == 1, priority high, uuid 4c6b6f77
+ 8. ls_in_acl_eval: reg0[7] == 1, priority 2001, uuid 4c6b6f77
+ingress(dp="ovn-worker", inport=
+`,
+			expected: []DatapathHop{
+				{Pipeline: "ingress", Datapath: "ovn-worker", InPort: "default_client", Verdict: hopVerdictForward},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseOvnTrace(tt.traceOut)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseOvnTrace() = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDatapathActions(t *testing.T) {
+	tests := []struct {
+		name     string
+		traceOut string
+		expected string
+	}{
+		{
+			name: "last datapath actions are returned",
+			traceOut: `Flow: tcp,in_port=1,vlan_tci=0x0000,dl_src=00:00:00:00:00:00,dl_dst=02:42:ac:12:00:03,nw_src=192.0.2.10,nw_dst=172.18.0.3,nw_tos=0,nw_ecn=0,nw_ttl=64,nw_frag=no,tp_src=12345,tp_dst=30080,tcp_flags=0
+
+bridge("breth0")
+----------------
+ 0. ip,in_port=1,nw_dst=172.18.0.3, priority 50
+    ct(table=1,zone=64000)
+    drop
+     -> A clone of the packet is forked to recirculate. The forked pipeline will be resumed at table 1.
+     -> Sets the packet to an untracked state, and clears all the conntrack fields.
+
+Final flow: unchanged
+Megaflow: recirc_id=0,eth,ip,in_port=1,nw_dst=172.18.0.3,nw_frag=no
+Datapath actions: ct(zone=64000),recirc(0x1)
+
+===============================================================================
+recirc(0x1) - resume conntrack with default ct_state=trk|new (use --ct-next to customize)
+===============================================================================
+
+Flow: recirc_id=0x1,ct_state=new|trk,ct_zone=64000,eth,tcp,in_port=1,nw_dst=172.18.0.3,tp_dst=30080
+
+bridge("breth0")
+----------------
+    thaw
+        Resuming from table 1
+ 1. ct_state=+new+trk,ip,in_port=1, priority 100
+    output:2
+
+Final flow: unchanged
+Megaflow: recirc_id=0x1,ct_state=+new-est-rel-rpl+trk,eth,ip,in_port=1,nw_frag=no
+Datapath actions: 3
+`,
+			expected: "3",
+		},
+		{
+			name:     "drop",
+			traceOut: "Megaflow: recirc_id=0,eth,ip,in_port=1,nw_frag=no\nDatapath actions: drop\n",
+			expected: "drop",
+		},
+		{
+			name:     "no datapath actions",
+			traceOut: "ovs-appctl: cannot connect to \"/var/run/openvswitch/ovs-vswitchd.1234.ctl\"\n",
+			expected: "",
+		},
+		{
+			name:     "prefix must start the line",
+			traceOut: "    Datapath actions: 3\n",
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDatapathActions(tt.traceOut); got != tt.expected {
+				t.Errorf("parseDatapathActions() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// unmarshalOvsdbValue decodes a column value of ovn-nbctl/ovn-sbctl --format=json output.
+func unmarshalOvsdbValue(t *testing.T, value string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		t.Fatalf("failed to unmarshal %q: %v", value, err)
+	}
+	return v
+}
+
+func TestOvsdbString(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{name: "string", value: `"allow-related"`, expected: "allow-related"},
+		{name: "integer", value: `1001`, expected: "1001"},
+		{name: "boolean", value: `true`, expected: "true"},
+		{name: "uuid", value: `["uuid","4c6b6f77-1d2e-4f3a-9b8c-7d6e5f4a3b2c"]`, expected: "4c6b6f77-1d2e-4f3a-9b8c-7d6e5f4a3b2c"},
+		{name: "optional value", value: `["set",["ANP:allow-all:Ingress:0"]]`, expected: "ANP:allow-all:Ingress:0"},
+		{name: "empty optional value", value: `["set",[]]`, expected: ""},
+		{name: "set with multiple values", value: `["set",["a","b"]]`, expected: ""},
+		{name: "map", value: `["map",[["k","v"]]]`, expected: ""},
+		{name: "malformed pair", value: `["uuid"]`, expected: ""},
+		{name: "null", value: `null`, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ovsdbString(unmarshalOvsdbValue(t, tt.value)); got != tt.expected {
+				t.Errorf("ovsdbString(%s) = %q, expected %q", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOvsdbMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected map[string]string
+	}{
+		{
+			name:  "external IDs",
+			value: `["map",[["k8s.ovn.org/name","default"],["k8s.ovn.org/owner-type","NetworkPolicy"],["k8s.ovn.org/priority","1001"]]]`,
+			expected: map[string]string{
+				"k8s.ovn.org/name":       "default",
+				"k8s.ovn.org/owner-type": "NetworkPolicy",
+				"k8s.ovn.org/priority":   "1001",
+			},
+		},
+		{name: "empty map", value: `["map",[]]`, expected: map[string]string{}},
+		{name: "not a map", value: `["set",[["k","v"]]]`, expected: map[string]string{}},
+		{name: "string", value: `"stage-hint=4c6b6f77"`, expected: map[string]string{}},
+		{name: "malformed pairs are skipped", value: `["map",[["k"],["k2","v2"],"k3"]]`, expected: map[string]string{"k2": "v2"}},
+		{name: "malformed map", value: `["map","k"]`, expected: map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ovsdbMap(unmarshalOvsdbValue(t, tt.value)); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ovsdbMap(%s) = %v, expected %v", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
@@ -152,3 +152,5 @@ echo "Copying clusterNetworkConnect CRD"
 cp _output/crds/k8s.ovn.org_clusternetworkconnects.yaml ../helm/ovn-kubernetes/crds/k8s.ovn.org_clusternetworkconnects.yaml
 echo "Copying vtep CRD"
 cp _output/crds/k8s.ovn.org_vteps.yaml ../helm/ovn-kubernetes/crds/k8s.ovn.org_vteps.yaml
+echo "Copying samplingConfig CRD"
+cp _output/crds/k8s.ovn.org_samplingconfigs.yaml ../helm/ovn-kubernetes/crds/k8s.ovn.org_samplingconfigs.yaml
@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package observability_lib
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/exporter"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
+)
+
+// aggregationBuckets is the number of buckets the sliding window is split into. Samples expire with the
+// granularity of window/aggregationBuckets.
+const aggregationBuckets = 10
+
+// flowKey identifies an aggregated flow.
+type flowKey struct {
+	src, dst       string
+	protocol       uint8
+	dstPort        uint16
+	srcPod, dstPod string
+	// message is the decoded event message, only set for denied flows.
+	message string
+}
+
+func (k flowKey) String() string {
+	endpoint := func(ip, pod string) string {
+		if pod != "" {
+			return fmt.Sprintf("%s(%s)", ip, pod)
+		}
+		return ip
+	}
+	s := endpoint(k.src, k.srcPod) + " -> " + endpoint(k.dst, k.dstPod)
+	if protocol := exporter.ProtocolName(k.protocol); protocol != "" {
+		s += " " + protocol
+		if k.dstPort != 0 {
+			s += fmt.Sprintf("/%d", k.dstPort)
+		}
+	}
+	return s
+}
+
+type flowStats struct {
+	samples uint64
+	bytes   uint64
+}
+
+type aggregationBucket struct {
+	start   time.Time
+	talkers map[flowKey]*flowStats
+	denied  map[flowKey]*flowStats
+}
+
+// sampleAggregator counts samples per flow over a sliding window, to report the top talkers and
+// top denied flows instead of printing every sample.
+type sampleAggregator struct {
+	window      time.Duration
+	bucketWidth time.Duration
+	topN        int
+
+	mu      sync.Mutex
+	buckets []aggregationBucket
+}
+
+func newSampleAggregator(window time.Duration, topN int) *sampleAggregator {
+	bucketWidth := window / aggregationBuckets
+	if bucketWidth <= 0 {
+		bucketWidth = window
+	}
+	return &sampleAggregator{
+		window:      window,
+		bucketWidth: bucketWidth,
+		topN:        topN,
+		buckets:     make([]aggregationBucket, aggregationBuckets),
+	}
+}
+
+func (a *sampleAggregator) add(sample *exporter.Sample, now time.Time) {
+	key := flowKey{
+		src:      sample.SrcIP.String(),
+		dst:      sample.DstIP.String(),
+		protocol: sample.Protocol,
+		dstPort:  sample.DstPort,
+		srcPod:   podName(sample.SrcPod),
+		dstPod:   podName(sample.DstPod),
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	start := now.Truncate(a.bucketWidth)
+	bucket := &a.buckets[(start.UnixNano()/int64(a.bucketWidth))%int64(len(a.buckets))]
+	if !bucket.start.Equal(start) {
+		*bucket = aggregationBucket{
+			start:   start,
+			talkers: map[flowKey]*flowStats{},
+			denied:  map[flowKey]*flowStats{},
+		}
+	}
+	addStats(bucket.talkers, key, sample.Bytes)
+	if model.GetEventOwner(sample.Event).IsDenied() {
+		key.message = sample.Event.String()
+		addStats(bucket.denied, key, sample.Bytes)
+	}
+}
+
+func addStats(flows map[flowKey]*flowStats, key flowKey, bytes uint32) {
+	stats := flows[key]
+	if stats == nil {
+		stats = &flowStats{}
+		flows[key] = stats
+	}
+	stats.samples++
+	stats.bytes += uint64(bytes)
+}
+
+// report returns the lines describing the top talkers and top denied flows in the window ending at now.
+func (a *sampleAggregator) report(now time.Time) []string {
+	talkers := map[flowKey]*flowStats{}
+	denied := map[flowKey]*flowStats{}
+	a.mu.Lock()
+	windowStart := now.Truncate(a.bucketWidth).Add(-a.window)
+	for _, bucket := range a.buckets {
+		if !bucket.start.After(windowStart) || bucket.start.After(now) {
+			continue
+		}
+		mergeStats(talkers, bucket.talkers)
+		mergeStats(denied, bucket.denied)
+	}
+	a.mu.Unlock()
+
+	lines := []string{fmt.Sprintf("Top %d talkers in the last %s:", a.topN, a.window)}
+	lines = append(lines, a.topFlows(talkers)...)
+	lines = append(lines, fmt.Sprintf("Top %d denied flows in the last %s:", a.topN, a.window))
+	lines = append(lines, a.topFlows(denied)...)
+	return lines
+}
+
+func mergeStats(dst, src map[flowKey]*flowStats) {
+	for key, stats := range src {
+		merged := dst[key]
+		if merged == nil {
+			merged = &flowStats{}
+			dst[key] = merged
+		}
+		merged.samples += stats.samples
+		merged.bytes += stats.bytes
+	}
+}
+
+// topFlows returns the topN flows with the most samples, most bytes first for the same number of samples.
+func (a *sampleAggregator) topFlows(flows map[flowKey]*flowStats) []string {
+	keys := make([]flowKey, 0, len(flows))
+	for key := range flows {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		si, sj := flows[keys[i]], flows[keys[j]]
+		if si.samples != sj.samples {
+			return si.samples > sj.samples
+		}
+		if si.bytes != sj.bytes {
+			return si.bytes > sj.bytes
+		}
+		return keys[i].String()+keys[i].message < keys[j].String()+keys[j].message
+	})
+	if len(keys) > a.topN {
+		keys = keys[:a.topN]
+	}
+	lines := make([]string, 0, len(keys))
+	for i, key := range keys {
+		line := fmt.Sprintf("  %d. %s: %d samples, %d bytes", i+1, key, flows[key].samples, flows[key].bytes)
+		if key.message != "" {
+			line += ", " + key.message
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func podName(pod *model.Pod) string {
+	if pod == nil {
+		return ""
+	}
+	return pod.String()
+}
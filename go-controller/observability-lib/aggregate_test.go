@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package observability_lib
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleAggregator(t *testing.T) {
+	a := newSampleAggregator(10*time.Second, 2)
+	start := time.Unix(1700000000, 0)
+
+	// old flow that leaves the window
+	old := newTestSample("drop")
+	old.SrcIP = net.ParseIP("10.244.0.9")
+	old.SrcPod = nil
+	for i := 0; i < 5; i++ {
+		a.add(old, start)
+	}
+	allowed := newTestSample("allow")
+	for i := 0; i < 3; i++ {
+		a.add(allowed, start.Add(5*time.Second))
+	}
+	denied := newTestSample("drop")
+	denied.DstPort = 9090
+	a.add(denied, start.Add(6*time.Second))
+	a.add(denied, start.Add(9*time.Second))
+
+	assert.Equal(t, []string{
+		"Top 2 talkers in the last 10s:",
+		"  1. 10.244.0.9 -> 10.244.1.4(server-ns/server) tcp/8080: 5 samples, 500 bytes",
+		"  2. 10.244.0.3(client-ns/client) -> 10.244.1.4(server-ns/server) tcp/8080: 3 samples, 300 bytes",
+		"Top 2 denied flows in the last 10s:",
+		"  1. 10.244.0.9 -> 10.244.1.4(server-ns/server) tcp/8080: 5 samples, 500 bytes, Dropped by network policy deny-all in namespace policy-ns, direction Ingress",
+		"  2. 10.244.0.3(client-ns/client) -> 10.244.1.4(server-ns/server) tcp/9090: 2 samples, 200 bytes, Dropped by network policy deny-all in namespace policy-ns, direction Ingress",
+	}, a.report(start.Add(9*time.Second)))
+
+	// the old flow has left the window
+	assert.Equal(t, []string{
+		"Top 2 talkers in the last 10s:",
+		"  1. 10.244.0.3(client-ns/client) -> 10.244.1.4(server-ns/server) tcp/8080: 3 samples, 300 bytes",
+		"  2. 10.244.0.3(client-ns/client) -> 10.244.1.4(server-ns/server) tcp/9090: 2 samples, 200 bytes",
+		"Top 2 denied flows in the last 10s:",
+		"  1. 10.244.0.3(client-ns/client) -> 10.244.1.4(server-ns/server) tcp/9090: 2 samples, 200 bytes, Dropped by network policy deny-all in namespace policy-ns, direction Ingress",
+	}, a.report(start.Add(12*time.Second)))
+
+	// buckets are reused after the window
+	a.add(allowed, start.Add(20*time.Second))
+	assert.Equal(t, []string{
+		"Top 2 talkers in the last 10s:",
+		"  1. 10.244.0.3(client-ns/client) -> 10.244.1.4(server-ns/server) tcp/8080: 1 samples, 100 bytes",
+		"Top 2 denied flows in the last 10s:",
+	}, a.report(start.Add(20*time.Second)))
+}
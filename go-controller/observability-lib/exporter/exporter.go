@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"net"
+	"time"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
+)
+
+// Sample is a single psample event together with its decoded ovn-kubernetes enrichment.
+type Sample struct {
+	Timestamp   time.Time
+	ObsDomainID uint32
+	ObsPointID  uint32
+	SrcIP       net.IP
+	DstIP       net.IP
+	// Protocol is the IP protocol number, e.g. 6 for TCP.
+	Protocol uint8
+	SrcPort  uint16
+	DstPort  uint16
+	// Bytes is the original size of the sampled packet.
+	Bytes uint32
+	// Event is the decoded network event, nil if enrichment is disabled or decoding failed.
+	Event model.NetworkEvent
+	// SrcPod and DstPod are the local pods owning the packet addresses, nil if enrichment is disabled or
+	// the address doesn't belong to a local pod.
+	SrcPod *model.Pod
+	DstPod *model.Pod
+}
+
+// Exporter sends samples to an external system. Export is called for every sample that passes the
+// configured filters, Close flushes pending samples and releases resources.
+type Exporter interface {
+	Export(sample *Sample) error
+	Close() error
+}
+
+// eventMessage returns the human-readable description of the given network event, or "" if there is none.
+func eventMessage(event model.NetworkEvent) string {
+	if event == nil {
+		return ""
+	}
+	return event.String()
+}
+
+// ProtocolName returns the lowercase name of the given IP protocol number, e.g. "tcp", or "" for protocols
+// that are not expected to be sampled.
+func ProtocolName(protocol uint8) string {
+	switch protocol {
+	case 1:
+		return "icmp"
+	case 6:
+		return "tcp"
+	case 17:
+		return "udp"
+	case 58:
+		return "icmpv6"
+	case 132:
+		return "sctp"
+	}
+	return ""
+}
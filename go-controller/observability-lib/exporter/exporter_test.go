@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
+)
+
+func newTestSample() *Sample {
+	return &Sample{
+		Timestamp:   time.UnixMilli(1700000000123),
+		ObsDomainID: 1,
+		ObsPointID:  42,
+		SrcIP:       net.ParseIP("10.244.0.3"),
+		DstIP:       net.ParseIP("10.244.1.4"),
+		Protocol:    6,
+		SrcPort:     34567,
+		DstPort:     8080,
+		Bytes:       74,
+		Event: &model.ACLEvent{
+			Action:    "drop",
+			Actor:     "NetworkPolicy",
+			Name:      "deny-all",
+			Namespace: "foo",
+			Direction: "Ingress",
+		},
+	}
+}
+
+func TestPrometheusExporter(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	e, err := NewPrometheusExporter(registry)
+	require.NoError(t, err)
+
+	sample := newTestSample()
+	require.NoError(t, e.Export(sample))
+	require.NoError(t, e.Export(sample))
+	require.NoError(t, e.Export(&Sample{Bytes: 100}))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	values := map[string]map[string]float64{}
+	for _, family := range families {
+		values[family.GetName()] = map[string]float64{}
+		for _, metric := range family.GetMetric() {
+			values[family.GetName()][metricLabels(metric)] = metric.GetCounter().GetValue()
+		}
+	}
+	ownerLabels := "action=drop,direction=Ingress,owner_name=deny-all,owner_namespace=foo,owner_type=NetworkPolicy"
+	noOwnerLabels := "action=,direction=,owner_name=,owner_namespace=,owner_type="
+	assert.Equal(t, map[string]float64{ownerLabels: 2, noOwnerLabels: 1}, values["ovnkube_observ_samples_total"])
+	assert.Equal(t, map[string]float64{ownerLabels: 148, noOwnerLabels: 100}, values["ovnkube_observ_sampled_bytes_total"])
+}
+
+// metricLabels returns the sorted labels of the given metric as a comma separated list of name=value.
+func metricLabels(metric *dto.Metric) string {
+	var labels string
+	for i, label := range metric.GetLabel() {
+		if i > 0 {
+			labels += ","
+		}
+		labels += label.GetName() + "=" + label.GetValue()
+	}
+	return labels
+}
+
+func TestOTLPLogsExporter(t *testing.T) {
+	var mu sync.Mutex
+	var requests []otlpExportLogsRequest
+	// collector is a stand-in for an OpenTelemetry collector OTLP/HTTP receiver.
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/logs", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		req := otlpExportLogsRequest{}
+		assert.NoError(t, json.Unmarshal(body, &req))
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	e := NewOTLPLogsExporter(collector.URL + "/v1/logs")
+	e.batchSize = 2
+	require.NoError(t, e.Export(newTestSample()))
+	require.NoError(t, e.Export(newTestSample()))
+	require.NoError(t, e.Export(newTestSample()))
+	// Close flushes the last, incomplete batch
+	require.NoError(t, e.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	var records []otlpLogRecord
+	for _, req := range requests {
+		require.Len(t, req.ResourceLogs, 1)
+		assert.Contains(t, req.ResourceLogs[0].Resource.Attributes, otlpString("service.name", "ovnkube-observ"))
+		require.Len(t, req.ResourceLogs[0].ScopeLogs, 1)
+		records = append(records, req.ResourceLogs[0].ScopeLogs[0].LogRecords...)
+	}
+	require.Len(t, records, 3)
+	record := records[0]
+	assert.Equal(t, "1700000000123000000", record.TimeUnixNano)
+	require.NotNil(t, record.Body.StringValue)
+	assert.Equal(t, "Dropped by network policy deny-all in namespace foo, direction Ingress", *record.Body.StringValue)
+	for _, attr := range []otlpKeyValue{
+		otlpString("source.address", "10.244.0.3"),
+		otlpString("destination.address", "10.244.1.4"),
+		otlpString("network.transport", "tcp"),
+		otlpInt("destination.port", 8080),
+		otlpInt("ovn.observability.point_id", 42),
+		otlpString("ovn.acl.owner_type", "NetworkPolicy"),
+		otlpString("ovn.acl.owner_namespace", "foo"),
+		otlpString("ovn.acl.owner_name", "deny-all"),
+	} {
+		assert.Contains(t, record.Attributes, attr)
+	}
+}
+
+func TestOTLPLogsExporterCollectorError(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	e := NewOTLPLogsExporter(collector.URL + "/v1/logs")
+	require.NoError(t, e.Export(newTestSample()))
+	assert.ErrorContains(t, e.Close(), "503")
+}
+
+func TestIPFIXExporter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	e, err := NewIPFIXExporter(conn.LocalAddr().String(), 0)
+	require.NoError(t, err)
+	defer e.Close()
+
+	readSets := func() (header []byte, sets map[uint16][]byte) {
+		buf := make([]byte, 65535)
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		msg := buf[:n]
+		require.GreaterOrEqual(t, len(msg), ipfixHeaderLen)
+		assert.Equal(t, uint16(ipfixVersion), binary.BigEndian.Uint16(msg[0:2]))
+		assert.Equal(t, uint16(n), binary.BigEndian.Uint16(msg[2:4]))
+		sets = map[uint16][]byte{}
+		for rest := msg[ipfixHeaderLen:]; len(rest) > 0; {
+			require.GreaterOrEqual(t, len(rest), 4)
+			setLen := binary.BigEndian.Uint16(rest[2:4])
+			require.GreaterOrEqual(t, len(rest), int(setLen))
+			sets[binary.BigEndian.Uint16(rest[0:2])] = rest[4:setLen]
+			rest = rest[setLen:]
+		}
+		return msg[:ipfixHeaderLen], sets
+	}
+
+	// the first message carries the templates
+	require.NoError(t, e.Export(newTestSample()))
+	header, sets := readSets()
+	assert.Equal(t, uint32(0), binary.BigEndian.Uint32(header[8:12]))
+	assert.Equal(t, uint32(1), binary.BigEndian.Uint32(header[12:16]))
+	require.Contains(t, sets, uint16(ipfixTemplateSetID))
+	template := sets[ipfixTemplateSetID]
+	assert.Equal(t, uint16(ipfixTemplateIDv4), binary.BigEndian.Uint16(template[0:2]))
+	assert.Equal(t, uint16(len(ipfixFieldsv4)), binary.BigEndian.Uint16(template[2:4]))
+	require.Contains(t, sets, uint16(ipfixTemplateIDv4))
+	record := sets[ipfixTemplateIDv4]
+	require.Len(t, record, 8+4+4+1+2+2+8+8+4+1)
+	assert.Equal(t, uint64(1700000000123), binary.BigEndian.Uint64(record[0:8]))
+	assert.Equal(t, net.ParseIP("10.244.0.3").To4(), net.IP(record[8:12]))
+	assert.Equal(t, net.ParseIP("10.244.1.4").To4(), net.IP(record[12:16]))
+	assert.Equal(t, uint8(6), record[16])
+	assert.Equal(t, uint16(8080), binary.BigEndian.Uint16(record[19:21]))
+	assert.Equal(t, uint64(74), binary.BigEndian.Uint64(record[21:29]))
+	assert.Equal(t, uint32(42), binary.BigEndian.Uint32(record[37:41]))
+	assert.Equal(t, uint8(ipfixFirewallEventDenied), record[41])
+
+	// the following messages only carry data records, with an increasing sequence number
+	sample := newTestSample()
+	sample.SrcIP, sample.DstIP = net.ParseIP("fd00:10:244::3"), net.ParseIP("fd00:10:244::4")
+	require.NoError(t, e.Export(sample))
+	header, sets = readSets()
+	assert.Equal(t, uint32(1), binary.BigEndian.Uint32(header[8:12]))
+	assert.NotContains(t, sets, uint16(ipfixTemplateSetID))
+	require.Contains(t, sets, uint16(ipfixTemplateIDv6))
+	assert.Equal(t, net.ParseIP("fd00:10:244::3"), net.IP(sets[ipfixTemplateIDv6][8:24]))
+}
+
+func TestIPFIXEnterpriseFields(t *testing.T) {
+	e := &IPFIXExporter{enterprise: 12345}
+	set, err := e.dataSet(newTestSample())
+	require.NoError(t, err)
+	// skip the set header and the fixed-length fields
+	rest := set[4+8+4+4+1+2+2+8+8+4+1:]
+	var values []string
+	for len(rest) > 0 {
+		length := int(rest[0])
+		values = append(values, string(rest[1:1+length]))
+		rest = rest[1+length:]
+	}
+	assert.Equal(t, []string{"NetworkPolicy", "foo", "deny-all", "drop", "Ingress",
+		"Dropped by network policy deny-all in namespace foo, direction Ingress"}, values)
+
+	assert.Len(t, e.fields(false), len(ipfixFieldsv4)+ipfixOVNKMessage)
+	assert.Len(t, ipfixFieldsv4, 10, "enterprise fields must not be appended to the shared IANA fields")
+}
@@ -0,0 +1,258 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
+)
+
+// IPFIX (RFC 7011) constants.
+const (
+	ipfixVersion          = 10
+	ipfixHeaderLen        = 16
+	ipfixTemplateSetID    = 2
+	ipfixVariableLength   = 0xffff
+	ipfixEnterpriseBit    = 0x8000
+	ipfixTemplateIDv4     = 256
+	ipfixTemplateIDv6     = 257
+	ipfixTemplateInterval = 30 * time.Second
+
+	// firewallEvent (IE 233) values, see the IANA IPFIX information elements registry.
+	ipfixFirewallEventIgnore  = 0
+	ipfixFirewallEventCreated = 1
+	ipfixFirewallEventDenied  = 3
+)
+
+// ipfixField is an information element of an IPFIX template.
+type ipfixField struct {
+	id     uint16
+	length uint16
+	// enterprise is the private enterprise number of enterprise-specific information elements, 0 for IANA ones.
+	enterprise uint32
+}
+
+var (
+	ipfixFieldsv4 = []ipfixField{
+		{id: 152, length: 8}, // flowStartMilliseconds
+		{id: 8, length: 4},   // sourceIPv4Address
+		{id: 12, length: 4},  // destinationIPv4Address
+		{id: 4, length: 1},   // protocolIdentifier
+		{id: 7, length: 2},   // sourceTransportPort
+		{id: 11, length: 2},  // destinationTransportPort
+		{id: 1, length: 8},   // octetDeltaCount
+		{id: 2, length: 8},   // packetDeltaCount
+		{id: 138, length: 4}, // observationPointId
+		{id: 233, length: 1}, // firewallEvent
+	}
+	ipfixFieldsv6 = []ipfixField{
+		{id: 152, length: 8}, // flowStartMilliseconds
+		{id: 27, length: 16}, // sourceIPv6Address
+		{id: 28, length: 16}, // destinationIPv6Address
+		{id: 4, length: 1},   // protocolIdentifier
+		{id: 7, length: 2},   // sourceTransportPort
+		{id: 11, length: 2},  // destinationTransportPort
+		{id: 1, length: 8},   // octetDeltaCount
+		{id: 2, length: 8},   // packetDeltaCount
+		{id: 138, length: 4}, // observationPointId
+		{id: 233, length: 1}, // firewallEvent
+	}
+)
+
+// Enterprise-specific information elements carrying the ovn-kubernetes enrichment, all of them variable-length
+// strings. They are only exported when an enterprise number is configured.
+const (
+	ipfixOVNKOwnerType = iota + 1
+	ipfixOVNKOwnerNamespace
+	ipfixOVNKOwnerName
+	ipfixOVNKAction
+	ipfixOVNKDirection
+	ipfixOVNKMessage
+)
+
+// IPFIXExporter sends every sample as an IPFIX data record over UDP, so that sampled packets can be consumed by
+// existing flow collectors. The IPFIX observation domain is the sample's observation domain ID.
+// Templates are sent with the first record of every observation domain and then every ipfixTemplateInterval,
+// as required for UDP transport.
+type IPFIXExporter struct {
+	conn       net.Conn
+	enterprise uint32
+
+	mu sync.Mutex
+	// sequence numbers and last template export time, per observation domain.
+	sequence     map[uint32]uint32
+	templateSent map[uint32]time.Time
+}
+
+// NewIPFIXExporter creates an IPFIXExporter that sends records to the given host:port UDP collector address.
+// If enterprise is not 0, the decoded event is exported in enterprise-specific fields with that private
+// enterprise number.
+func NewIPFIXExporter(collector string, enterprise uint32) (*IPFIXExporter, error) {
+	conn, err := net.Dial("udp", collector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to IPFIX collector %s: %w", collector, err)
+	}
+	return &IPFIXExporter{
+		conn:         conn,
+		enterprise:   enterprise,
+		sequence:     map[uint32]uint32{},
+		templateSent: map[uint32]time.Time{},
+	}, nil
+}
+
+func (e *IPFIXExporter) Export(sample *Sample) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := time.Now()
+	domain := sample.ObsDomainID
+	var sets [][]byte
+	if sent, ok := e.templateSent[domain]; !ok || now.Sub(sent) >= ipfixTemplateInterval {
+		sets = append(sets, e.templateSet())
+		e.templateSent[domain] = now
+	}
+	dataSet, err := e.dataSet(sample)
+	if err != nil {
+		return err
+	}
+	sets = append(sets, dataSet)
+	msg := e.message(domain, now, sets)
+	if _, err := e.conn.Write(msg); err != nil {
+		return fmt.Errorf("failed to send IPFIX message: %w", err)
+	}
+	e.sequence[domain]++
+	return nil
+}
+
+func (e *IPFIXExporter) Close() error {
+	return e.conn.Close()
+}
+
+// message builds an IPFIX message from the given sets.
+func (e *IPFIXExporter) message(domain uint32, exportTime time.Time, sets [][]byte) []byte {
+	length := ipfixHeaderLen
+	for _, set := range sets {
+		length += len(set)
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, length))
+	_ = binary.Write(buf, binary.BigEndian, uint16(ipfixVersion))
+	_ = binary.Write(buf, binary.BigEndian, uint16(length))
+	_ = binary.Write(buf, binary.BigEndian, uint32(exportTime.Unix()))
+	_ = binary.Write(buf, binary.BigEndian, e.sequence[domain])
+	_ = binary.Write(buf, binary.BigEndian, domain)
+	for _, set := range sets {
+		buf.Write(set)
+	}
+	return buf.Bytes()
+}
+
+// fields returns the template fields for the given IP version.
+func (e *IPFIXExporter) fields(ipv6 bool) []ipfixField {
+	fields := ipfixFieldsv4
+	if ipv6 {
+		fields = ipfixFieldsv6
+	}
+	if e.enterprise == 0 {
+		return fields
+	}
+	fields = append([]ipfixField{}, fields...)
+	for id := uint16(ipfixOVNKOwnerType); id <= ipfixOVNKMessage; id++ {
+		fields = append(fields, ipfixField{id: id, length: ipfixVariableLength, enterprise: e.enterprise})
+	}
+	return fields
+}
+
+// templateSet builds a template set with both the IPv4 and the IPv6 templates.
+func (e *IPFIXExporter) templateSet() []byte {
+	body := &bytes.Buffer{}
+	for _, template := range []struct {
+		id   uint16
+		ipv6 bool
+	}{{ipfixTemplateIDv4, false}, {ipfixTemplateIDv6, true}} {
+		fields := e.fields(template.ipv6)
+		_ = binary.Write(body, binary.BigEndian, template.id)
+		_ = binary.Write(body, binary.BigEndian, uint16(len(fields)))
+		for _, field := range fields {
+			if field.enterprise != 0 {
+				_ = binary.Write(body, binary.BigEndian, field.id|ipfixEnterpriseBit)
+				_ = binary.Write(body, binary.BigEndian, field.length)
+				_ = binary.Write(body, binary.BigEndian, field.enterprise)
+			} else {
+				_ = binary.Write(body, binary.BigEndian, field.id)
+				_ = binary.Write(body, binary.BigEndian, field.length)
+			}
+		}
+	}
+	return ipfixSet(ipfixTemplateSetID, body.Bytes())
+}
+
+// dataSet builds a data set with a single record for the given sample.
+func (e *IPFIXExporter) dataSet(sample *Sample) ([]byte, error) {
+	templateID := uint16(ipfixTemplateIDv4)
+	srcIP, dstIP := sample.SrcIP.To4(), sample.DstIP.To4()
+	if srcIP == nil || dstIP == nil {
+		templateID = ipfixTemplateIDv6
+		srcIP, dstIP = sample.SrcIP.To16(), sample.DstIP.To16()
+		if srcIP == nil || dstIP == nil {
+			return nil, fmt.Errorf("invalid sample IPs: src=%s, dst=%s", sample.SrcIP, sample.DstIP)
+		}
+	}
+	owner := model.GetEventOwner(sample.Event)
+	body := &bytes.Buffer{}
+	_ = binary.Write(body, binary.BigEndian, uint64(sample.Timestamp.UnixMilli()))
+	body.Write(srcIP)
+	body.Write(dstIP)
+	body.WriteByte(sample.Protocol)
+	_ = binary.Write(body, binary.BigEndian, sample.SrcPort)
+	_ = binary.Write(body, binary.BigEndian, sample.DstPort)
+	_ = binary.Write(body, binary.BigEndian, uint64(sample.Bytes))
+	_ = binary.Write(body, binary.BigEndian, uint64(1))
+	_ = binary.Write(body, binary.BigEndian, sample.ObsPointID)
+	body.WriteByte(ipfixFirewallEvent(owner.Action))
+	if e.enterprise != 0 {
+		for _, value := range []string{owner.Type, owner.Namespace, owner.Name, owner.Action, owner.Direction,
+			eventMessage(sample.Event)} {
+			writeIPFIXString(body, value)
+		}
+	}
+	return ipfixSet(templateID, body.Bytes()), nil
+}
+
+// ipfixSet prepends the set header to the given set body.
+func ipfixSet(id uint16, body []byte) []byte {
+	set := make([]byte, 4, 4+len(body))
+	binary.BigEndian.PutUint16(set[0:2], id)
+	binary.BigEndian.PutUint16(set[2:4], uint16(4+len(body)))
+	return append(set, body...)
+}
+
+// writeIPFIXString writes a variable-length string information element, see RFC 7011 section 7.
+func writeIPFIXString(buf *bytes.Buffer, value string) {
+	if len(value) > 0xfffe {
+		value = value[:0xfffe]
+	}
+	if len(value) < 255 {
+		buf.WriteByte(uint8(len(value)))
+	} else {
+		buf.WriteByte(255)
+		_ = binary.Write(buf, binary.BigEndian, uint16(len(value)))
+	}
+	buf.WriteString(value)
+}
+
+// ipfixFirewallEvent maps an ACL action to a firewallEvent value.
+func ipfixFirewallEvent(action string) uint8 {
+	switch action {
+	case "allow", "allow-related", "allow-stateless":
+		return ipfixFirewallEventCreated
+	case "drop", "reject":
+		return ipfixFirewallEventDenied
+	}
+	return ipfixFirewallEventIgnore
+}
@@ -0,0 +1,230 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
+)
+
+const (
+	// DefaultOTLPLogsEndpoint is the default OTLP/HTTP logs endpoint of a local OpenTelemetry collector.
+	DefaultOTLPLogsEndpoint = "http://localhost:4318/v1/logs"
+
+	otlpServiceName   = "ovnkube-observ"
+	otlpSeverityInfo  = 9
+	otlpBatchSize     = 100
+	otlpFlushInterval = time.Second
+	otlpTimeout       = 10 * time.Second
+)
+
+// OTLPLogsExporter sends samples as OpenTelemetry log records to a collector, using the OTLP/HTTP JSON encoding.
+// Records are batched and sent when the batch is full, every flush interval, and on Close.
+type OTLPLogsExporter struct {
+	endpoint      string
+	client        *http.Client
+	resource      otlpResource
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	records []otlpLogRecord
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// The following types are the subset of the OTLP JSON encoding of ExportLogsServiceRequest that is used here,
+// see https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding.
+
+type otlpExportLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	// 64-bit integers are encoded as decimal strings in OTLP JSON.
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+func otlpInt(key string, value int64) otlpKeyValue {
+	v := strconv.FormatInt(value, 10)
+	return otlpKeyValue{Key: key, Value: otlpAnyValue{IntValue: &v}}
+}
+
+// NewOTLPLogsExporter creates an OTLPLogsExporter that sends log records to the given OTLP/HTTP logs endpoint,
+// e.g. DefaultOTLPLogsEndpoint.
+func NewOTLPLogsExporter(endpoint string) *OTLPLogsExporter {
+	hostname, _ := os.Hostname()
+	e := &OTLPLogsExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: otlpTimeout},
+		resource: otlpResource{Attributes: []otlpKeyValue{
+			otlpString("service.name", otlpServiceName),
+			otlpString("host.name", hostname),
+		}},
+		batchSize:     otlpBatchSize,
+		flushInterval: otlpFlushInterval,
+		stopCh:        make(chan struct{}),
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e
+}
+
+func (e *OTLPLogsExporter) run() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stopCh:
+			return
+		case <-ticker.C:
+			if err := e.flush(); err != nil {
+				fmt.Printf("ERROR: failed to export samples to %s: %v\n", e.endpoint, err)
+			}
+		}
+	}
+}
+
+func (e *OTLPLogsExporter) Export(sample *Sample) error {
+	e.mu.Lock()
+	e.records = append(e.records, newOTLPLogRecord(sample))
+	full := len(e.records) >= e.batchSize
+	e.mu.Unlock()
+	if full {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *OTLPLogsExporter) Close() error {
+	close(e.stopCh)
+	e.wg.Wait()
+	return e.flush()
+}
+
+// flush sends all pending log records to the collector.
+func (e *OTLPLogsExporter) flush() error {
+	e.mu.Lock()
+	records := e.records
+	e.records = nil
+	e.mu.Unlock()
+	if len(records) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(otlpExportLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: e.resource,
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: otlpServiceName},
+				LogRecords: records,
+			}},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal log records: %w", err)
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send %d log records: %w", len(records), err)
+	}
+	defer resp.Body.Close()
+	// drain the body to allow connection reuse
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send %d log records: collector returned %s", len(records), resp.Status)
+	}
+	return nil
+}
+
+func newOTLPLogRecord(sample *Sample) otlpLogRecord {
+	body := eventMessage(sample.Event)
+	if body == "" {
+		body = fmt.Sprintf("src=%s, dst=%s", sample.SrcIP, sample.DstIP)
+	}
+	attributes := []otlpKeyValue{
+		otlpString("source.address", sample.SrcIP.String()),
+		otlpString("destination.address", sample.DstIP.String()),
+		otlpInt("ovn.observability.domain_id", int64(sample.ObsDomainID)),
+		otlpInt("ovn.observability.point_id", int64(sample.ObsPointID)),
+		otlpInt("network.packet.size", int64(sample.Bytes)),
+	}
+	if transport := ProtocolName(sample.Protocol); transport != "" {
+		attributes = append(attributes, otlpString("network.transport", transport))
+	}
+	if sample.SrcPort != 0 || sample.DstPort != 0 {
+		attributes = append(attributes,
+			otlpInt("source.port", int64(sample.SrcPort)),
+			otlpInt("destination.port", int64(sample.DstPort)))
+	}
+	owner := model.GetEventOwner(sample.Event)
+	for _, attr := range []struct{ key, value string }{
+		{"ovn.acl.action", owner.Action},
+		{"ovn.acl.owner_type", owner.Type},
+		{"ovn.acl.owner_namespace", owner.Namespace},
+		{"ovn.acl.owner_name", owner.Name},
+		{"ovn.acl.direction", owner.Direction},
+	} {
+		if attr.value != "" {
+			attributes = append(attributes, otlpString(attr.key, attr.value))
+		}
+	}
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	return otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(sample.Timestamp.UnixNano(), 10),
+		ObservedTimeUnixNano: now,
+		SeverityNumber:       otlpSeverityInfo,
+		SeverityText:         "INFO",
+		Body:                 otlpAnyValue{StringValue: &body},
+		Attributes:           attributes,
+	}
+}
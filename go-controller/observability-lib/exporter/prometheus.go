@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
+)
+
+const metricNamespace = "ovnkube_observ"
+
+var sampleLabels = []string{"action", "owner_type", "owner_namespace", "owner_name", "direction"}
+
+// PrometheusExporter counts samples and sampled bytes per ACL owner. As only a fraction of the packets is sampled,
+// the counters reflect the sampled traffic, not the total traffic.
+type PrometheusExporter struct {
+	samples *prometheus.CounterVec
+	bytes   *prometheus.CounterVec
+}
+
+// NewPrometheusExporter creates a PrometheusExporter and registers its metrics with the given registerer.
+func NewPrometheusExporter(registerer prometheus.Registerer) (*PrometheusExporter, error) {
+	e := &PrometheusExporter{
+		samples: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Name:      "samples_total",
+			Help:      "The number of decoded samples, by the owner of the ACL that generated them.",
+		}, sampleLabels),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Name:      "sampled_bytes_total",
+			Help:      "The original size in bytes of the sampled packets, by the owner of the ACL that generated them.",
+		}, sampleLabels),
+	}
+	for _, collector := range []prometheus.Collector{e.samples, e.bytes} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (e *PrometheusExporter) Export(sample *Sample) error {
+	owner := model.GetEventOwner(sample.Event)
+	labels := prometheus.Labels{
+		"action":          owner.Action,
+		"owner_type":      owner.Type,
+		"owner_namespace": owner.Namespace,
+		"owner_name":      owner.Name,
+		"direction":       owner.Direction,
+	}
+	e.samples.With(labels).Inc()
+	e.bytes.With(labels).Add(float64(sample.Bytes))
+	return nil
+}
+
+func (e *PrometheusExporter) Close() error {
+	return nil
+}
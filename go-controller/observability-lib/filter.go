@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package observability_lib
+
+import (
+	"strings"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/exporter"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
+)
+
+// DefaultNetworkName is used to filter samples of the default cluster network.
+const DefaultNetworkName = "default"
+
+// SampleFilter selects the samples that are printed, aggregated and exported. Empty fields match all samples,
+// a sample must match all the non-empty fields.
+type SampleFilter struct {
+	// Namespace matches the namespace of the source or destination pod, or of the sample owner,
+	// e.g. network policy namespace.
+	Namespace string
+	// Pod matches the name of the source or destination pod, either as "name" or "namespace/name".
+	Pod string
+	// Network matches the (C)UDN of the source or destination pod, UDN namespace+name are joined by "/",
+	// CUDN will just have a name. DefaultNetworkName matches the default network.
+	Network string
+	// Action matches the event action, e.g. the ACL action, "allow" matches all allow actions.
+	Action string
+	// OwnerType matches the sample owner type, e.g. "NetworkPolicy", case-insensitive.
+	OwnerType string
+	// Protocol matches the IP protocol name, e.g. "tcp", case-insensitive.
+	Protocol string
+	// Port matches the source or destination port.
+	Port uint16
+}
+
+// needsPods returns true if the filter uses the source and destination pods.
+func (f *SampleFilter) needsPods() bool {
+	return f.Namespace != "" || f.Pod != "" || f.Network != ""
+}
+
+// needsEvent returns true if the filter uses the decoded network event.
+func (f *SampleFilter) needsEvent() bool {
+	return f.Namespace != "" || f.Action != "" || f.OwnerType != ""
+}
+
+func (f *SampleFilter) matches(sample *exporter.Sample) bool {
+	owner := model.GetEventOwner(sample.Event)
+	pods := []*model.Pod{sample.SrcPod, sample.DstPod}
+
+	if f.Namespace != "" {
+		match := owner.Namespace == f.Namespace
+		for _, pod := range pods {
+			match = match || (pod != nil && pod.Namespace == f.Namespace)
+		}
+		if !match {
+			return false
+		}
+	}
+	if f.Pod != "" && !anyPod(pods, func(pod *model.Pod) bool {
+		return pod.Name == f.Pod || pod.String() == f.Pod
+	}) {
+		return false
+	}
+	if f.Network != "" && !anyPod(pods, func(pod *model.Pod) bool {
+		return pod.Network == f.Network || (pod.Network == "" && f.Network == DefaultNetworkName)
+	}) {
+		return false
+	}
+	if f.Action != "" && !matchesAction(owner.Action, f.Action) {
+		return false
+	}
+	if f.OwnerType != "" && !strings.EqualFold(owner.Type, f.OwnerType) {
+		return false
+	}
+	if f.Protocol != "" && !strings.EqualFold(exporter.ProtocolName(sample.Protocol), f.Protocol) {
+		return false
+	}
+	if f.Port != 0 && sample.SrcPort != f.Port && sample.DstPort != f.Port {
+		return false
+	}
+	return true
+}
+
+func anyPod(pods []*model.Pod, match func(pod *model.Pod) bool) bool {
+	for _, pod := range pods {
+		if pod != nil && match(pod) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAction returns true if the given ACL action matches the filter action.
+// "allow" matches allow-related and allow-stateless too.
+func matchesAction(action, filterAction string) bool {
+	if filterAction == "allow" {
+		return strings.HasPrefix(action, "allow")
+	}
+	return action == filterAction
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package observability_lib
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/exporter"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
+)
+
+func newTestSample(action string) *exporter.Sample {
+	return &exporter.Sample{
+		SrcIP:    net.ParseIP("10.244.0.3"),
+		DstIP:    net.ParseIP("10.244.1.4"),
+		Protocol: 6,
+		SrcPort:  34567,
+		DstPort:  8080,
+		Bytes:    100,
+		Event: &model.ACLEvent{
+			Action:    action,
+			Actor:     "NetworkPolicy",
+			Name:      "deny-all",
+			Namespace: "policy-ns",
+			Direction: "Ingress",
+		},
+		SrcPod: &model.Pod{Namespace: "client-ns", Name: "client"},
+		DstPod: &model.Pod{Namespace: "server-ns", Name: "server", Network: "server-ns/udn"},
+	}
+}
+
+func TestSampleFilter(t *testing.T) {
+	sample := newTestSample("allow-related")
+	tests := []struct {
+		name   string
+		filter SampleFilter
+		match  bool
+	}{
+		{"empty filter", SampleFilter{}, true},
+		{"source pod namespace", SampleFilter{Namespace: "client-ns"}, true},
+		{"owner namespace", SampleFilter{Namespace: "policy-ns"}, true},
+		{"other namespace", SampleFilter{Namespace: "other"}, false},
+		{"pod name", SampleFilter{Pod: "server"}, true},
+		{"pod namespaced name", SampleFilter{Pod: "client-ns/client"}, true},
+		{"pod in other namespace", SampleFilter{Pod: "server-ns/client"}, false},
+		{"udn", SampleFilter{Network: "server-ns/udn"}, true},
+		{"default network", SampleFilter{Network: DefaultNetworkName}, true},
+		{"other network", SampleFilter{Network: "other"}, false},
+		{"allow matches allow-related", SampleFilter{Action: "allow"}, true},
+		{"exact action", SampleFilter{Action: "allow-related"}, true},
+		{"other action", SampleFilter{Action: "drop"}, false},
+		{"owner type", SampleFilter{OwnerType: "networkpolicy"}, true},
+		{"other owner type", SampleFilter{OwnerType: "AdminNetworkPolicy"}, false},
+		{"protocol", SampleFilter{Protocol: "TCP"}, true},
+		{"other protocol", SampleFilter{Protocol: "udp"}, false},
+		{"destination port", SampleFilter{Port: 8080}, true},
+		{"source port", SampleFilter{Port: 34567}, true},
+		{"other port", SampleFilter{Port: 80}, false},
+		{"all fields match", SampleFilter{Namespace: "server-ns", Action: "allow", Protocol: "tcp", Port: 8080}, true},
+		{"one field doesn't match", SampleFilter{Namespace: "server-ns", Action: "allow", Protocol: "tcp", Port: 80}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.match, tt.filter.matches(sample))
+		})
+	}
+
+	// samples without enrichment only match filters that don't need it
+	sample = &exporter.Sample{SrcIP: net.ParseIP("10.244.0.3"), DstIP: net.ParseIP("10.244.1.4"), Protocol: 17}
+	assert.True(t, (&SampleFilter{Protocol: "udp"}).matches(sample))
+	assert.False(t, (&SampleFilter{Namespace: "client-ns"}).matches(sample))
+	assert.False(t, (&SampleFilter{Action: "drop"}).matches(sample))
+	assert.False(t, (&SampleFilter{Network: DefaultNetworkName}).matches(sample))
+}
@@ -5,6 +5,7 @@ package model
 
 import (
 	"fmt"
+	"strings"
 )
 
 const (
@@ -42,20 +43,25 @@ type ACLEvent struct {
 	Name      string
 	Namespace string
 	Direction string
+	// DryRunAction is set for ACLs of policies in dry-run mode to the action the ACL would have
+	// if the policy was enforced, Action is then the action that was actually applied.
+	DryRunAction string
 }
 
-func (e *ACLEvent) String() string {
-	var action string
-	switch e.Action {
+func aclActionString(aclAction string) string {
+	switch aclAction {
 	case aclActionAllow, aclActionAllowRelated, aclActionAllowStateless:
-		action = "Allowed"
+		return "Allowed"
 	case aclActionDrop:
-		action = "Dropped"
+		return "Dropped"
 	case aclActionPass:
-		action = "Delegated to network policy"
-	default:
-		action = "Action " + e.Action
+		return "Delegated to network policy"
 	}
+	return "Action " + aclAction
+}
+
+func (e *ACLEvent) String() string {
+	action := aclActionString(e.Action)
 	var msg string
 	switch e.Actor {
 	case adminNetworkPolicyOwnerType:
@@ -81,5 +87,52 @@ func (e *ACLEvent) String() string {
 	case udnIsolationOwnerType:
 		msg = fmt.Sprintf("UDN isolation of type %s", e.Name)
 	}
+	if e.DryRunAction != "" {
+		return fmt.Sprintf("Would have been %s by dry-run %s", strings.ToLower(aclActionString(e.DryRunAction)), msg)
+	}
 	return fmt.Sprintf("%s by %s", action, msg)
 }
+
+// EgressFirewallEvent is generated when pod egress traffic matches an egress firewall rule.
+type EgressFirewallEvent struct {
+	NetworkEvent
+	Action    string
+	Namespace string
+	// RuleIndex is the index of the matched rule in the egress firewall spec.egress list.
+	RuleIndex int
+}
+
+func (e *EgressFirewallEvent) String() string {
+	return fmt.Sprintf("%s by egress firewall rule %d in namespace %s", aclActionString(e.Action), e.RuleIndex, e.Namespace)
+}
+
+// EventOwner describes the object responsible for a network event.
+type EventOwner struct {
+	Action    string
+	Type      string
+	Namespace string
+	Name      string
+	Direction string
+}
+
+// GetEventOwner returns the owner of the given network event, unknown and nil events return an empty EventOwner.
+func GetEventOwner(event NetworkEvent) EventOwner {
+	switch e := event.(type) {
+	case *ACLEvent:
+		if e == nil {
+			return EventOwner{}
+		}
+		return EventOwner{Action: e.Action, Type: e.Actor, Namespace: e.Namespace, Name: e.Name, Direction: e.Direction}
+	case *EgressFirewallEvent:
+		if e == nil {
+			return EventOwner{}
+		}
+		return EventOwner{Action: e.Action, Type: egressFirewallOwnerType, Namespace: e.Namespace, Direction: "Egress"}
+	}
+	return EventOwner{}
+}
+
+// IsDenied returns true if the event owner action drops or rejects traffic.
+func (o EventOwner) IsDenied() bool {
+	return o.Action == aclActionDrop || o.Action == aclActionReject
+}
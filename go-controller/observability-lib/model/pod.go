@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+// Pod is the local pod that a sampled packet address belongs to.
+type Pod struct {
+	Namespace string
+	Name      string
+	// Network is the (C)UDN namespaced name of the pod interface, empty for the default network.
+	// UDN namespace+name are joined by "/", CUDN will just have a name.
+	Network string
+}
+
+func (p *Pod) String() string {
+	return p.Namespace + "/" + p.Name
+}
@@ -14,6 +14,7 @@ import (
 	"os"
 	"strings"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"github.com/google/gopacket"
@@ -22,6 +23,7 @@ import (
 	"github.com/vishvananda/netlink/nl"
 	"golang.org/x/sys/unix"
 
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/exporter"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/sampledecoder"
 )
 
@@ -60,6 +62,11 @@ type SampleReader struct {
 
 	decoder   *sampledecoder.SampleDecoder
 	cookieStr []string
+	exporters []exporter.Exporter
+
+	filter            *SampleFilter
+	aggregator        *sampleAggregator
+	aggregateInterval time.Duration
 }
 
 func NewSampleReader(enableDecoder, logCookie, printFullPacket, addOVSCollector bool, srcIP, dstIP, outputFile string) *SampleReader {
@@ -78,7 +85,38 @@ func NewSampleReader(enableDecoder, logCookie, printFullPacket, addOVSCollector
 	return r
 }
 
+// AddExporter adds an exporter that every sample passing the filters is sent to, in addition to being printed.
+// Exporters are closed when ReadSamples returns.
+func (r *SampleReader) AddExporter(e exporter.Exporter) {
+	r.exporters = append(r.exporters, e)
+}
+
+// SetFilter sets additional filters for the samples, on top of the source and destination IP filters.
+func (r *SampleReader) SetFilter(filter *SampleFilter) {
+	r.filter = filter
+}
+
+// needsPods returns true if the source and destination pods of the samples are used by the filter,
+// the exporters or the aggregation.
+func (r *SampleReader) needsPods() bool {
+	return (r.filter != nil && r.filter.needsPods()) || len(r.exporters) > 0 || r.aggregator != nil
+}
+
+// SetAggregation replaces printing every sample with printing the topN talkers and denied flows
+// over the given sliding window every interval.
+func (r *SampleReader) SetAggregation(window, interval time.Duration, topN int) {
+	r.aggregator = newSampleAggregator(window, topN)
+	r.aggregateInterval = interval
+}
+
 func (r *SampleReader) ReadSamples(ctx context.Context) error {
+	defer func() {
+		for _, e := range r.exporters {
+			if err := e.Close(); err != nil {
+				fmt.Printf("ERROR: failed to close exporter: %v\n", err)
+			}
+		}
+	}()
 	if r.enableDecoder {
 		var err error
 		// currently only local nbdb connection is supported.
@@ -96,6 +134,9 @@ func (r *SampleReader) ReadSamples(ctx context.Context) error {
 			}
 		}
 	}
+	if r.filter != nil && (r.filter.needsPods() || r.filter.needsEvent()) && !r.enableDecoder {
+		return fmt.Errorf("namespace, pod, network, action and owner type filters require enrichment")
+	}
 	var writer io.Writer
 	if r.outputFile != "" {
 		file, err := os.Create(r.outputFile)
@@ -144,6 +185,23 @@ func (r *SampleReader) ReadSamples(ctx context.Context) error {
 		sock.Close()
 	}()
 
+	if r.aggregator != nil {
+		go func() {
+			ticker := time.NewTicker(r.aggregateInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case now := <-ticker.C:
+					for _, line := range r.aggregator.report(now) {
+						printlnFunc(line)
+					}
+				}
+			}
+		}()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -183,8 +241,17 @@ var hostEndian = getHostEndian()
 func (r *SampleReader) parseMsg(msgs []syscall.NetlinkMessage, printlnFunc func(a ...any)) error {
 	for _, msg := range msgs {
 		var packetStr, sampleStr string
+		sample := &exporter.Sample{}
 		data := msg.Data[nl.SizeofGenlmsg:]
 		for attr := range nl.ParseAttributes(data) {
+			if attr.Type == PSAMPLE_ATTR_ORIGSIZE && len(attr.Value) == 4 {
+				sample.Bytes = hostEndian.Uint32(attr.Value)
+			}
+			if attr.Type == PSAMPLE_ATTR_TIMESTAMP && len(attr.Value) == 8 {
+				if ts := hostEndian.Uint64(attr.Value); ts != 0 {
+					sample.Timestamp = time.Unix(0, int64(ts))
+				}
+			}
 			if r.logCookie && attr.Type == PSAMPLE_ATTR_SAMPLE_GROUP {
 				if uint64(len(attr.Value)) == 4 {
 					g := uint32(0)
@@ -203,6 +270,7 @@ func (r *SampleReader) parseMsg(msgs []syscall.NetlinkMessage, printlnFunc func(
 					if err != nil {
 						return err
 					}
+					sample.ObsDomainID, sample.ObsPointID = c.ObsDomainID, c.ObsPointID
 					if r.logCookie {
 						r.cookieStr[1] = fmt.Sprintf("obs_domain=%v, obs_point=%v",
 							c.ObsDomainID, c.ObsPointID)
@@ -213,6 +281,7 @@ func (r *SampleReader) parseMsg(msgs []syscall.NetlinkMessage, printlnFunc func(
 							sampleStr = fmt.Sprintf("decoding failed: %v", err)
 						} else {
 							sampleStr = fmt.Sprintf("OVN-K message: %s", decoded.String())
+							sample.Event = decoded
 						}
 					}
 				}
@@ -232,8 +301,33 @@ func (r *SampleReader) parseMsg(msgs []syscall.NetlinkMessage, printlnFunc func(
 				if r.dstIP != "" && r.dstIP != networkLayer.Dst().String() {
 					return nil
 				}
+				setPacketInfo(sample, packet)
 			}
 		}
+		if r.decoder != nil && sample.SrcIP != nil && r.needsPods() {
+			var err error
+			if sample.SrcPod, err = r.decoder.GetPodByIP(sample.SrcIP.String()); err != nil {
+				return err
+			}
+			if sample.DstPod, err = r.decoder.GetPodByIP(sample.DstIP.String()); err != nil {
+				return err
+			}
+		}
+		if r.filter != nil && !r.filter.matches(sample) {
+			continue
+		}
+		if sample.Timestamp.IsZero() {
+			sample.Timestamp = time.Now()
+		}
+		for _, e := range r.exporters {
+			if err := e.Export(sample); err != nil {
+				printlnFunc("ERROR: export failed:", err)
+			}
+		}
+		if r.aggregator != nil {
+			r.aggregator.add(sample, time.Now())
+			continue
+		}
 		if r.logCookie {
 			printlnFunc(strings.Join(r.cookieStr, ", "))
 		}
@@ -244,3 +338,21 @@ func (r *SampleReader) parseMsg(msgs []syscall.NetlinkMessage, printlnFunc func(
 	}
 	return nil
 }
+
+// setPacketInfo sets the addresses, protocol and ports of the given packet in the sample.
+func setPacketInfo(sample *exporter.Sample, packet gopacket.Packet) {
+	switch ip := packet.NetworkLayer().(type) {
+	case *layers.IPv4:
+		sample.SrcIP, sample.DstIP, sample.Protocol = ip.SrcIP, ip.DstIP, uint8(ip.Protocol)
+	case *layers.IPv6:
+		sample.SrcIP, sample.DstIP, sample.Protocol = ip.SrcIP, ip.DstIP, uint8(ip.NextHeader)
+	}
+	switch transport := packet.TransportLayer().(type) {
+	case *layers.TCP:
+		sample.SrcPort, sample.DstPort = uint16(transport.SrcPort), uint16(transport.DstPort)
+	case *layers.UDP:
+		sample.SrcPort, sample.DstPort = uint16(transport.SrcPort), uint16(transport.DstPort)
+	case *layers.SCTP:
+		sample.SrcPort, sample.DstPort = uint16(transport.SrcPort), uint16(transport.DstPort)
+	}
+}
@@ -45,6 +45,7 @@ func NewNBClientWithConfig(ctx context.Context, cfg dbConfig) (client.Client, er
 		c.NewMonitor(
 			client.WithTable(&nbdb.ACL{}),
 			client.WithTable(&nbdb.Sample{}),
+			client.WithTable(&nbdb.LogicalSwitchPort{}),
 		),
 	)
 
@@ -8,15 +8,20 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/ovn-kubernetes/libovsdb/cache"
 	"github.com/ovn-kubernetes/libovsdb/client"
+	libovsdbmodel "github.com/ovn-kubernetes/libovsdb/model"
 
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/ovsdb"
 	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/observability"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
 )
 
@@ -24,6 +29,10 @@ type SampleDecoder struct {
 	nbClient          client.Client
 	ovsdbClient       client.Client
 	cleanupCollectors []int
+
+	// podsByIP indexes the pods with a logical switch port in the local nbdb by IP address
+	podsLock sync.RWMutex
+	podsByIP map[string]*model.Pod
 }
 
 type dbConfig struct {
@@ -85,6 +94,9 @@ func NewSampleDecoderWithDefaultCollector(ctx context.Context, nbdbSocketPath st
 		nbClient:    nbClient,
 		ovsdbClient: ovsdbClient,
 	}
+	if err = decoder.watchPods(); err != nil {
+		return nil, err
+	}
 	err = decoder.AddCollector(observability.DefaultObservabilityCollectorSetID, groupID, ownerName)
 	if err != nil {
 		return nil, err
@@ -99,9 +111,13 @@ func NewSampleDecoder(ctx context.Context, nbdbSocketPath string) (*SampleDecode
 	if err != nil {
 		return nil, err
 	}
-	return &SampleDecoder{
+	decoder := &SampleDecoder{
 		nbClient: nbClient,
-	}, nil
+	}
+	if err = decoder.watchPods(); err != nil {
+		return nil, err
+	}
+	return decoder, nil
 }
 
 func (d *SampleDecoder) Shutdown() {
@@ -125,58 +141,57 @@ func findACLBySample(nbClient client.Client, acl *nbdb.ACL) ([]*nbdb.ACL, error)
 }
 
 func (d *SampleDecoder) DecodeCookieIDs(obsDomainID, obsPointID uint32) (model.NetworkEvent, error) {
+	switch getObservAppID(obsDomainID) {
+	case observability.ACLNewTrafficSamplingID, observability.ACLEstTrafficSamplingID:
+		acl, err := d.findACL(getObservAppID(obsDomainID), obsPointID)
+		if err != nil {
+			return nil, err
+		}
+		var event model.NetworkEvent
+		if acl.ExternalIDs[libovsdbops.OwnerTypeKey.String()] == libovsdbops.EgressFirewallOwnerType {
+			event, err = newEgressFirewallEvent(acl)
+		} else {
+			event, err = newACLEvent(acl)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to build ACL network event: %w", err)
+		}
+		return event, nil
+	}
+	return nil, fmt.Errorf("unknown app ID: %d", getObservAppID(obsDomainID))
+}
+
+// findACL finds the ACL with the given sample ID for the new or established traffic app.
+func (d *SampleDecoder) findACL(appID uint8, obsPointID uint32) (*nbdb.ACL, error) {
 	// Find sample using obsPointID
 	sample, err := libovsdbops.FindSample(d.nbClient, int(obsPointID))
 	if err != nil || sample == nil {
 		return nil, fmt.Errorf("find sample failed: %w", err)
 	}
-	// find db object using observ application ID
 	// Since ACL is indexed both by sample_new and sample_est, when searching by one of them,
 	// we need to make sure the other one will not match.
 	// nil is a valid index value, therefore we have to use non-existing UUID.
 	wrongUUID := "wrongUUID"
-	var dbObj interface{}
-	switch getObservAppID(obsDomainID) {
-	case observability.ACLNewTrafficSamplingID:
-		acls, err := findACLBySample(d.nbClient, &nbdb.ACL{SampleNew: &sample.UUID, SampleEst: &wrongUUID})
-		if err != nil {
-			return nil, fmt.Errorf("find acl for sample failed: %w", err)
-		}
-		if len(acls) != 1 {
-			return nil, fmt.Errorf("expected 1 ACL, got %d", len(acls))
-		}
-		dbObj = acls[0]
-	case observability.ACLEstTrafficSamplingID:
-		acls, err := findACLBySample(d.nbClient, &nbdb.ACL{SampleNew: &wrongUUID, SampleEst: &sample.UUID})
-		if err != nil {
-			return nil, fmt.Errorf("find acl for sample failed: %w", err)
-		}
-		if len(acls) != 1 {
-			return nil, fmt.Errorf("expected 1 ACL, got %d", len(acls))
-		}
-		dbObj = acls[0]
-	default:
-		return nil, fmt.Errorf("unknown app ID: %d", getObservAppID(obsDomainID))
-	}
-	var event model.NetworkEvent
-	switch o := dbObj.(type) {
-	case *nbdb.ACL:
-		event, err = newACLEvent(o)
-		if err != nil {
-			return nil, fmt.Errorf("failed to build ACL network event: %w", err)
-		}
+	acl := &nbdb.ACL{SampleNew: &sample.UUID, SampleEst: &wrongUUID}
+	if appID == observability.ACLEstTrafficSamplingID {
+		acl = &nbdb.ACL{SampleNew: &wrongUUID, SampleEst: &sample.UUID}
 	}
-	if event == nil {
-		return nil, fmt.Errorf("failed to build network event for db object %v", dbObj)
+	acls, err := findACLBySample(d.nbClient, acl)
+	if err != nil {
+		return nil, fmt.Errorf("find acl for sample failed: %w", err)
+	}
+	if len(acls) != 1 {
+		return nil, fmt.Errorf("expected 1 ACL, got %d", len(acls))
 	}
-	return event, nil
+	return acls[0], nil
 }
 
 func newACLEvent(o *nbdb.ACL) (*model.ACLEvent, error) {
 	actor := o.ExternalIDs[libovsdbops.OwnerTypeKey.String()]
 	event := model.ACLEvent{
-		Action: o.Action,
-		Actor:  actor,
+		Action:       o.Action,
+		Actor:        actor,
+		DryRunAction: o.ExternalIDs[types.ACLDryRunActionExternalID],
 	}
 	switch actor {
 	case libovsdbops.NetworkPolicyOwnerType:
@@ -208,6 +223,18 @@ func newACLEvent(o *nbdb.ACL) (*model.ACLEvent, error) {
 	return &event, nil
 }
 
+func newEgressFirewallEvent(o *nbdb.ACL) (*model.EgressFirewallEvent, error) {
+	ruleIndex, err := strconv.Atoi(o.ExternalIDs[libovsdbops.RuleIndex.String()])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse egress firewall rule index: %w", err)
+	}
+	return &model.EgressFirewallEvent{
+		Action:    o.Action,
+		Namespace: o.ExternalIDs[libovsdbops.ObjectNameKey.String()],
+		RuleIndex: ruleIndex,
+	}, nil
+}
+
 func (d *SampleDecoder) DecodeCookieBytes(cookie []byte) (model.NetworkEvent, error) {
 	if uint64(len(cookie)) != CookieSize {
 		return nil, fmt.Errorf("invalid cookie size: %d", len(cookie))
@@ -332,3 +359,101 @@ func (d *SampleDecoder) GetInterfaceUDNs() (map[string]string, error) {
 	}
 	return res, nil
 }
+
+// GetPodByIP returns the local pod that has the given IP address assigned, or nil if there is none.
+// Only pods with a logical switch port in the local nbdb are found, that is, with interconnect only
+// pods of the local node.
+func (d *SampleDecoder) GetPodByIP(ip string) (*model.Pod, error) {
+	d.podsLock.RLock()
+	defer d.podsLock.RUnlock()
+	return d.podsByIP[ip], nil
+}
+
+// watchPods indexes the pods of the logical switch ports in the nbdb cache by IP address and keeps
+// the index up to date with the logical switch port changes.
+func (d *SampleDecoder) watchPods() error {
+	d.podsByIP = map[string]*model.Pod{}
+	// the handler is added before listing the existing ports so that no change is missed,
+	// indexing the same port twice is harmless.
+	d.nbClient.Cache().AddEventHandler(&cache.EventHandlerFuncs{
+		AddFunc: func(table string, row libovsdbmodel.Model) {
+			if lsp, ok := row.(*nbdb.LogicalSwitchPort); ok {
+				d.indexPod(nil, lsp)
+			}
+		},
+		UpdateFunc: func(table string, old, new libovsdbmodel.Model) {
+			oldLSP, oldOK := old.(*nbdb.LogicalSwitchPort)
+			newLSP, newOK := new.(*nbdb.LogicalSwitchPort)
+			if oldOK && newOK {
+				d.indexPod(oldLSP, newLSP)
+			}
+		},
+		DeleteFunc: func(table string, row libovsdbmodel.Model) {
+			if lsp, ok := row.(*nbdb.LogicalSwitchPort); ok {
+				d.indexPod(lsp, nil)
+			}
+		},
+	})
+	lsps := []*nbdb.LogicalSwitchPort{}
+	err := d.nbClient.WhereCache(func(item *nbdb.LogicalSwitchPort) bool {
+		return item.ExternalIDs["pod"] == "true"
+	}).List(context.Background(), &lsps)
+	if err != nil {
+		return fmt.Errorf("failed listing logical switch ports: %w", err)
+	}
+	for _, lsp := range lsps {
+		d.indexPod(nil, lsp)
+	}
+	return nil
+}
+
+// indexPod replaces the IP addresses of the old logical switch port in the pod index with the ones of
+// the new logical switch port. Either can be nil, ports that don't belong to pods are ignored.
+func (d *SampleDecoder) indexPod(oldLSP, newLSP *nbdb.LogicalSwitchPort) {
+	d.podsLock.Lock()
+	defer d.podsLock.Unlock()
+	if oldLSP != nil && oldLSP.ExternalIDs["pod"] == "true" {
+		oldPod := newPod(oldLSP)
+		for _, ip := range lspIPs(oldLSP) {
+			// the IP may have been reused by another pod already
+			if pod := d.podsByIP[ip]; pod != nil && *pod == *oldPod {
+				delete(d.podsByIP, ip)
+			}
+		}
+	}
+	if newLSP != nil && newLSP.ExternalIDs["pod"] == "true" {
+		pod := newPod(newLSP)
+		for _, ip := range lspIPs(newLSP) {
+			d.podsByIP[ip] = pod
+		}
+	}
+}
+
+// lspIPs returns the IP addresses of the logical switch port.
+// LSP addresses are "MAC IP1 IP2...", see base_network_controller_pods.go.
+func lspIPs(lsp *nbdb.LogicalSwitchPort) []string {
+	var ips []string
+	for _, address := range lsp.Addresses {
+		fields := strings.Fields(address)
+		if len(fields) < 2 {
+			continue
+		}
+		ips = append(ips, fields[1:]...)
+	}
+	return ips
+}
+
+func newPod(lsp *nbdb.LogicalSwitchPort) *model.Pod {
+	namespace := lsp.ExternalIDs["namespace"]
+	// The port name is namespace_name, prefixed with the network name for UDNs. Neither namespace
+	// nor pod name can contain "_".
+	name := lsp.Name[strings.LastIndex(lsp.Name, "_")+1:]
+	pod := &model.Pod{
+		Namespace: namespace,
+		Name:      name,
+	}
+	if network := lsp.ExternalIDs[types.NetworkExternalID]; network != "" {
+		pod.Network = networkNameToUDNNamespacedName(network)
+	}
+	return pod
+}
@@ -5,13 +5,17 @@ package sampledecoder
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/observability-lib/model"
 	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
 	libovsdbutil "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/util"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	libovsdbtest "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 )
 
 func TestCreateOrUpdateACL(t *testing.T) {
@@ -68,4 +72,120 @@ func TestCreateOrUpdateACL(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "Allowed by default allow from local node policy, direction Ingress", event.String())
 	assert.Equal(t, "Ingress", event.Direction)
+
+	event, err = newACLEvent(&nbdb.ACL{
+		Action: nbdb.ACLActionPass,
+		ExternalIDs: map[string]string{
+			libovsdbops.OwnerTypeKey.String():       libovsdbops.AdminNetworkPolicyOwnerType,
+			libovsdbops.ObjectNameKey.String():      "foo",
+			libovsdbops.PolicyDirectionKey.String(): string(libovsdbutil.ACLEgress),
+			types.ACLDryRunActionExternalID:         nbdb.ACLActionDrop,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Would have been dropped by dry-run admin network policy foo, direction Egress", event.String())
+	assert.Equal(t, nbdb.ACLActionPass, event.Action)
+
+	event, err = newACLEvent(&nbdb.ACL{
+		Action: nbdb.ACLActionAllowRelated,
+		ExternalIDs: map[string]string{
+			libovsdbops.OwnerTypeKey.String():       libovsdbops.NetpolNamespaceOwnerType,
+			libovsdbops.ObjectNameKey.String():      "foo",
+			libovsdbops.PolicyDirectionKey.String(): string(libovsdbutil.ACLIngress),
+			types.ACLDryRunActionExternalID:         nbdb.ACLActionDrop,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Would have been dropped by dry-run network policies isolation in namespace foo, direction Ingress", event.String())
+}
+
+func TestNewEgressFirewallEvent(t *testing.T) {
+	event, err := newEgressFirewallEvent(&nbdb.ACL{
+		Action: nbdb.ACLActionDrop,
+		ExternalIDs: map[string]string{
+			libovsdbops.OwnerTypeKey.String():  libovsdbops.EgressFirewallOwnerType,
+			libovsdbops.ObjectNameKey.String(): "foo",
+			libovsdbops.RuleIndex.String():     "2",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Dropped by egress firewall rule 2 in namespace foo", event.String())
+	assert.Equal(t, model.EventOwner{Action: nbdb.ACLActionDrop, Type: libovsdbops.EgressFirewallOwnerType,
+		Namespace: "foo", Direction: "Egress"}, model.GetEventOwner(event))
+	assert.True(t, model.GetEventOwner(event).IsDenied())
+
+	_, err = newEgressFirewallEvent(&nbdb.ACL{
+		Action: nbdb.ACLActionDrop,
+		ExternalIDs: map[string]string{
+			libovsdbops.OwnerTypeKey.String():  libovsdbops.EgressFirewallOwnerType,
+			libovsdbops.ObjectNameKey.String(): "foo",
+		},
+	})
+	require.ErrorContains(t, err, "failed to parse egress firewall rule index")
+}
+
+func TestNewPod(t *testing.T) {
+	lsp := &nbdb.LogicalSwitchPort{
+		Name:        "foo_bar-7d9f",
+		Addresses:   []string{"0a:58:0a:f4:00:03 10.244.0.3 fd00:10:244::3"},
+		ExternalIDs: map[string]string{"namespace": "foo", "pod": "true"},
+	}
+	assert.Equal(t, &model.Pod{Namespace: "foo", Name: "bar-7d9f"}, newPod(lsp))
+	assert.Equal(t, []string{"10.244.0.3", "fd00:10:244::3"}, lspIPs(lsp))
+	assert.Empty(t, lspIPs(&nbdb.LogicalSwitchPort{Addresses: []string{"dynamic"}}))
+
+	lsp = &nbdb.LogicalSwitchPort{
+		Name: "ns1.udn_foo_bar",
+		ExternalIDs: map[string]string{
+			"namespace":             "foo",
+			"pod":                   "true",
+			types.NetworkExternalID: "ns1_udn",
+		},
+	}
+	assert.Equal(t, &model.Pod{Namespace: "foo", Name: "bar", Network: "ns1/udn"}, newPod(lsp))
+}
+
+func TestGetPodByIP(t *testing.T) {
+	podLSP := func(name, namespace string, addresses ...string) *nbdb.LogicalSwitchPort {
+		return &nbdb.LogicalSwitchPort{
+			UUID:        name + "-UUID",
+			Name:        namespace + "_" + name,
+			Addresses:   addresses,
+			ExternalIDs: map[string]string{"namespace": namespace, "pod": "true"},
+		}
+	}
+	nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(libovsdbtest.TestSetup{
+		NBData: []libovsdbtest.TestData{
+			podLSP("foo", "ns1", "0a:58:0a:f4:00:03 10.244.0.3 fd00:10:244::3"),
+			&nbdb.LogicalSwitchPort{UUID: "mgmt-UUID", Name: "k8s-node1", Addresses: []string{"0a:58:0a:f4:00:02 10.244.0.2"}},
+			&nbdb.LogicalSwitch{UUID: "node1-UUID", Name: "node1", Ports: []string{"foo-UUID", "mgmt-UUID"}},
+		},
+	}, nil)
+	require.NoError(t, err)
+	t.Cleanup(cleanup.Cleanup)
+
+	d := &SampleDecoder{nbClient: nbClient}
+	require.NoError(t, d.watchPods())
+	getPod := func(ip string) *model.Pod {
+		pod, err := d.GetPodByIP(ip)
+		require.NoError(t, err)
+		return pod
+	}
+	fooPod := &model.Pod{Namespace: "ns1", Name: "foo"}
+	assert.Equal(t, fooPod, getPod("10.244.0.3"))
+	assert.Equal(t, fooPod, getPod("fd00:10:244::3"))
+	// ports of other types are not indexed
+	assert.Nil(t, getPod("10.244.0.2"))
+
+	// the IP of a deleted pod is reused by a new pod before the old port is deleted
+	nodeSwitch := &nbdb.LogicalSwitch{Name: "node1"}
+	barLSP := podLSP("bar", "ns2", "0a:58:0a:f4:00:03 10.244.0.3")
+	barLSP.UUID = ""
+	require.NoError(t, libovsdbops.CreateOrUpdateLogicalSwitchPortsOnSwitch(nbClient, nodeSwitch, barLSP))
+	barPod := &model.Pod{Namespace: "ns2", Name: "bar"}
+	assert.Eventually(t, func() bool { return assert.ObjectsAreEqual(barPod, getPod("10.244.0.3")) }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, libovsdbops.DeleteLogicalSwitchPorts(nbClient, nodeSwitch, &nbdb.LogicalSwitchPort{Name: "ns1_foo"}))
+	assert.Eventually(t, func() bool { return getPod("fd00:10:244::3") == nil }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, barPod, getPod("10.244.0.3"))
 }
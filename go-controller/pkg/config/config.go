@@ -164,6 +164,7 @@ var (
 		EgressIPReachabiltyTotalTimeout: 1,
 		AdvertisedUDNIsolationMode:      AdvertisedUDNIsolationModeStrict,
 		UDNDeletionGracePeriod:          120 * time.Second,
+		EgressFirewallDNSLearningPeers:  "kube-system/k8s-app=kube-dns",
 	}
 
 	// OvnNorth holds northbound OVN database client and server authentication and location details
@@ -448,6 +449,7 @@ type KubernetesConfig struct {
 	ServiceCIDRs            []*net.IPNet
 	OVNConfigNamespace      string `gcfg:"ovn-config-namespace"`
 	OVNEmptyLbEvents        bool   `gcfg:"ovn-empty-lb-events"`
+	OVNServiceHealthChecks  bool   `gcfg:"ovn-service-health-checks"`
 	RawNoHostSubnetNodes    string `gcfg:"no-hostsubnet-nodes"`
 	NoHostSubnetNodes       labels.Selector
 	HostNetworkNamespace    string `gcfg:"host-network-namespace"`
@@ -478,6 +480,8 @@ type MetricsConfig struct {
 	// configuration duration and optionally, its application to all nodes
 	EnableConfigDuration bool `gcfg:"enable-config-duration"`
 	EnableScaleMetrics   bool `gcfg:"enable-scale-metrics"`
+	// EnableServiceMetrics enables the ovnkube-node per-service connection, packet and byte counters
+	EnableServiceMetrics bool `gcfg:"enable-service-metrics"`
 }
 
 // OVNKubernetesFeatureConfig holds OVN-Kubernetes feature enhancement config file parameters and command-line overrides
@@ -514,6 +518,37 @@ type OVNKubernetesFeatureConfig struct {
 	// UDNDeletionGracePeriod specified in number of seconds to wait before garbage collecting a UDN. Applies
 	// only when Dynamic UDN Allocation is enabled.
 	UDNDeletionGracePeriod time.Duration `gcfg:"udn-deletion-grace-period"`
+	// EgressFirewallRuleHitsInterval is how often egress firewall rule hit counts are collected from the local
+	// OVS flows and reported in the EgressFirewall status. 0 disables rule hit counting.
+	EgressFirewallRuleHitsInterval time.Duration `gcfg:"egress-firewall-rule-hits-interval"`
+	// EgressFirewallDNSLearningAddress is the tcp://host:port or unix:///path address where the dnstap plugin of
+	// the cluster DNS sends the DNS responses sent to pods, used to learn the DNS names matching wildcard
+	// EgressFirewall DNS names when DNSNameResolver is not enabled. Wildcard DNS names are only supported when set.
+	EgressFirewallDNSLearningAddress string `gcfg:"egress-firewall-dns-learning-address"`
+	// EgressFirewallDNSLearningPeers is the namespace/label-selector of the cluster DNS pods allowed to send
+	// dnstap messages to a tcp EgressFirewallDNSLearningAddress, connections from other IPs are rejected.
+	EgressFirewallDNSLearningPeers string `gcfg:"egress-firewall-dns-learning-peers"`
+	// PolicyRuleHitsInterval is how often NetworkPolicy, AdminNetworkPolicy and BaselineAdminNetworkPolicy rule
+	// hit counts are collected from the local OVS flows, exported as metrics and, for admin network policies,
+	// summarized in the status. 0 disables policy rule hit counting.
+	PolicyRuleHitsInterval time.Duration `gcfg:"policy-rule-hits-interval"`
+	// MulticastIdleTimeout is the number of seconds after which a multicast group membership learnt by
+	// IGMP/MLD snooping on the node switches expires. 0 keeps the OVN default.
+	MulticastIdleTimeout int `gcfg:"multicast-idle-timeout"`
+	// MulticastQueryInterval is the number of seconds between IGMP/MLD general queries sent by the node
+	// switches. 0 keeps the OVN default (half of the idle timeout).
+	MulticastQueryInterval int `gcfg:"multicast-query-interval"`
+	// MulticastQueryMaxResponse is the max response time in seconds advertised in the IGMP/MLD queries sent
+	// by the node switches. 0 keeps the OVN default.
+	MulticastQueryMaxResponse int `gcfg:"multicast-query-max-response"`
+	// MulticastTableSize is the max number of multicast groups learnt by each node switch. 0 keeps the OVN default.
+	MulticastTableSize int `gcfg:"multicast-table-size"`
+	// MulticastFloodUnregistered floods multicast traffic to groups without registered receivers to all the
+	// node switch ports, instead of only to the router.
+	MulticastFloodUnregistered bool `gcfg:"multicast-flood-unregistered"`
+	// DisableMulticastQuerier disables the IGMP/MLD querier of the node switches, for networks that already
+	// have a querier.
+	DisableMulticastQuerier bool `gcfg:"disable-multicast-querier"`
 }
 
 // GatewayMode holds the node gateway mode
@@ -1338,6 +1373,71 @@ var OVNK8sFeatureFlags = []cli.Flag{
 		Destination: &cliConfig.OVNKubernetesFeature.UDNDeletionGracePeriod,
 		Value:       OVNKubernetesFeature.UDNDeletionGracePeriod,
 	},
+	&cli.DurationFlag{
+		Name: "egress-firewall-rule-hits-interval",
+		Usage: "Interval to collect egress firewall rule hit counts from the local OVS flows and report them in " +
+			"the EgressFirewall status. Disabled when 0.",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressFirewallRuleHitsInterval,
+		Value:       OVNKubernetesFeature.EgressFirewallRuleHitsInterval,
+	},
+	&cli.StringFlag{
+		Name: "egress-firewall-dns-learning-address",
+		Usage: "Address (tcp://host:port or unix:///path) that receives the dnstap messages of the cluster DNS, " +
+			"to support wildcard EgressFirewall DNS names without DNSNameResolver.",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressFirewallDNSLearningAddress,
+		Value:       OVNKubernetesFeature.EgressFirewallDNSLearningAddress,
+	},
+	&cli.StringFlag{
+		Name: "egress-firewall-dns-learning-peers",
+		Usage: "Namespace and label selector (namespace/selector) of the cluster DNS pods allowed to send dnstap " +
+			"messages to a tcp egress-firewall-dns-learning-address, connections from other IPs are rejected.",
+		Destination: &cliConfig.OVNKubernetesFeature.EgressFirewallDNSLearningPeers,
+		Value:       OVNKubernetesFeature.EgressFirewallDNSLearningPeers,
+	},
+	&cli.DurationFlag{
+		Name: "policy-rule-hits-interval",
+		Usage: "Interval to collect network policy and admin network policy rule hit counts from the local OVS " +
+			"flows, export them as metrics and report the rules that were hit in the AdminNetworkPolicy and " +
+			"BaselineAdminNetworkPolicy status. Disabled when 0.",
+		Destination: &cliConfig.OVNKubernetesFeature.PolicyRuleHitsInterval,
+		Value:       OVNKubernetesFeature.PolicyRuleHitsInterval,
+	},
+	&cli.IntFlag{
+		Name:        "multicast-idle-timeout",
+		Usage:       "Number of seconds after which a multicast group membership learnt by IGMP/MLD snooping expires. Uses the OVN default when 0.",
+		Destination: &cliConfig.OVNKubernetesFeature.MulticastIdleTimeout,
+		Value:       OVNKubernetesFeature.MulticastIdleTimeout,
+	},
+	&cli.IntFlag{
+		Name:        "multicast-query-interval",
+		Usage:       "Number of seconds between IGMP/MLD general queries. Uses the OVN default when 0.",
+		Destination: &cliConfig.OVNKubernetesFeature.MulticastQueryInterval,
+		Value:       OVNKubernetesFeature.MulticastQueryInterval,
+	},
+	&cli.IntFlag{
+		Name:        "multicast-query-max-response",
+		Usage:       "Max response time in seconds advertised in IGMP/MLD queries. Uses the OVN default when 0.",
+		Destination: &cliConfig.OVNKubernetesFeature.MulticastQueryMaxResponse,
+		Value:       OVNKubernetesFeature.MulticastQueryMaxResponse,
+	},
+	&cli.IntFlag{
+		Name:        "multicast-table-size",
+		Usage:       "Max number of multicast groups learnt by each node switch. Uses the OVN default when 0.",
+		Destination: &cliConfig.OVNKubernetesFeature.MulticastTableSize,
+		Value:       OVNKubernetesFeature.MulticastTableSize,
+	},
+	&cli.BoolFlag{
+		Name:        "multicast-flood-unregistered",
+		Usage:       "Flood multicast traffic to groups without registered receivers to all node switch ports.",
+		Destination: &cliConfig.OVNKubernetesFeature.MulticastFloodUnregistered,
+		Value:       OVNKubernetesFeature.MulticastFloodUnregistered,
+	},
+	&cli.BoolFlag{
+		Name:        "disable-multicast-querier",
+		Usage:       "Disable the IGMP/MLD querier of the node switches, for networks that already have a querier.",
+		Destination: &cliConfig.OVNKubernetesFeature.DisableMulticastQuerier,
+		Value:       OVNKubernetesFeature.DisableMulticastQuerier,
+	},
 }
 
 // K8sFlags capture Kubernetes-related options
@@ -1422,6 +1522,13 @@ var K8sFlags = []cli.Flag{
 			"will spin up pods for the load balancer to send traffic to.",
 		Destination: &cliConfig.Kubernetes.OVNEmptyLbEvents,
 	},
+	&cli.BoolFlag{
+		Name: "ovn-service-health-checks",
+		Usage: "If set, then OVN load balancer health checks are configured for the ClusterIP backends " +
+			"of all services, so that backends failing the OVN service monitor probes stop receiving traffic. " +
+			"Services can opt in or out with the k8s.ovn.org/service-health-check annotation.",
+		Destination: &cliConfig.Kubernetes.OVNServiceHealthChecks,
+	},
 	&cli.StringFlag{
 		Name:  "pod-ip",
 		Usage: "UNUSED",
@@ -1512,6 +1619,11 @@ var MetricsFlags = []cli.Flag{
 		Usage:       "Enables metrics related to scaling",
 		Destination: &cliConfig.Metrics.EnableScaleMetrics,
 	},
+	&cli.BoolFlag{
+		Name:        "metrics-enable-service",
+		Usage:       "Enables the per-service connection, packet and byte counters of ovnkube-node, collected from conntrack",
+		Destination: &cliConfig.Metrics.EnableServiceMetrics,
+	},
 }
 
 // OvnNBFlags capture OVN northbound database options
@@ -2311,6 +2423,10 @@ func buildOVNKubernetesFeatureConfig(cli, file *config) error {
 	if OVNKubernetesFeature.EnableDynamicUDNAllocation && !OVNKubernetesFeature.EnableNetworkSegmentation {
 		return fmt.Errorf("the Dynamic UDN Allocation feature cannot be enabled without also enabling Network Segmentation")
 	}
+	if OVNKubernetesFeature.MulticastIdleTimeout < 0 || OVNKubernetesFeature.MulticastQueryInterval < 0 ||
+		OVNKubernetesFeature.MulticastQueryMaxResponse < 0 || OVNKubernetesFeature.MulticastTableSize < 0 {
+		return fmt.Errorf("invalid multicast configuration: timeouts, intervals and table size can't be negative")
+	}
 	return nil
 }
 
@@ -75,6 +75,9 @@ type ControllerManager struct {
 	eIPController *ovn.EgressIPController
 
 	addressSetManager *addresssetmanager.AddressSetManager
+
+	// observabilityManager configures sampling, it is nil when observability is disabled
+	observabilityManager *observability.Manager
 }
 
 func (cm *ControllerManager) NewNetworkController(nInfo util.NetInfo) (networkmanager.NetworkController, error) {
@@ -483,10 +486,11 @@ func (cm *ControllerManager) Start(ctx context.Context) error {
 
 	var observabilityManager *observability.Manager
 	if config.OVNKubernetesFeature.EnableObservability {
-		observabilityManager = observability.NewManager(cm.nbClient)
+		observabilityManager = observability.NewManager(cm.nbClient, cm.watchFactory.SamplingConfigInformer())
 		if err = observabilityManager.Init(); err != nil {
 			return fmt.Errorf("failed to init observability manager: %w", err)
 		}
+		cm.observabilityManager = observabilityManager
 	} else {
 		err = observability.Cleanup(cm.nbClient)
 		if err != nil {
@@ -566,6 +570,10 @@ func (cm *ControllerManager) Stop() {
 	if cm.addressSetManager != nil {
 		cm.addressSetManager.Stop()
 	}
+
+	if cm.observabilityManager != nil {
+		cm.observabilityManager.Stop()
+	}
 }
 
 func (cm *ControllerManager) Reconcile(_ string, _, _ util.NetInfo) error {
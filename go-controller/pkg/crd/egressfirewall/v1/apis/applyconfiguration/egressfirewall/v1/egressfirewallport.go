@@ -10,10 +10,18 @@ package v1
 //
 // EgressFirewallPort specifies the port to allow or deny traffic to
 type EgressFirewallPortApplyConfiguration struct {
-	// protocol (tcp, udp, sctp) that the traffic must match.
+	// protocol (tcp, udp, sctp, icmp, icmpv6) that the traffic must match.
 	Protocol *string `json:"protocol,omitempty"`
-	// port that the traffic must match
+	// port that the traffic must match.
+	// When unset, all the ports of the TCP, UDP or SCTP protocol are matched.
 	Port *int32 `json:"port,omitempty"`
+	// endPort indicates that the range of ports from port to endPort, inclusive, is matched.
+	EndPort *int32 `json:"endPort,omitempty"`
+	// icmpType is the ICMP or ICMPv6 type that the traffic must match.
+	// When unset, all ICMP or ICMPv6 traffic is matched.
+	ICMPType *int32 `json:"icmpType,omitempty"`
+	// icmpCode is the ICMP or ICMPv6 code that the traffic must match, requires icmpType.
+	ICMPCode *int32 `json:"icmpCode,omitempty"`
 }
 
 // EgressFirewallPortApplyConfiguration constructs a declarative configuration of the EgressFirewallPort type for use with
@@ -37,3 +45,27 @@ func (b *EgressFirewallPortApplyConfiguration) WithPort(value int32) *EgressFire
 	b.Port = &value
 	return b
 }
+
+// WithEndPort sets the EndPort field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the EndPort field is set to the value of the last call.
+func (b *EgressFirewallPortApplyConfiguration) WithEndPort(value int32) *EgressFirewallPortApplyConfiguration {
+	b.EndPort = &value
+	return b
+}
+
+// WithICMPType sets the ICMPType field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ICMPType field is set to the value of the last call.
+func (b *EgressFirewallPortApplyConfiguration) WithICMPType(value int32) *EgressFirewallPortApplyConfiguration {
+	b.ICMPType = &value
+	return b
+}
+
+// WithICMPCode sets the ICMPCode field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ICMPCode field is set to the value of the last call.
+func (b *EgressFirewallPortApplyConfiguration) WithICMPCode(value int32) *EgressFirewallPortApplyConfiguration {
+	b.ICMPCode = &value
+	return b
+}
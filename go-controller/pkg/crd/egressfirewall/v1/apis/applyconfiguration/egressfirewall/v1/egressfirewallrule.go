@@ -20,6 +20,12 @@ type EgressFirewallRuleApplyConfiguration struct {
 	Ports []EgressFirewallPortApplyConfiguration `json:"ports,omitempty"`
 	// to is the target that traffic is allowed/denied to
 	To *EgressFirewallDestinationApplyConfiguration `json:"to,omitempty"`
+	// logLevel sets the ACL logging severity for this rule, overriding the level set for the rule type
+	// by the namespace k8s.ovn.org/acl-logging annotation. "none" disables logging for this rule.
+	LogLevel *string `json:"logLevel,omitempty"`
+	// sample can be set to false to exclude this rule from observability sampling.
+	// When unset or true, the rule is sampled if EgressFirewall sampling is enabled.
+	Sample *bool `json:"sample,omitempty"`
 }
 
 // EgressFirewallRuleApplyConfiguration constructs a declarative configuration of the EgressFirewallRule type for use with
@@ -56,3 +62,19 @@ func (b *EgressFirewallRuleApplyConfiguration) WithTo(value *EgressFirewallDesti
 	b.To = value
 	return b
 }
+
+// WithLogLevel sets the LogLevel field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LogLevel field is set to the value of the last call.
+func (b *EgressFirewallRuleApplyConfiguration) WithLogLevel(value string) *EgressFirewallRuleApplyConfiguration {
+	b.LogLevel = &value
+	return b
+}
+
+// WithSample sets the Sample field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Sample field is set to the value of the last call.
+func (b *EgressFirewallRuleApplyConfiguration) WithSample(value bool) *EgressFirewallRuleApplyConfiguration {
+	b.Sample = &value
+	return b
+}
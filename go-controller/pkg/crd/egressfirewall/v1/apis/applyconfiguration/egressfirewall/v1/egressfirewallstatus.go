@@ -8,8 +8,9 @@ package v1
 // EgressFirewallStatusApplyConfiguration represents a declarative configuration of the EgressFirewallStatus type for use
 // with apply.
 type EgressFirewallStatusApplyConfiguration struct {
-	Status   *string  `json:"status,omitempty"`
-	Messages []string `json:"messages,omitempty"`
+	Status   *string                                    `json:"status,omitempty"`
+	Messages []string                                   `json:"messages,omitempty"`
+	RuleHits []EgressFirewallRuleHitsApplyConfiguration `json:"ruleHits,omitempty"`
 }
 
 // EgressFirewallStatusApplyConfiguration constructs a declarative configuration of the EgressFirewallStatus type for use with
@@ -35,3 +36,16 @@ func (b *EgressFirewallStatusApplyConfiguration) WithMessages(values ...string)
 	}
 	return b
 }
+
+// WithRuleHits adds the given value to the RuleHits field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the RuleHits field.
+func (b *EgressFirewallStatusApplyConfiguration) WithRuleHits(values ...*EgressFirewallRuleHitsApplyConfiguration) *EgressFirewallStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithRuleHits")
+		}
+		b.RuleHits = append(b.RuleHits, *values[i])
+	}
+	return b
+}
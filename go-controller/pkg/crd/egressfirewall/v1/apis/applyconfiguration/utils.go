@@ -27,6 +27,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &egressfirewallv1.EgressFirewallPortApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("EgressFirewallRule"):
 		return &egressfirewallv1.EgressFirewallRuleApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("EgressFirewallRuleHits"):
+		return &egressfirewallv1.EgressFirewallRuleHitsApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("EgressFirewallSpec"):
 		return &egressfirewallv1.EgressFirewallSpecApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("EgressFirewallStatus"):
@@ -44,6 +44,23 @@ type EgressFirewallStatus struct {
 	// +listType=set
 	// +optional
 	Messages []string `json:"messages,omitempty"`
+	// ruleHits reports when each rule last matched traffic, rules that didn't match any traffic are not listed.
+	// Only reported when egress firewall rule hit reporting is enabled.
+	// +listType=map
+	// +listMapKey=index
+	// +optional
+	RuleHits []EgressFirewallRuleHits `json:"ruleHits,omitempty"`
+}
+
+// EgressFirewallRuleHits is the last time an egress firewall rule matched traffic in any zone.
+type EgressFirewallRuleHits struct {
+	// index of the rule in spec.egress.
+	Index int32 `json:"index"`
+	// lastHitTime is the time when the rule was last seen matching new packets.
+	// It is only updated once it is older than a few minutes, to limit status updates.
+	LastHitTime metav1.Time `json:"lastHitTime"`
+	// zone that reported the last hit, with interconnect this is the node name.
+	Zone string `json:"zone"`
 }
 
 // EgressFirewallSpec is a desired state description of EgressFirewall.
@@ -61,19 +78,60 @@ type EgressFirewallRule struct {
 	Ports []EgressFirewallPort `json:"ports,omitempty"`
 	// to is the target that traffic is allowed/denied to
 	To EgressFirewallDestination `json:"to"`
+	// logLevel sets the ACL logging severity for this rule, overriding the level set for the rule type
+	// by the namespace k8s.ovn.org/acl-logging annotation. "none" disables logging for this rule.
+	// +kubebuilder:validation:Enum=alert;warning;notice;info;debug;none
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+	// sample can be set to false to exclude this rule from observability sampling.
+	// When unset or true, the rule is sampled if EgressFirewall sampling is enabled.
+	// +optional
+	Sample *bool `json:"sample,omitempty"`
 }
 
+// EgressFirewallRuleLogLevelNone disables ACL logging for an egress firewall rule.
+const EgressFirewallRuleLogLevelNone = "none"
+
 // EgressFirewallPort specifies the port to allow or deny traffic to
+// +kubebuilder:validation:XValidation:rule="!has(self.endPort) || (has(self.port) && self.endPort >= self.port)", message="endPort requires port and must be greater than or equal to port"
+// +kubebuilder:validation:XValidation:rule="!(self.protocol in ['ICMP', 'ICMPv6']) || (!has(self.port) && !has(self.endPort))", message="port and endPort are not supported for ICMP and ICMPv6"
+// +kubebuilder:validation:XValidation:rule="self.protocol in ['ICMP', 'ICMPv6'] || (!has(self.icmpType) && !has(self.icmpCode))", message="icmpType and icmpCode are only supported for ICMP and ICMPv6"
+// +kubebuilder:validation:XValidation:rule="!has(self.icmpCode) || has(self.icmpType)", message="icmpCode requires icmpType"
 type EgressFirewallPort struct {
-	// protocol (tcp, udp, sctp) that the traffic must match.
-	// +kubebuilder:validation:Pattern=^TCP|UDP|SCTP$
+	// protocol (tcp, udp, sctp, icmp, icmpv6) that the traffic must match.
+	// +kubebuilder:validation:Pattern=^(TCP|UDP|SCTP|ICMP|ICMPv6)$
 	Protocol string `json:"protocol"`
-	// port that the traffic must match
+	// port that the traffic must match.
+	// When unset, all the ports of the TCP, UDP or SCTP protocol are matched.
 	// +kubebuilder:validation:Minimum:=1
 	// +kubebuilder:validation:Maximum:=65535
-	Port int32 `json:"port"`
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// endPort indicates that the range of ports from port to endPort, inclusive, is matched.
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=65535
+	// +optional
+	EndPort *int32 `json:"endPort,omitempty"`
+	// icmpType is the ICMP or ICMPv6 type that the traffic must match.
+	// When unset, all ICMP or ICMPv6 traffic is matched.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=255
+	// +optional
+	ICMPType *int32 `json:"icmpType,omitempty"`
+	// icmpCode is the ICMP or ICMPv6 code that the traffic must match, requires icmpType.
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=255
+	// +optional
+	ICMPCode *int32 `json:"icmpCode,omitempty"`
 }
 
+const (
+	// EgressFirewallProtocolICMP matches ICMP traffic to IPv4 destinations.
+	EgressFirewallProtocolICMP = "ICMP"
+	// EgressFirewallProtocolICMPv6 matches ICMPv6 traffic to IPv6 destinations.
+	EgressFirewallProtocolICMPv6 = "ICMPv6"
+)
+
 // +kubebuilder:validation:MinProperties:=1
 // +kubebuilder:validation:MaxProperties:=1
 // EgressFirewallDestination is the target that traffic is either allowed or denied to
@@ -98,6 +98,21 @@ func (in *EgressFirewallList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressFirewallPort) DeepCopyInto(out *EgressFirewallPort) {
 	*out = *in
+	if in.EndPort != nil {
+		in, out := &in.EndPort, &out.EndPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ICMPType != nil {
+		in, out := &in.ICMPType, &out.ICMPType
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ICMPCode != nil {
+		in, out := &in.ICMPCode, &out.ICMPCode
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -117,9 +132,16 @@ func (in *EgressFirewallRule) DeepCopyInto(out *EgressFirewallRule) {
 	if in.Ports != nil {
 		in, out := &in.Ports, &out.Ports
 		*out = make([]EgressFirewallPort, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.To.DeepCopyInto(&out.To)
+	if in.Sample != nil {
+		in, out := &in.Sample, &out.Sample
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -133,6 +155,23 @@ func (in *EgressFirewallRule) DeepCopy() *EgressFirewallRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EgressFirewallRuleHits) DeepCopyInto(out *EgressFirewallRuleHits) {
+	*out = *in
+	in.LastHitTime.DeepCopyInto(&out.LastHitTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EgressFirewallRuleHits.
+func (in *EgressFirewallRuleHits) DeepCopy() *EgressFirewallRuleHits {
+	if in == nil {
+		return nil
+	}
+	out := new(EgressFirewallRuleHits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EgressFirewallSpec) DeepCopyInto(out *EgressFirewallSpec) {
 	*out = *in
@@ -164,6 +203,13 @@ func (in *EgressFirewallStatus) DeepCopyInto(out *EgressFirewallStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RuleHits != nil {
+		in, out := &in.RuleHits, &out.RuleHits
+		*out = make([]EgressFirewallRuleHits, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
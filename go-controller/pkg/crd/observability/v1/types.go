@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SamplingConfig configures the OVN observability sampling: which OVN-Kubernetes features generate packet samples,
+// with which probability, and which OVS collector sets receive them.
+// All SamplingConfigs in the cluster are merged. When no SamplingConfig exists, all features are sampled with
+// probability 100 to the default collector set 42.
+//
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:path=samplingconfigs,scope=Cluster
+// +kubebuilder:singular=samplingconfig
+// +kubebuilder:object:root=true
+type SamplingConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired sampling configuration.
+	// +kubebuilder:validation:Required
+	// +required
+	Spec SamplingConfigSpec `json:"spec"`
+}
+
+// SamplingConfigSpec defines the desired state of SamplingConfig.
+type SamplingConfigSpec struct {
+	// Collectors is the list of collectors that receive samples.
+	// Every collector must have a unique collectorSetID, across all SamplingConfigs.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:XValidation:rule="self.all(x, self.exists_one(y, y.collectorSetID == x.collectorSetID))", message="collectorSetID must be unique"
+	// +required
+	Collectors []SamplingCollector `json:"collectors"`
+}
+
+// SamplingCollector defines the samples sent to one OVS collector set.
+type SamplingCollector struct {
+	// CollectorSetID is the ID of the OVS Flow_Sample_Collector_Set that receives the samples.
+	// ovnkube-observ uses collector set 42 by default.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	CollectorSetID int32 `json:"collectorSetID"`
+
+	// Features is the list of features that generate samples for this collector, with their sampling probability.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=5
+	// +listType=map
+	// +listMapKey=feature
+	// +required
+	Features []FeatureSampling `json:"features"`
+
+	// Namespaces limits sampling to objects in the given namespaces, e.g. NetworkPolicies or EgressFirewalls.
+	// Cluster-scoped objects, like AdminNetworkPolicies, are not sampled when Namespaces is set.
+	// When empty, objects in all namespaces are sampled.
+	// +kubebuilder:validation:MaxItems=100
+	// +listType=set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Networks limits sampling to objects of the given networks. The default network is named "default",
+	// a UserDefinedNetwork is referred to as "namespace/name" and a ClusterUserDefinedNetwork by its name.
+	// When empty, objects of all networks are sampled.
+	// +kubebuilder:validation:MaxItems=100
+	// +listType=set
+	// +optional
+	Networks []string `json:"networks,omitempty"`
+}
+
+// FeatureSampling sets the sampling probability for a feature.
+type FeatureSampling struct {
+	// Feature is the OVN-Kubernetes feature that generates samples.
+	// +kubebuilder:validation:Required
+	// +required
+	Feature SamplingFeature `json:"feature"`
+
+	// Probability is the percentage of packets that are sampled.
+	// Defaults to 100.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=100
+	// +optional
+	Probability int32 `json:"probability,omitempty"`
+}
+
+// SamplingFeature is an OVN-Kubernetes feature that supports sampling.
+// +kubebuilder:validation:Enum=EgressFirewall;NetworkPolicy;AdminNetworkPolicy;Multicast;UDNIsolation
+type SamplingFeature string
+
+const (
+	EgressFirewallSampling     SamplingFeature = "EgressFirewall"
+	NetworkPolicySampling      SamplingFeature = "NetworkPolicy"
+	AdminNetworkPolicySampling SamplingFeature = "AdminNetworkPolicy"
+	MulticastSampling          SamplingFeature = "Multicast"
+	UDNIsolationSampling       SamplingFeature = "UDNIsolation"
+)
+
+// SamplingConfigList contains a list of SamplingConfig.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type SamplingConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SamplingConfig `json:"items"`
+}
@@ -93,6 +93,8 @@ import (
 	networkqosinformerfactory "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/networkqos/v1alpha1/apis/informers/externalversions"
 	networkqosinformer "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/networkqos/v1alpha1/apis/informers/externalversions/networkqos/v1alpha1"
 	networkqoslister "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/networkqos/v1alpha1/apis/listers/networkqos/v1alpha1"
+	observabilityinformerfactory "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1/apis/informers/externalversions"
+	observabilityinformer "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1/apis/informers/externalversions/observability/v1"
 	routeadvertisementsapi "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/routeadvertisements/v1"
 	routeadvertisementsscheme "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/routeadvertisements/v1/apis/clientset/versioned/scheme"
 	routeadvertisementsinformerfactory "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/routeadvertisements/v1/apis/informers/externalversions"
@@ -135,6 +137,7 @@ type WatchFactory struct {
 	frrFactory           frrinformerfactory.SharedInformerFactory
 	networkQoSFactory    networkqosinformerfactory.SharedInformerFactory
 	vtepFactory          vtepinformerfactory.SharedInformerFactory
+	samplingFactory      observabilityinformerfactory.SharedInformerFactory
 	informers            map[reflect.Type]*informer
 
 	stopChan chan struct{}
@@ -165,6 +168,7 @@ func (wf *WatchFactory) ShallowClone() *WatchFactory {
 		frrFactory:           wf.frrFactory,
 		networkQoSFactory:    wf.networkQoSFactory,
 		vtepFactory:          wf.vtepFactory,
+		samplingFactory:      wf.samplingFactory,
 		informers:            wf.informers,
 		stopChan:             wf.stopChan,
 
@@ -584,6 +588,12 @@ func NewOVNKubeControllerWatchFactory(ovnClientset *util.OVNKubeControllerClient
 		wf.vtepFactory.K8s().V1().VTEPs().Informer()
 	}
 
+	if config.OVNKubernetesFeature.EnableObservability {
+		wf.samplingFactory = observabilityinformerfactory.NewSharedInformerFactory(ovnClientset.ObservabilityClient, resyncInterval)
+		// make sure shared informer is created for a factory, so on wf.samplingFactory.Start() it is initialized and caches are synced.
+		wf.samplingFactory.K8s().V1().SamplingConfigs().Informer()
+	}
+
 	return wf, nil
 }
 
@@ -696,6 +706,13 @@ func (wf *WatchFactory) Start() error {
 		}
 	}
 
+	if wf.samplingFactory != nil {
+		wf.samplingFactory.Start(wf.stopChan)
+		if err := waitForCacheSyncWithTimeout(wf.samplingFactory, wf.stopChan); err != nil {
+			return err
+		}
+	}
+
 	if wf.raFactory != nil {
 		wf.raFactory.Start(wf.stopChan)
 		if err := waitForCacheSyncWithTimeout(wf.raFactory, wf.stopChan); err != nil {
@@ -760,6 +777,10 @@ func (wf *WatchFactory) Stop() {
 		wf.vtepFactory.Shutdown()
 	}
 
+	if wf.samplingFactory != nil {
+		wf.samplingFactory.Shutdown()
+	}
+
 	if wf.raFactory != nil {
 		wf.raFactory.Shutdown()
 	}
@@ -1884,6 +1905,10 @@ func (wf *WatchFactory) VTEPInformer() vtepinformer.VTEPInformer {
 	return wf.vtepFactory.K8s().V1().VTEPs()
 }
 
+func (wf *WatchFactory) SamplingConfigInformer() observabilityinformer.SamplingConfigInformer {
+	return wf.samplingFactory.K8s().V1().SamplingConfigs()
+}
+
 func (wf *WatchFactory) DNSNameResolverInformer() ocpnetworkinformerv1alpha1.DNSNameResolverInformer {
 	return wf.dnsFactory.Network().V1alpha1().DNSNameResolvers()
 }
@@ -47,8 +47,16 @@ type Server interface {
 	// not exist will be dropped.  The value of the map is the number of
 	// endpoints the service has on this node.
 	SyncEndpoints(newEndpoints map[types.NamespacedName]int) error
+	// SetDataplaneCheck installs a check that is consulted before answering
+	// healthy for a service that has local endpoints. A nil check disables it.
+	SetDataplaneCheck(check DataplaneCheck)
 }
 
+// DataplaneCheck verifies that the node dataplane is able to forward traffic
+// for the given service. A non-nil error makes the health check fail even if
+// the service has local endpoints.
+type DataplaneCheck func(name types.NamespacedName) error
+
 // Listener allows for testing of Server.  If the Listener argument
 // to NewServer() is nil, the real net.Listen function will be used.
 type Listener interface {
@@ -117,8 +125,15 @@ type server struct {
 	listener    Listener
 	httpFactory HTTPServerFactory
 
-	lock     sync.RWMutex
-	services map[types.NamespacedName]*hcInstance
+	lock           sync.RWMutex
+	services       map[types.NamespacedName]*hcInstance
+	dataplaneCheck DataplaneCheck
+}
+
+func (hcs *server) SetDataplaneCheck(check DataplaneCheck) {
+	hcs.lock.Lock()
+	defer hcs.lock.Unlock()
+	hcs.dataplaneCheck = check
 }
 
 func (hcs *server) SyncServices(newServices map[types.NamespacedName]uint16) error {
@@ -203,16 +218,27 @@ func (h hcHandler) ServeHTTP(resp http.ResponseWriter, _ *http.Request) {
 		return
 	}
 	count := svc.endpoints
+	check := h.hcs.dataplaneCheck
 	h.hcs.lock.RUnlock()
 
+	// only consult the dataplane when we would otherwise answer healthy, the
+	// check may be expensive and its result is irrelevant without endpoints
+	dataplaneReady := true
+	if count > 0 && check != nil {
+		if err := check(h.name); err != nil {
+			klog.V(5).Infof("Healthcheck %q failing: dataplane not ready: %v", h.name.String(), err)
+			dataplaneReady = false
+		}
+	}
+
 	resp.Header().Set("Content-Type", "application/json")
-	if count == 0 {
+	if count == 0 || !dataplaneReady {
 		resp.WriteHeader(http.StatusServiceUnavailable)
 	} else {
 		resp.WriteHeader(http.StatusOK)
 	}
-	fmt.Fprintf(resp, `{ "service": { "namespace": %q, "name": %q }, "localEndpoints": %d }`,
-		h.name.Namespace, h.name.Name, count)
+	fmt.Fprintf(resp, `{ "service": { "namespace": %q, "name": %q }, "localEndpoints": %d, "dataplaneReady": %t }`,
+		h.name.Namespace, h.name.Name, count, dataplaneReady)
 }
 
 func (hcs *server) SyncEndpoints(newEndpoints map[types.NamespacedName]int) error {
@@ -21,6 +21,7 @@ package healthcheck
 
 import (
 	"encoding/json"
+	"errors"
 	"net"
 	"net/http"
 	"net/http/httptest"
@@ -107,6 +108,7 @@ type hcPayload struct {
 		Name      string
 	}
 	LocalEndpoints int
+	DataplaneReady bool
 }
 
 func TestServer(t *testing.T) {
@@ -378,6 +380,53 @@ func TestServer(t *testing.T) {
 	testHandler(t, hcs, nsn4, http.StatusOK, 6)
 }
 
+func TestServerDataplaneCheck(t *testing.T) {
+	hcsi := NewServer("hostname", nil, newFakeListener(), newFakeHTTPServerFactory())
+	hcs := hcsi.(*server)
+
+	nsn := mknsn("a", "b")
+	if err := hcs.SyncServices(map[types.NamespacedName]uint16{nsn: 9376}); err != nil {
+		t.Fatalf("unexpected error while syncing services: %v", err)
+	}
+	if err := hcs.SyncEndpoints(map[types.NamespacedName]int{nsn: 2}); err != nil {
+		t.Fatalf("unexpected error while syncing endpoints: %v", err)
+	}
+
+	var checked []types.NamespacedName
+	var dataplaneErr error
+	hcs.SetDataplaneCheck(func(name types.NamespacedName) error {
+		checked = append(checked, name)
+		return dataplaneErr
+	})
+
+	// healthy dataplane
+	testHandler(t, hcs, nsn, http.StatusOK, 2)
+	if len(checked) != 1 || checked[0] != nsn {
+		t.Errorf("expected dataplane check for %q, got %v", nsn.String(), checked)
+	}
+
+	// broken dataplane fails the check despite local endpoints
+	dataplaneErr = errors.New("ovn-controller not connected")
+	testHandler(t, hcs, nsn, http.StatusServiceUnavailable, 2)
+
+	// the dataplane is not consulted when there are no local endpoints
+	checked = nil
+	if err := hcs.SyncEndpoints(map[types.NamespacedName]int{nsn: 0}); err != nil {
+		t.Fatalf("unexpected error while syncing endpoints: %v", err)
+	}
+	testHandler(t, hcs, nsn, http.StatusServiceUnavailable, 0)
+	if len(checked) != 0 {
+		t.Errorf("expected no dataplane checks, got %v", checked)
+	}
+
+	// removing the check restores endpoint-only behavior
+	hcs.SetDataplaneCheck(nil)
+	if err := hcs.SyncEndpoints(map[types.NamespacedName]int{nsn: 1}); err != nil {
+		t.Fatalf("unexpected error while syncing endpoints: %v", err)
+	}
+	testHandler(t, hcs, nsn, http.StatusOK, 1)
+}
+
 func testHandler(t *testing.T, hcs *server, nsn types.NamespacedName, status int, endpoints int) {
 	t.Helper()
 	handler := hcs.services[nsn].server.(*fakeHTTPServer).handler
@@ -402,4 +451,7 @@ func testHandler(t *testing.T, hcs *server, nsn types.NamespacedName, status int
 	if payload.LocalEndpoints != endpoints {
 		t.Errorf("expected %d endpoints, got %d", endpoints, payload.LocalEndpoints)
 	}
+	if expectReady := status == http.StatusOK; endpoints > 0 && payload.DataplaneReady != expectReady {
+		t.Errorf("expected dataplaneReady %t, got %t", expectReady, payload.DataplaneReady)
+	}
 }
@@ -174,6 +174,8 @@ func NewSBClientWithConfig(cfg config.OvnAuthConfig, promRegistry prometheus.Reg
 			client.WithTable(&sbdb.SBGlobal{}),
 			// used for metrics
 			client.WithTable(&sbdb.PortBinding{}),
+			// used by services controller to report backend health check status
+			client.WithTable(&sbdb.ServiceMonitor{}),
 		),
 	)
 	if err != nil {
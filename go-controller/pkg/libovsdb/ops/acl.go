@@ -182,3 +182,24 @@ func UpdateACLsLoggingOps(nbClient libovsdbclient.Client, ops []ovsdb.Operation,
 	modelClient := newModelClient(nbClient)
 	return modelClient.CreateOrUpdateOps(ops, opModels...)
 }
+
+// UpdateACLsSamplesOps updates the samples of the provided ACLs according to the given samplingConfig
+// and returns the corresponding ops. It is used to apply a new sampling config to existing ACLs.
+func UpdateACLsSamplesOps(nbClient libovsdbclient.Client, ops []ovsdb.Operation, samplingConfig *SamplingConfig, acls ...*nbdb.ACL) ([]ovsdb.Operation, error) {
+	opModels := make([]operationModel, 0, 2*len(acls))
+	for i := range acls {
+		// can't use i in the predicate, for loop replaces it in-memory
+		acl := acls[i]
+		opModels = addSample(samplingConfig, opModels, acl)
+		opModel := operationModel{
+			Model:          acl,
+			OnModelUpdates: []interface{}{&acl.SampleNew, &acl.SampleEst},
+			ErrNotFound:    true,
+			BulkOp:         false,
+		}
+		opModels = append(opModels, opModel)
+	}
+
+	modelClient := newModelClient(nbClient)
+	return modelClient.CreateOrUpdateOps(ops, opModels...)
+}
@@ -39,9 +39,11 @@ const (
 	MulticastClusterOwnerType   ownerType = "MulticastCluster"
 	NetpolNodeOwnerType         ownerType = "NetpolNode"
 	NetpolNamespaceOwnerType    ownerType = "NetpolNamespace"
-	VirtualMachineOwnerType     ownerType = "VirtualMachine"
-	UDNEnabledServiceOwnerType  ownerType = "UDNEnabledService"
-	AdvertisedNetworkOwnerType  ownerType = "AdvertisedNetwork"
+	// NetpolNamespaceDryRunOwnerType owns the resources shared by all dry-run network policies in a namespace
+	NetpolNamespaceDryRunOwnerType ownerType = "NetpolNamespaceDryRun"
+	VirtualMachineOwnerType        ownerType = "VirtualMachine"
+	UDNEnabledServiceOwnerType     ownerType = "UDNEnabledService"
+	AdvertisedNetworkOwnerType     ownerType = "AdvertisedNetwork"
 	// NetworkPolicyPortIndexOwnerType is the old version of NetworkPolicyOwnerType, kept for sync only
 	NetworkPolicyPortIndexOwnerType ownerType = "NetworkPolicyPortIndexOwnerType"
 	// ClusterOwnerType means the object is cluster-scoped and doesn't belong to any k8s objects
@@ -340,6 +342,15 @@ var PortGroupNetpolNamespace = newObjectIDsType(portGroup, NetpolNamespaceOwnerT
 	PolicyDirectionKey,
 })
 
+// every namespace that has at least 1 dry-run network policy, has port groups for the pods selected by
+// dry-run policies, that are shared by all dry-run network policies in that namespace.
+var PortGroupNetpolNamespaceDryRun = newObjectIDsType(portGroup, NetpolNamespaceDryRunOwnerType, []ExternalIDKey{
+	// namespace
+	ObjectNameKey,
+	// in the same namespace there can be 2 dry-run port groups, egress and ingress
+	PolicyDirectionKey,
+})
+
 var PortGroupNetworkPolicy = newObjectIDsType(portGroup, NetworkPolicyOwnerType, []ExternalIDKey{
 	// policy namespace+name
 	ObjectNameKey,
@@ -5,6 +5,7 @@ package ops
 
 import (
 	"context"
+	"errors"
 
 	libovsdbclient "github.com/ovn-kubernetes/libovsdb/client"
 	"github.com/ovn-kubernetes/libovsdb/ovsdb"
@@ -81,6 +82,58 @@ func CreateOrUpdateLoadBalancersOps(nbClient libovsdbclient.Client, ops []ovsdb.
 	return modelClient.CreateOrUpdateOps(ops, opModels...)
 }
 
+// CreateOrUpdateLoadBalancerHealthChecksOps creates or updates the provided
+// health checks and sets them as the health checks of the provided load
+// balancer, returning the corresponding ops. Health checks the load balancer
+// already has for the same vip are updated. The load balancer ops must be
+// generated after calling this function, health checks that are no longer
+// referenced by the load balancer are garbage collected by the database.
+func CreateOrUpdateLoadBalancerHealthChecksOps(nbClient libovsdbclient.Client, ops []ovsdb.Operation, lb *nbdb.LoadBalancer,
+	hcs ...*nbdb.LoadBalancerHealthCheck) ([]ovsdb.Operation, error) {
+	existingByVip := map[string]string{}
+	if lb.UUID != "" {
+		existingLB := &nbdb.LoadBalancer{UUID: lb.UUID}
+		ctx, cancel := context.WithTimeout(context.Background(), config.Default.OVSDBTxnTimeout)
+		defer cancel()
+		err := nbClient.Get(ctx, existingLB)
+		if err != nil && !errors.Is(err, libovsdbclient.ErrNotFound) {
+			return nil, err
+		}
+		for _, uuid := range existingLB.HealthCheck {
+			existingHC := &nbdb.LoadBalancerHealthCheck{UUID: uuid}
+			if err := nbClient.Get(ctx, existingHC); err == nil {
+				existingByVip[existingHC.Vip] = uuid
+			}
+		}
+	}
+
+	opModels := make([]operationModel, 0, len(hcs))
+	for i := range hcs {
+		// can't use i in the predicate, for loop replaces it in-memory
+		hc := hcs[i]
+		hc.UUID = existingByVip[hc.Vip]
+		opModel := operationModel{
+			Model:          hc,
+			OnModelUpdates: onModelUpdatesAllNonDefault(),
+			ErrNotFound:    false,
+			BulkOp:         false,
+		}
+		opModels = append(opModels, opModel)
+	}
+
+	modelClient := newModelClient(nbClient)
+	ops, err := modelClient.CreateOrUpdateOps(ops, opModels...)
+	if err != nil {
+		return nil, err
+	}
+
+	lb.HealthCheck = make([]string, 0, len(hcs))
+	for _, hc := range hcs {
+		lb.HealthCheck = append(lb.HealthCheck, hc.UUID)
+	}
+	return ops, nil
+}
+
 // RemoveLoadBalancerVipsOps removes the provided VIPs from the provided load
 // balancer set and returns the corresponding ops
 func RemoveLoadBalancerVipsOps(nbClient libovsdbclient.Client, ops []ovsdb.Operation, lb *nbdb.LoadBalancer, vips ...string) ([]ovsdb.Operation, error) {
@@ -132,6 +185,25 @@ func DeleteLoadBalancers(nbClient libovsdbclient.Client, lbs []*nbdb.LoadBalance
 	return err
 }
 
+// GetLoadBalancer looks up a load balancer from the cache
+func GetLoadBalancer(nbClient libovsdbclient.Client, lb *nbdb.LoadBalancer) (*nbdb.LoadBalancer, error) {
+	found := []*nbdb.LoadBalancer{}
+	opModel := operationModel{
+		Model:          lb,
+		ExistingResult: &found,
+		ErrNotFound:    true,
+		BulkOp:         false,
+	}
+
+	modelClient := newModelClient(nbClient)
+	err := modelClient.Lookup(opModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return found[0], nil
+}
+
 // ListLoadBalancers looks up all load balancers from the cache
 func ListLoadBalancers(nbClient libovsdbclient.Client) ([]*nbdb.LoadBalancer, error) {
 	lbs := []*nbdb.LoadBalancer{}
@@ -6,6 +6,9 @@ package ops
 import (
 	"context"
 	"hash/fnv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	libovsdbclient "github.com/ovn-kubernetes/libovsdb/client"
 	"github.com/ovn-kubernetes/libovsdb/model"
@@ -13,6 +16,7 @@ import (
 
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 )
 
 func CreateOrUpdateSampleCollector(nbClient libovsdbclient.Client, collector *nbdb.SampleCollector) error {
@@ -130,6 +134,10 @@ func GetSample(nbClient libovsdbclient.Client, sample *nbdb.Sample) (*nbdb.Sampl
 	return found[0], err
 }
 
+// SamplingDisabledKey can be set to "true" in the ExternalIDs of an ACL to exclude it from sampling,
+// regardless of the SamplingConfig.
+const SamplingDisabledKey = types.OvnK8sPrefix + "/sampling-disabled"
+
 type SampleFeature = string
 
 const (
@@ -140,9 +148,19 @@ const (
 	UDNIsolationSample       SampleFeature = "UDNIsolation"
 )
 
+// SamplingScope limits sampling to db objects owned by the given namespaces and networks.
+// An empty set matches all namespaces or networks.
+type SamplingScope struct {
+	Namespaces sets.Set[string]
+	// Networks are network names, as used by the network controllers.
+	Networks sets.Set[string]
+}
+
 // SamplingConfig is used to configure sampling for different db objects.
 type SamplingConfig struct {
 	featureCollectors map[SampleFeature][]string
+	// collectorScopes maps collector UUID to its scope. Collectors without a scope sample all db objects.
+	collectorScopes map[string]*SamplingScope
 }
 
 func NewSamplingConfig(featureCollectors map[SampleFeature][]string) *SamplingConfig {
@@ -151,6 +169,15 @@ func NewSamplingConfig(featureCollectors map[SampleFeature][]string) *SamplingCo
 	}
 }
 
+// NewScopedSamplingConfig returns a SamplingConfig where collectors present in collectorScopes only sample
+// db objects that match the collector's scope.
+func NewScopedSamplingConfig(featureCollectors map[SampleFeature][]string, collectorScopes map[string]*SamplingScope) *SamplingConfig {
+	return &SamplingConfig{
+		featureCollectors: featureCollectors,
+		collectorScopes:   collectorScopes,
+	}
+}
+
 func addSample(c *SamplingConfig, opModels []operationModel, model model.Model) []operationModel {
 	switch t := model.(type) {
 	case *nbdb.ACL:
@@ -166,7 +193,7 @@ func createOrUpdateSampleForACL(opModels []operationModel, c *SamplingConfig, ac
 		acl.SampleNew = nil
 		return opModels
 	}
-	collectors := c.featureCollectors[getACLSampleFeature(acl)]
+	collectors := c.getACLCollectors(acl)
 	if len(collectors) == 0 {
 		acl.SampleEst = nil
 		acl.SampleNew = nil
@@ -208,7 +235,8 @@ func GetACLSampleID(acl *nbdb.ACL) uint32 {
 	return h.Sum32()
 }
 
-func getACLSampleFeature(acl *nbdb.ACL) SampleFeature {
+// GetACLSampleFeature returns the sample feature of the ACL owner, or "" if the owner is not sampled.
+func GetACLSampleFeature(acl *nbdb.ACL) SampleFeature {
 	switch acl.ExternalIDs[OwnerTypeKey.String()] {
 	case AdminNetworkPolicyOwnerType, BaselineAdminNetworkPolicyOwnerType:
 		return AdminNetworkPolicySample
@@ -223,3 +251,45 @@ func getACLSampleFeature(acl *nbdb.ACL) SampleFeature {
 	}
 	return ""
 }
+
+// getACLCollectors returns the collectors that should sample given ACL.
+func (c *SamplingConfig) getACLCollectors(acl *nbdb.ACL) []string {
+	if acl.ExternalIDs[SamplingDisabledKey] == "true" {
+		return nil
+	}
+	collectors := c.featureCollectors[GetACLSampleFeature(acl)]
+	if len(c.collectorScopes) == 0 || len(collectors) == 0 {
+		return collectors
+	}
+	namespace, namespaced := getACLSampleNamespace(acl)
+	network := strings.TrimSuffix(acl.ExternalIDs[OwnerControllerKey.String()], "-network-controller")
+	scopedCollectors := make([]string, 0, len(collectors))
+	for _, collector := range collectors {
+		scope := c.collectorScopes[collector]
+		if scope != nil {
+			if len(scope.Namespaces) > 0 && (!namespaced || !scope.Namespaces.Has(namespace)) {
+				continue
+			}
+			if len(scope.Networks) > 0 && !scope.Networks.Has(network) {
+				continue
+			}
+		}
+		scopedCollectors = append(scopedCollectors, collector)
+	}
+	return scopedCollectors
+}
+
+// getACLSampleNamespace returns the namespace of the object that owns given ACL.
+// The second return value is false for cluster-scoped owners.
+func getACLSampleNamespace(acl *nbdb.ACL) (string, bool) {
+	objName := acl.ExternalIDs[ObjectNameKey.String()]
+	switch acl.ExternalIDs[OwnerTypeKey.String()] {
+	case NetworkPolicyOwnerType:
+		// object name has "namespace:name" format
+		namespace, _, found := strings.Cut(objName, ":")
+		return namespace, found
+	case NetpolNamespaceOwnerType, MulticastNamespaceOwnerType, EgressFirewallOwnerType:
+		return objName, true
+	}
+	return "", false
+}
@@ -137,6 +137,32 @@ func BuildACL(dbIDs *libovsdbops.DbObjectIDs, priority int, match, action string
 	return ACL
 }
 
+// BuildDryRunACL builds an ACL for a policy in dry-run mode: the ACL is created with dryRunAction instead of
+// the given action, which is recorded in the ACL external IDs and in the ACL name so that logs and samples
+// can tell what the policy would do if enforced. Logging follows the severity configured for action.
+func BuildDryRunACL(dbIDs *libovsdbops.DbObjectIDs, priority int, match, action, dryRunAction string,
+	logLevels *ACLLoggingLevels, aclT ACLPipelineType, tier int) *nbdb.ACL {
+	acl := BuildACL(dbIDs, priority, match, dryRunAction, nil, aclT, tier)
+	acl.ExternalIDs[types.ACLDryRunActionExternalID] = action
+	name := GetDryRunACLName(action, GetACLName(dbIDs))
+	acl.Name = &name
+	log, logSeverity := getLogSeverity(action, logLevels)
+	libovsdbops.SetACLLogging(acl, logSeverity, log)
+	return acl
+}
+
+// GetDryRunACLName prefixes the ACL name with the verdict the ACL would have if enforced, e.g. "would-deny".
+func GetDryRunACLName(action, aclName string) string {
+	verdict := "would-" + action
+	switch action {
+	case nbdb.ACLActionAllow, nbdb.ACLActionAllowRelated, nbdb.ACLActionAllowStateless:
+		verdict = "would-allow"
+	case nbdb.ACLActionDrop, nbdb.ACLActionReject:
+		verdict = "would-deny"
+	}
+	return fmt.Sprintf("%.63s", verdict+":"+aclName)
+}
+
 func BuildANPACL(dbIDs *libovsdbops.DbObjectIDs, priority int, match, action string, aclT ACLPipelineType, logLevels *ACLLoggingLevels) *nbdb.ACL {
 	anpACL := BuildACL(dbIDs, priority, match, action, logLevels, aclT, GetACLTier(dbIDs))
 	return anpACL
@@ -207,7 +233,12 @@ func UpdateACLLogging(nbClient libovsdbclient.Client, ACLs []*nbdb.ACL, aclLoggi
 		return nil
 	}
 	for i := range ACLs {
-		log, severity := getLogSeverity(ACLs[i].Action, aclLogging)
+		action := ACLs[i].Action
+		if dryRunAction, ok := ACLs[i].ExternalIDs[types.ACLDryRunActionExternalID]; ok {
+			// dry-run ACLs are logged as the action they would have
+			action = dryRunAction
+		}
+		log, severity := getLogSeverity(action, aclLogging)
 		libovsdbops.SetACLLogging(ACLs[i], severity, log)
 	}
 	ops, err := libovsdbops.UpdateACLsLoggingOps(nbClient, nil, ACLs...)
@@ -50,6 +50,37 @@ var metricOvnKubeNodeLogFileSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 	},
 )
 
+// serviceMetricLabels are the labels of the per-service metrics: the service and the vip and port of its connections.
+// NodePort connections have the node IP as vip.
+var serviceMetricLabels = []string{"namespace", "name", "vip", "port", "protocol"}
+
+// MetricServiceConnections is the number of connections to a service established through the node
+var MetricServiceConnections = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: types.MetricOvnkubeNamespace,
+	Subsystem: types.MetricOvnkubeSubsystemNode,
+	Name:      "service_connections_total",
+	Help:      "The number of connections to the service vip and port established through the node."},
+	serviceMetricLabels,
+)
+
+// MetricServicePackets is the number of packets of the service connections established through the node
+var MetricServicePackets = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: types.MetricOvnkubeNamespace,
+	Subsystem: types.MetricOvnkubeSubsystemNode,
+	Name:      "service_packets_total",
+	Help:      "The number of packets, in both directions, of the connections to the service vip and port established through the node."},
+	serviceMetricLabels,
+)
+
+// MetricServiceBytes is the number of bytes of the service connections established through the node
+var MetricServiceBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: types.MetricOvnkubeNamespace,
+	Subsystem: types.MetricOvnkubeSubsystemNode,
+	Name:      "service_bytes_total",
+	Help:      "The number of bytes, in both directions, of the connections to the service vip and port established through the node."},
+	serviceMetricLabels,
+)
+
 var registerNodeMetricsOnce sync.Once
 
 func RegisterNodeMetrics(stopChan <-chan struct{}) {
@@ -84,5 +115,10 @@ func RegisterNodeMetrics(stopChan <-chan struct{}) {
 		}
 		prometheus.MustRegister(metricOvnKubeNodeLogFileSize)
 		go ovnKubeLogFileSizeMetricsUpdater(metricOvnKubeNodeLogFileSize, stopChan)
+		if config.Metrics.EnableServiceMetrics {
+			prometheus.MustRegister(MetricServiceConnections)
+			prometheus.MustRegister(MetricServicePackets)
+			prometheus.MustRegister(MetricServiceBytes)
+		}
 	})
 }
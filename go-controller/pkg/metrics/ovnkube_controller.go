@@ -131,6 +131,15 @@ var MetricSyncServiceLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
 	Buckets:   prometheus.ExponentialBuckets(.1, 2, 15)},
 )
 
+// MetricServiceMonitors is the number of OVN service monitors checking the health of service backends, by status.
+var MetricServiceMonitors = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: types.MetricOvnkubeNamespace,
+	Subsystem: types.MetricOvnkubeSubsystemController,
+	Name:      "service_monitors",
+	Help:      "The number of OVN service monitors checking the health of service backends, by status"},
+	[]string{"status"},
+)
+
 var MetricOVNKubeControllerReadyDuration = prometheus.NewGauge(prometheus.GaugeOpts{
 	Namespace: types.MetricOvnkubeNamespace,
 	Subsystem: types.MetricOvnkubeSubsystemController,
@@ -308,6 +317,63 @@ var metricBANPDBObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 
 /** AdminNetworkPolicyMetrics End**/
 
+/** PolicyRuleHitsMetrics Begin**/
+var metricNetworkPolicyRuleHitPackets = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: types.MetricOvnkubeNamespace,
+	Subsystem: types.MetricOvnkubeSubsystemController,
+	Name:      "network_policy_rule_hit_packets_total",
+	Help:      "The number of packets that matched a network policy rule in the local zone"},
+	[]string{
+		"namespace",
+		"policy",
+		"direction",
+		"rule",
+	},
+)
+
+var metricNetworkPolicyRuleHitBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: types.MetricOvnkubeNamespace,
+	Subsystem: types.MetricOvnkubeSubsystemController,
+	Name:      "network_policy_rule_hit_bytes_total",
+	Help:      "The number of bytes that matched a network policy rule in the local zone"},
+	[]string{
+		"namespace",
+		"policy",
+		"direction",
+		"rule",
+	},
+)
+
+var metricAdminNetworkPolicyRuleHitPackets = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: types.MetricOvnkubeNamespace,
+	Subsystem: types.MetricOvnkubeSubsystemController,
+	Name:      "admin_network_policy_rule_hit_packets_total",
+	Help: "The number of packets that matched an admin (policy_type=ANP) or baseline admin (policy_type=BANP) " +
+		"network policy rule in the local zone"},
+	[]string{
+		"policy_type",
+		"policy",
+		"direction",
+		"rule",
+	},
+)
+
+var metricAdminNetworkPolicyRuleHitBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: types.MetricOvnkubeNamespace,
+	Subsystem: types.MetricOvnkubeSubsystemController,
+	Name:      "admin_network_policy_rule_hit_bytes_total",
+	Help: "The number of bytes that matched an admin (policy_type=ANP) or baseline admin (policy_type=BANP) " +
+		"network policy rule in the local zone"},
+	[]string{
+		"policy_type",
+		"policy",
+		"direction",
+		"rule",
+	},
+)
+
+/** PolicyRuleHitsMetrics End**/
+
 // metricFirstSeenLSPLatency is the time between a pod first seen in OVN-Kubernetes and its Logical Switch Port is created
 var metricFirstSeenLSPLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
 	Namespace: types.MetricOvnkubeNamespace,
@@ -383,6 +449,7 @@ func RegisterOVNKubeControllerPerformance(nbClient libovsdbclient.Client) {
 	prometheus.MustRegister(MetricRequeueServiceCount)
 	prometheus.MustRegister(MetricSyncServiceCount)
 	prometheus.MustRegister(MetricSyncServiceLatency)
+	prometheus.MustRegister(MetricServiceMonitors)
 	registerWorkqueueMetrics(types.MetricOvnkubeNamespace, types.MetricOvnkubeSubsystemController)
 	prometheus.MustRegister(prometheus.NewGaugeFunc(
 		prometheus.GaugeOpts{
@@ -417,6 +484,12 @@ func RegisterOVNKubeControllerFunctional(stopChan <-chan struct{}) {
 	prometheus.MustRegister(metricEgressRoutingViaHost)
 	prometheus.MustRegister(metricANPCount)
 	prometheus.MustRegister(metricBANPCount)
+	if config.OVNKubernetesFeature.PolicyRuleHitsInterval > 0 {
+		prometheus.MustRegister(metricNetworkPolicyRuleHitPackets)
+		prometheus.MustRegister(metricNetworkPolicyRuleHitBytes)
+		prometheus.MustRegister(metricAdminNetworkPolicyRuleHitPackets)
+		prometheus.MustRegister(metricAdminNetworkPolicyRuleHitBytes)
+	}
 	if err := prometheus.Register(MetricResourceRetryFailuresCount); err != nil {
 		if _, ok := err.(prometheus.AlreadyRegisteredError); !ok {
 			panic(err)
@@ -652,6 +725,34 @@ func DecrementBANPCount() {
 	metricBANPCount.Dec()
 }
 
+// AddNetworkPolicyRuleHits adds the new hits of a network policy rule
+func AddNetworkPolicyRuleHits(namespace, policy, direction string, rule int, packets, bytes int64) {
+	ruleIdx := strconv.Itoa(rule)
+	metricNetworkPolicyRuleHitPackets.WithLabelValues(namespace, policy, direction, ruleIdx).Add(float64(packets))
+	metricNetworkPolicyRuleHitBytes.WithLabelValues(namespace, policy, direction, ruleIdx).Add(float64(bytes))
+}
+
+// DeleteNetworkPolicyRuleHits removes the hit counts of a network policy rule
+func DeleteNetworkPolicyRuleHits(namespace, policy, direction string, rule int) {
+	ruleIdx := strconv.Itoa(rule)
+	metricNetworkPolicyRuleHitPackets.DeleteLabelValues(namespace, policy, direction, ruleIdx)
+	metricNetworkPolicyRuleHitBytes.DeleteLabelValues(namespace, policy, direction, ruleIdx)
+}
+
+// AddAdminNetworkPolicyRuleHits adds the new hits of an admin or baseline admin network policy rule
+func AddAdminNetworkPolicyRuleHits(policyType, policy, direction string, rule int, packets, bytes int64) {
+	ruleIdx := strconv.Itoa(rule)
+	metricAdminNetworkPolicyRuleHitPackets.WithLabelValues(policyType, policy, direction, ruleIdx).Add(float64(packets))
+	metricAdminNetworkPolicyRuleHitBytes.WithLabelValues(policyType, policy, direction, ruleIdx).Add(float64(bytes))
+}
+
+// DeleteAdminNetworkPolicyRuleHits removes the hit counts of an admin or baseline admin network policy rule
+func DeleteAdminNetworkPolicyRuleHits(policyType, policy, direction string, rule int) {
+	ruleIdx := strconv.Itoa(rule)
+	metricAdminNetworkPolicyRuleHitPackets.DeleteLabelValues(policyType, policy, direction, ruleIdx)
+	metricAdminNetworkPolicyRuleHitBytes.DeleteLabelValues(policyType, policy, direction, ruleIdx)
+}
+
 type (
 	timestampType int
 	operation     int
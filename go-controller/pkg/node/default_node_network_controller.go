@@ -1136,6 +1136,22 @@ func (nc *DefaultNodeNetworkController) Start(ctx context.Context) error {
 			return err
 		}
 	}
+	// collect the per-service connection, packet and byte counters from conntrack
+	if config.Metrics.EnableServiceMetrics && config.IsModeFull() {
+		collector := newServiceMetricsCollector(nc.watchFactory.GetServices, func() []net.IP {
+			addressManager := nc.GetNodeAddressManager()
+			if addressManager == nil {
+				return nil
+			}
+			nodeIPs, _ := addressManager.ListAddresses()
+			return nodeIPs
+		})
+		nc.wg.Add(1)
+		go func() {
+			defer nc.wg.Done()
+			collector.run(nc.stopChan)
+		}()
+	}
 	if config.OVNKubernetesFeature.EnableMultiExternalGateway {
 		if err = nc.apbExternalRouteNodeController.Run(nc.wg, 1); err != nil {
 			return err
@@ -239,6 +239,15 @@ func (g *gateway) DeleteEndpointSlice(epSlice *discovery.EndpointSlice) error {
 	return utilerrors.Join(errors...)
 }
 
+// checkGatewayBridgeFlows returns an error if the flows of the default gateway
+// bridge are not installed.
+func (g *gateway) checkGatewayBridgeFlows() error {
+	if g.openflowManager == nil {
+		return fmt.Errorf("gateway bridge flows are not initialized")
+	}
+	return g.openflowManager.checkDefaultBridgeFlows()
+}
+
 // canHandleBridgeEgressIP returns true if this node should handle EgressIP
 // configuration on the bridge. Returns false if:
 // - Network segmentation (UDN) is not enabled
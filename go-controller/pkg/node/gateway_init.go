@@ -348,6 +348,9 @@ func (nc *DefaultNodeNetworkController) initGatewayMainStart(gw *gateway, waiter
 	var err error
 	if config.Gateway.NodeportEnable && config.IsModeFull() {
 		loadBalancerHealthChecker = newLoadBalancerHealthChecker(nc.name, nc.watchFactory)
+		// ovn-controller and the gateway bridge are local to the node in full
+		// mode, so health checks can also account for the dataplane state
+		loadBalancerHealthChecker.enableDataplaneCheck(gw.checkGatewayBridgeFlows, nc.stopChan, nc.wg)
 		portClaimWatcher, err = newPortClaimWatcher(nc.recorder)
 		if err != nil {
 			return err
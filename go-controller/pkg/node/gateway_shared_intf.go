@@ -383,9 +383,10 @@ func (npw *nodePortWatcher) updateServiceFlowCache(service *corev1.Service, netI
 		// Flows for cloud load balancers on Azure/GCP
 		// Established traffic is handled by default conntrack rules
 		// NodePort/Ingress access in the OVS bridge will only ever come from outside of the host
+		// Ingress IPs in Proxy mode are not the destination of the traffic proxied by the load balancer
 		ingParsedIPs := make([]string, 0, len(service.Status.LoadBalancer.Ingress))
 		for _, ing := range service.Status.LoadBalancer.Ingress {
-			if len(ing.IP) > 0 {
+			if len(ing.IP) > 0 && util.IsLoadBalancerIngressVIPMode(ing) {
 				ip := utilnet.ParseIPSloppy(ing.IP)
 				if ip == nil {
 					errors = append(errors, fmt.Errorf("failed to parse Ingress IP: %q", ing.IP))
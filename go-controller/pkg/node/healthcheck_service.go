@@ -4,8 +4,10 @@
 package node
 
 import (
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	discovery "k8s.io/api/discovery/v1"
@@ -29,6 +31,9 @@ type loadBalancerHealthChecker struct {
 	services     map[ktypes.NamespacedName]uint16
 	endpoints    map[ktypes.NamespacedName]int
 	watchFactory factory.NodeWatchFactory
+	// dataplane, if set, is consulted before reporting a service with local
+	// endpoints as healthy
+	dataplane *lbDataplaneChecker
 }
 
 func newLoadBalancerHealthChecker(nodeName string, watchFactory factory.NodeWatchFactory) *loadBalancerHealthChecker {
@@ -41,6 +46,19 @@ func newLoadBalancerHealthChecker(nodeName string, watchFactory factory.NodeWatc
 	}
 }
 
+// enableDataplaneCheck makes the health checks also verify the OVN dataplane
+// state of the node, refreshed in the background until stopCh is closed;
+// gatewayBridgeCheck may be nil if the node has no gateway bridge flows to verify.
+func (l *loadBalancerHealthChecker) enableDataplaneCheck(gatewayBridgeCheck func() error, stopCh <-chan struct{}, wg *sync.WaitGroup) {
+	l.dataplane = newLBDataplaneChecker(gatewayBridgeCheck)
+	l.server.SetDataplaneCheck(l.dataplane.Check)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		l.dataplane.run(stopCh)
+	}()
+}
+
 func (l *loadBalancerHealthChecker) AddService(svc *corev1.Service) error {
 	if svc.Spec.HealthCheckNodePort != 0 {
 		l.Lock()
@@ -95,6 +113,9 @@ func (l *loadBalancerHealthChecker) DeleteService(svc *corev1.Service) error {
 		name := ktypes.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}
 		delete(l.services, name)
 		delete(l.endpoints, name)
+		if l.dataplane != nil {
+			l.dataplane.Forget(name)
+		}
 		return l.server.SyncServices(l.services)
 	}
 	return nil
@@ -178,3 +199,153 @@ func (l *loadBalancerHealthChecker) CountLocalReadyEndpointAddresses(endpointSli
 	}
 	return len(localEndpointAddresses)
 }
+
+// lbDataplaneCheckInterval is the interval between two refreshes of the OVN and
+// OVS state backing the service health checks. Cloud load balancers probe every
+// node frequently and each refresh shells out to ovn-appctl, ovn-sbctl and
+// ovs-ofctl, so the probes only read the result of the last refresh.
+var lbDataplaneCheckInterval = 5 * time.Second
+
+var errLBDataplaneNotChecked = errors.New("load balancer dataplane not checked yet")
+
+// lbDataplaneChecker verifies that the node is able to forward traffic for a
+// service before its health check node port reports healthy: ovn-controller
+// must be connected to the southbound database, the load balancers of the
+// service must be present in the local southbound database and the gateway
+// bridge flows must be installed. The checks run in the background every
+// lbDataplaneCheckInterval, and right away for a service checked the first time.
+type lbDataplaneChecker struct {
+	sync.Mutex
+	// node is the result of the node wide checks
+	node error
+	// services are the results of the per service checks
+	services map[ktypes.NamespacedName]error
+	// refreshCh requests a refresh before the next interval
+	refreshCh chan struct{}
+
+	checkOVNController  func() error
+	checkGatewayBridge  func() error
+	checkServiceLBsInDB func(name ktypes.NamespacedName) error
+}
+
+func newLBDataplaneChecker(gatewayBridgeCheck func() error) *lbDataplaneChecker {
+	return &lbDataplaneChecker{
+		node:                errLBDataplaneNotChecked,
+		services:            make(map[ktypes.NamespacedName]error),
+		refreshCh:           make(chan struct{}, 1),
+		checkOVNController:  checkOVNControllerConnected,
+		checkGatewayBridge:  gatewayBridgeCheck,
+		checkServiceLBsInDB: checkServiceLoadBalancersInSBDB,
+	}
+}
+
+// run refreshes the results of the checks until stopCh is closed.
+func (d *lbDataplaneChecker) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(lbDataplaneCheckInterval)
+	defer ticker.Stop()
+	for {
+		d.refresh()
+		select {
+		case <-ticker.C:
+		case <-d.refreshCh:
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// refresh runs the node wide checks and the checks of the known services.
+func (d *lbDataplaneChecker) refresh() {
+	d.Lock()
+	names := make([]ktypes.NamespacedName, 0, len(d.services))
+	for name := range d.services {
+		names = append(names, name)
+	}
+	d.Unlock()
+
+	node := d.checkNode()
+	services := make(map[ktypes.NamespacedName]error, len(names))
+	if node == nil {
+		for _, name := range names {
+			services[name] = d.checkServiceLBsInDB(name)
+		}
+	}
+
+	d.Lock()
+	defer d.Unlock()
+	d.node = node
+	for name, err := range services {
+		// the service might have been forgotten during the checks
+		if _, ok := d.services[name]; ok {
+			d.services[name] = err
+		}
+	}
+}
+
+// Check implements healthcheck.DataplaneCheck. It returns the result of the
+// last refresh, and requests a refresh for a service checked the first time.
+func (d *lbDataplaneChecker) Check(name ktypes.NamespacedName) error {
+	d.Lock()
+	defer d.Unlock()
+	err, ok := d.services[name]
+	if !ok {
+		d.services[name] = errLBDataplaneNotChecked
+		select {
+		case d.refreshCh <- struct{}{}:
+		default:
+		}
+		err = errLBDataplaneNotChecked
+	}
+	if d.node != nil {
+		return d.node
+	}
+	return err
+}
+
+// Forget drops the result for a service that is no longer health checked.
+func (d *lbDataplaneChecker) Forget(name ktypes.NamespacedName) {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.services, name)
+}
+
+func (d *lbDataplaneChecker) checkNode() error {
+	if err := d.checkOVNController(); err != nil {
+		return err
+	}
+	if d.checkGatewayBridge != nil {
+		if err := d.checkGatewayBridge(); err != nil {
+			return fmt.Errorf("gateway bridge not ready: %w", err)
+		}
+	}
+	return nil
+}
+
+func checkOVNControllerConnected() error {
+	status, _, err := util.RunOVNControllerAppCtl("connection-status")
+	if err != nil {
+		return fmt.Errorf("could not get ovn-controller connection status: %w", err)
+	}
+	if status != "connected" {
+		return fmt.Errorf("ovn-controller connection status is %q", status)
+	}
+	return nil
+}
+
+// checkServiceLoadBalancersInSBDB verifies that northd synced at least one load
+// balancer of the service to the southbound database. northd only syncs load
+// balancers that are applied to a datapath, and with interconnect the local
+// southbound database only holds the datapaths of this node's zone, so finding
+// one means the load balancer is attached to the node's switch or gateway router.
+func checkServiceLoadBalancersInSBDB(name ktypes.NamespacedName) error {
+	stdout, stderr, err := util.RunOVNSbctl("--bare", "--columns=_uuid", "find", "Load_Balancer",
+		fmt.Sprintf("external_ids:\"%s\"=Service", types.LoadBalancerKindExternalID),
+		fmt.Sprintf("external_ids:\"%s\"=\"%s\"", types.LoadBalancerOwnerExternalID, name.String()))
+	if err != nil {
+		return fmt.Errorf("could not find load balancers of service %s, stderr: %q: %w", name, stderr, err)
+	}
+	if stdout == "" {
+		return fmt.Errorf("no load balancers of service %s found in the southbound database", name)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package node
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Load balancer health check dataplane checker", func() {
+	var (
+		checker       *lbDataplaneChecker
+		controllerErr error
+		bridgeErr     error
+		serviceErrs   map[types.NamespacedName]error
+		calls         map[string]int
+		svc           = types.NamespacedName{Namespace: "ns", Name: "svc"}
+	)
+
+	BeforeEach(func() {
+		controllerErr, bridgeErr = nil, nil
+		serviceErrs = map[types.NamespacedName]error{}
+		calls = map[string]int{}
+		checker = newLBDataplaneChecker(func() error {
+			calls["bridge"]++
+			return bridgeErr
+		})
+		checker.checkOVNController = func() error {
+			calls["controller"]++
+			return controllerErr
+		}
+		checker.checkServiceLBsInDB = func(name types.NamespacedName) error {
+			calls[name.String()]++
+			return serviceErrs[name]
+		}
+	})
+
+	It("reports not ready until the service is checked, and requests a refresh", func() {
+		Expect(checker.Check(svc)).To(MatchError(errLBDataplaneNotChecked))
+		Expect(checker.refreshCh).To(HaveLen(1))
+		Expect(calls).To(BeEmpty())
+	})
+
+	It("reports ready when the node and the service load balancers are ready", func() {
+		Expect(checker.Check(svc)).To(MatchError(errLBDataplaneNotChecked))
+		checker.refresh()
+		Expect(checker.Check(svc)).To(Succeed())
+		Expect(calls).To(Equal(map[string]int{"controller": 1, "bridge": 1, svc.String(): 1}))
+	})
+
+	It("reports not ready when ovn-controller is disconnected", func() {
+		controllerErr = fmt.Errorf("ovn-controller connection status is \"not connected\"")
+		Expect(checker.Check(svc)).To(HaveOccurred())
+		checker.refresh()
+		Expect(checker.Check(svc)).To(MatchError(controllerErr))
+		// node wide failures short-circuit the per service checks
+		Expect(calls).NotTo(HaveKey("bridge"))
+		Expect(calls).NotTo(HaveKey(svc.String()))
+	})
+
+	It("reports not ready when the gateway bridge has no flows", func() {
+		bridgeErr = fmt.Errorf("no flows installed on bridge breth0")
+		checker.refresh()
+		Expect(checker.Check(svc)).To(MatchError(ContainSubstring("gateway bridge not ready")))
+	})
+
+	It("reports not ready when the service load balancers are missing", func() {
+		other := types.NamespacedName{Namespace: "ns", Name: "other"}
+		serviceErrs[svc] = fmt.Errorf("no load balancers of service %s found", svc)
+		Expect(checker.Check(svc)).To(HaveOccurred())
+		Expect(checker.Check(other)).To(HaveOccurred())
+		checker.refresh()
+		Expect(checker.Check(svc)).To(MatchError(serviceErrs[svc]))
+		Expect(checker.Check(other)).To(Succeed())
+	})
+
+	It("only runs the checks when refreshed", func() {
+		Expect(checker.Check(svc)).To(HaveOccurred())
+		checker.refresh()
+		controllerErr = fmt.Errorf("ovn-controller connection status is \"not connected\"")
+		Expect(checker.Check(svc)).To(Succeed())
+		Expect(checker.Check(svc)).To(Succeed())
+		Expect(calls["controller"]).To(Equal(1))
+		Expect(calls[svc.String()]).To(Equal(1))
+
+		checker.refresh()
+		Expect(checker.Check(svc)).To(MatchError(controllerErr))
+		Expect(calls["controller"]).To(Equal(2))
+
+		controllerErr = nil
+		checker.refresh()
+		Expect(calls[svc.String()]).To(Equal(2))
+		checker.Forget(svc)
+		checker.refresh()
+		Expect(calls[svc.String()]).To(Equal(2))
+		Expect(checker.Check(svc)).To(MatchError(errLBDataplaneNotChecked))
+	})
+
+	It("refreshes in the background until stopped", func() {
+		stopCh := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			checker.run(stopCh)
+		}()
+		Eventually(func() error { return checker.Check(svc) }).Should(Succeed())
+		close(stopCh)
+		Eventually(done).Should(BeClosed())
+	})
+})
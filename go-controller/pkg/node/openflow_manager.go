@@ -31,6 +31,9 @@ type openflowManager struct {
 	exGWFlowMutex sync.Mutex
 	// channel to indicate we need to update flows immediately
 	flowChan chan struct{}
+	// syncErr is the outcome of the last attempt to sync the default bridge,
+	// protected by flowMutex
+	syncErr error
 }
 
 // UTILs Needed for UDN (also leveraged for default netInfo) in openflowmanager
@@ -128,7 +131,9 @@ func (c *openflowManager) syncFlows() {
 	if err != nil {
 		klog.Errorf("Failed to add flows for bridge %s, error: %v, stderr, %s, flow count: %d",
 			c.defaultBridge.GetBridgeName(), err, stderr, len(flows))
+		err = fmt.Errorf("failed to add flows for bridge %s: %v", c.defaultBridge.GetBridgeName(), err)
 	}
+	c.setSyncError(err)
 
 	if c.externalGatewayBridge != nil {
 		c.exGWFlowMutex.Lock()
@@ -143,6 +148,32 @@ func (c *openflowManager) syncFlows() {
 	}
 }
 
+func (c *openflowManager) setSyncError(err error) {
+	c.flowMutex.Lock()
+	defer c.flowMutex.Unlock()
+	c.syncErr = err
+}
+
+// checkDefaultBridgeFlows returns an error if the last sync of the default
+// gateway bridge failed or if the bridge has no flows installed at all.
+func (c *openflowManager) checkDefaultBridgeFlows() error {
+	c.flowMutex.Lock()
+	syncErr := c.syncErr
+	c.flowMutex.Unlock()
+	if syncErr != nil {
+		return syncErr
+	}
+	bridgeName := c.defaultBridge.GetBridgeName()
+	stdout, stderr, err := util.RunOVSOfctl("dump-aggregate", bridgeName)
+	if err != nil {
+		return fmt.Errorf("failed to dump aggregate flows of bridge %s, stderr: %q: %v", bridgeName, stderr, err)
+	}
+	if strings.Contains(stdout, "flow_count=0") {
+		return fmt.Errorf("no flows installed on bridge %s", bridgeName)
+	}
+	return nil
+}
+
 func flattenFlowCacheEntries(flowCache map[string][]string) []string {
 	flowCount := 0
 	for _, entry := range flowCache {
@@ -193,6 +224,7 @@ func (c *openflowManager) Run(stopChan <-chan struct{}, doneWg *sync.WaitGroup)
 
 				if err := checkPorts(c.getDefaultBridgePortConfigurations()); err != nil {
 					klog.Errorf("Checkports failed %v", err)
+					c.setSyncError(err)
 					continue
 				}
 
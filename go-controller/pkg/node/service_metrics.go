@@ -0,0 +1,461 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package node
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/metrics"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+)
+
+const (
+	// serviceMetricsInterval is the interval between two collections of the per-service packet and byte counters
+	serviceMetricsInterval = 15 * time.Second
+	// conntrackEventsRetryInterval is the interval between two subscriptions to the conntrack events if the
+	// subscription fails
+	conntrackEventsRetryInterval = 5 * time.Second
+
+	// nested attributes of CTA_COUNTERS_ORIG and CTA_COUNTERS_REPLY
+	ctaCountersPackets = 1
+	ctaCountersBytes   = 2
+)
+
+// serviceVIP is a vip, port and protocol of a service
+type serviceVIP struct {
+	ip       string
+	port     uint16
+	protocol uint8
+}
+
+// serviceMetricKey are the label values of the per-service metrics
+type serviceMetricKey struct {
+	namespace string
+	name      string
+	vip       string
+	port      string
+	protocol  string
+}
+
+func (k serviceMetricKey) labelValues() []string {
+	return []string{k.namespace, k.name, k.vip, k.port, k.protocol}
+}
+
+// serviceConnKey identifies a conntrack entry across collections and events
+type serviceConnKey struct {
+	zone     uint16
+	protocol uint8
+	srcIP    string
+	srcPort  uint16
+	dstIP    string
+	dstPort  uint16
+}
+
+// serviceConnCounters are the counters of a service conntrack entry at the last collection
+type serviceConnCounters struct {
+	key     serviceMetricKey
+	packets uint64
+	bytes   uint64
+}
+
+// serviceMetricsCollector collects the per-service connection, packet and byte counters from the conntrack
+// entries of the connections that were DNATed to a service backend on the node, by the OVN load balancers or by
+// the host service rules. The connections are counted from the conntrack NEW events, so that short-lived
+// connections are not missed. The packet and byte counters of the open connections are collected periodically,
+// and the remaining ones when the connections are destroyed. They require conntrack accounting
+// (net.netfilter.nf_conntrack_acct=1).
+type serviceMetricsCollector struct {
+	getServices func() ([]*corev1.Service, error)
+	getNodeIPs  func() []net.IP
+
+	sync.Mutex
+	// service ports of the vips and of the node ports at the last collection
+	vips      map[serviceVIP]serviceVIPPort
+	nodePorts map[serviceVIP]serviceVIPPort
+	// counters of the open service connections at the last collection or event
+	connections map[serviceConnKey]serviceConnCounters
+	// connections destroyed while a collection lists the conntrack entries, nil when none is listing
+	destroyed map[serviceConnKey]struct{}
+	// label values of the metrics set since the last collection
+	metricKeys map[serviceMetricKey]struct{}
+}
+
+func newServiceMetricsCollector(getServices func() ([]*corev1.Service, error), getNodeIPs func() []net.IP) *serviceMetricsCollector {
+	return &serviceMetricsCollector{
+		getServices: getServices,
+		getNodeIPs:  getNodeIPs,
+		vips:        map[serviceVIP]serviceVIPPort{},
+		nodePorts:   map[serviceVIP]serviceVIPPort{},
+		connections: map[serviceConnKey]serviceConnCounters{},
+		metricKeys:  map[serviceMetricKey]struct{}{},
+	}
+}
+
+func (c *serviceMetricsCollector) run(stopCh <-chan struct{}) {
+	if err := c.collect(); err != nil {
+		klog.Errorf("Failed to collect the service metrics: %v", err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		wait.Until(func() {
+			if err := c.receiveConntrackEvents(stopCh); err != nil {
+				klog.Errorf("Failed to receive the conntrack events of the service connections: %v", err)
+			}
+		}, conntrackEventsRetryInterval, stopCh)
+	}()
+	wait.Until(func() {
+		if err := c.collect(); err != nil {
+			klog.Errorf("Failed to collect the service metrics: %v", err)
+		}
+	}, serviceMetricsInterval, stopCh)
+	wg.Wait()
+}
+
+// receiveConntrackEvents receives the conntrack NEW and DESTROY events until the stop channel is closed or
+// receiving fails.
+func (c *serviceMetricsCollector) receiveConntrackEvents(stopCh <-chan struct{}) error {
+	socket, err := nl.Subscribe(unix.NETLINK_NETFILTER, unix.NFNLGRP_CONNTRACK_NEW, unix.NFNLGRP_CONNTRACK_DESTROY)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to the conntrack events: %w", err)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stopCh:
+		case <-done:
+		}
+		socket.Close()
+	}()
+	for {
+		msgs, _, err := socket.Receive()
+		if err != nil {
+			select {
+			case <-stopCh:
+				return nil
+			default:
+			}
+			if errors.Is(err, unix.ENOBUFS) {
+				klog.Warningf("Lost conntrack events of the service connections, the service connection counters are lower than the actual number of connections")
+				continue
+			}
+			return err
+		}
+		for _, msg := range msgs {
+			if msg.Header.Type>>8 != unix.NFNL_SUBSYS_CTNETLINK {
+				continue
+			}
+			flow, err := parseConntrackEvent(msg.Data)
+			if err != nil {
+				klog.V(5).Infof("Ignoring invalid conntrack event: %v", err)
+				continue
+			}
+			c.handleConntrackEvent(msg.Header.Type&0xff == nl.IPCTNL_MSG_CT_DELETE, flow)
+		}
+	}
+}
+
+// handleConntrackEvent counts a new service connection, or adds the last packet and byte counters of a
+// destroyed one.
+func (c *serviceMetricsCollector) handleConntrackEvent(destroyed bool, flow *netlink.ConntrackFlow) {
+	c.Lock()
+	defer c.Unlock()
+	key, counters, ok := c.getServiceConnection(flow)
+	if !ok {
+		return
+	}
+	labels := counters.key.labelValues()
+	c.metricKeys[counters.key] = struct{}{}
+	previous, seen := c.connections[key]
+	if !destroyed {
+		if !seen {
+			metrics.MetricServiceConnections.WithLabelValues(labels...).Inc()
+			c.connections[key] = serviceConnCounters{key: counters.key}
+		}
+		return
+	}
+	delete(c.connections, key)
+	if c.destroyed != nil {
+		c.destroyed[key] = struct{}{}
+	}
+	if previous.key != counters.key {
+		previous = serviceConnCounters{}
+	}
+	addCounterDelta(metrics.MetricServicePackets.WithLabelValues(labels...), previous.packets, counters.packets)
+	addCounterDelta(metrics.MetricServiceBytes.WithLabelValues(labels...), previous.bytes, counters.bytes)
+}
+
+var serviceProtocolNumbers = map[corev1.Protocol]uint8{
+	corev1.ProtocolTCP:  unix.IPPROTO_TCP,
+	corev1.ProtocolUDP:  unix.IPPROTO_UDP,
+	corev1.ProtocolSCTP: unix.IPPROTO_SCTP,
+}
+
+// serviceVIPPort is the service port of a vip
+type serviceVIPPort struct {
+	service  *corev1.Service
+	protocol corev1.Protocol
+}
+
+// buildServiceVIPs returns the service ports of the vips and of the node ports on the node IPs
+func buildServiceVIPs(services []*corev1.Service, nodeIPs []net.IP) (map[serviceVIP]serviceVIPPort, map[serviceVIP]serviceVIPPort) {
+	vips := map[serviceVIP]serviceVIPPort{}
+	nodePorts := map[serviceVIP]serviceVIPPort{}
+	for _, service := range services {
+		if !util.ServiceTypeHasClusterIP(service) || !util.IsClusterIPSet(service) {
+			continue
+		}
+		ips := append(util.GetClusterIPs(service), util.GetExternalAndLBIPs(service)...)
+		for _, port := range service.Spec.Ports {
+			protocol := serviceProtocolNumbers[port.Protocol]
+			vipPort := serviceVIPPort{service: service, protocol: port.Protocol}
+			for _, ip := range ips {
+				vips[serviceVIP{ip: ip, port: uint16(port.Port), protocol: protocol}] = vipPort
+			}
+			if port.NodePort == 0 {
+				continue
+			}
+			for _, ip := range nodeIPs {
+				nodePorts[serviceVIP{ip: ip.String(), port: uint16(port.NodePort), protocol: protocol}] = vipPort
+			}
+		}
+	}
+	return vips, nodePorts
+}
+
+// getServiceConnection returns the key and the counters of a conntrack entry of a connection DNATed to a
+// service backend.
+func (c *serviceMetricsCollector) getServiceConnection(flow *netlink.ConntrackFlow) (serviceConnKey, serviceConnCounters, bool) {
+	// only count the entries of the connections DNATed to a backend, the same connection can
+	// have other entries in other zones
+	if flow.Reverse.SrcIP.Equal(flow.Forward.DstIP) && flow.Reverse.SrcPort == flow.Forward.DstPort {
+		return serviceConnKey{}, serviceConnCounters{}, false
+	}
+	dst := serviceVIP{
+		ip:       flow.Forward.DstIP.String(),
+		port:     flow.Forward.DstPort,
+		protocol: flow.Forward.Protocol,
+	}
+	vipPort, ok := c.vips[dst]
+	if !ok {
+		vipPort, ok = c.nodePorts[dst]
+	}
+	if !ok {
+		return serviceConnKey{}, serviceConnCounters{}, false
+	}
+	key := serviceConnKey{
+		zone:     flow.Zone,
+		protocol: flow.Forward.Protocol,
+		srcIP:    flow.Forward.SrcIP.String(),
+		srcPort:  flow.Forward.SrcPort,
+		dstIP:    dst.ip,
+		dstPort:  dst.port,
+	}
+	counters := serviceConnCounters{
+		key: serviceMetricKey{
+			namespace: vipPort.service.Namespace,
+			name:      vipPort.service.Name,
+			vip:       dst.ip,
+			port:      strconv.Itoa(int(dst.port)),
+			protocol:  string(vipPort.protocol),
+		},
+		packets: flow.Forward.Packets + flow.Reverse.Packets,
+		bytes:   flow.Forward.Bytes + flow.Reverse.Bytes,
+	}
+	return key, counters, true
+}
+
+func (c *serviceMetricsCollector) collect() error {
+	services, err := c.getServices()
+	if err != nil {
+		return err
+	}
+	vips, nodePorts := buildServiceVIPs(services, c.getNodeIPs())
+
+	// the entries of the connections destroyed while listing are stale, their last counters were
+	// added by the DESTROY events
+	c.Lock()
+	c.destroyed = map[serviceConnKey]struct{}{}
+	c.Unlock()
+	defer func() {
+		c.Lock()
+		defer c.Unlock()
+		c.destroyed = nil
+	}()
+	var flows []*netlink.ConntrackFlow
+	if config.IPv4Mode {
+		v4Flows, err := util.GetNetLinkOps().ConntrackTableList(netlink.ConntrackTable, netlink.FAMILY_V4)
+		if err != nil {
+			return err
+		}
+		flows = append(flows, v4Flows...)
+	}
+	if config.IPv6Mode {
+		v6Flows, err := util.GetNetLinkOps().ConntrackTableList(netlink.ConntrackTable, netlink.FAMILY_V6)
+		if err != nil {
+			return err
+		}
+		flows = append(flows, v6Flows...)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.vips, c.nodePorts = vips, nodePorts
+	connections := make(map[serviceConnKey]serviceConnCounters, len(c.connections))
+	for _, flow := range flows {
+		key, counters, ok := c.getServiceConnection(flow)
+		if !ok {
+			continue
+		}
+		if _, ok := c.destroyed[key]; ok {
+			continue
+		}
+		connections[key] = counters
+		c.metricKeys[counters.key] = struct{}{}
+
+		// the connections are counted by the NEW events, the entries that were already there when the
+		// collector started are not
+		labels := counters.key.labelValues()
+		previous := c.connections[key]
+		if previous.key != counters.key {
+			previous = serviceConnCounters{}
+		}
+		addCounterDelta(metrics.MetricServicePackets.WithLabelValues(labels...), previous.packets, counters.packets)
+		addCounterDelta(metrics.MetricServiceBytes.WithLabelValues(labels...), previous.bytes, counters.bytes)
+	}
+
+	// forget the metrics of the deleted services
+	servicesByKey := make(map[string]struct{}, len(services))
+	for _, service := range services {
+		servicesByKey[service.Namespace+"/"+service.Name] = struct{}{}
+	}
+	for key := range c.metricKeys {
+		if _, ok := servicesByKey[key.namespace+"/"+key.name]; ok {
+			continue
+		}
+		delete(c.metricKeys, key)
+		metrics.MetricServiceConnections.DeleteLabelValues(key.labelValues()...)
+		metrics.MetricServicePackets.DeleteLabelValues(key.labelValues()...)
+		metrics.MetricServiceBytes.DeleteLabelValues(key.labelValues()...)
+	}
+
+	c.connections = connections
+	return nil
+}
+
+// parseConntrackEvent parses the tuples, zone and counters of the conntrack entry of a ctnetlink event.
+func parseConntrackEvent(data []byte) (*netlink.ConntrackFlow, error) {
+	if len(data) < nl.SizeofNfgenmsg {
+		return nil, fmt.Errorf("truncated ctnetlink message")
+	}
+	flow := &netlink.ConntrackFlow{FamilyType: data[0]}
+	attrs, err := nl.ParseRouteAttr(data[nl.SizeofNfgenmsg:])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_ORIG:
+			err = parseConntrackTuple(attr.Value, &flow.Forward)
+		case nl.CTA_TUPLE_REPLY:
+			err = parseConntrackTuple(attr.Value, &flow.Reverse)
+		case nl.CTA_COUNTERS_ORIG:
+			err = parseConntrackCounters(attr.Value, &flow.Forward)
+		case nl.CTA_COUNTERS_REPLY:
+			err = parseConntrackCounters(attr.Value, &flow.Reverse)
+		case nl.CTA_ZONE:
+			if len(attr.Value) < 2 {
+				return nil, fmt.Errorf("truncated conntrack zone")
+			}
+			flow.Zone = binary.BigEndian.Uint16(attr.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return flow, nil
+}
+
+func parseConntrackTuple(data []byte, tuple *netlink.IPTuple) error {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		var nested []syscall.NetlinkRouteAttr
+		nested, err = nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return err
+		}
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.CTA_TUPLE_IP:
+			for _, ipAttr := range nested {
+				switch ipAttr.Attr.Type {
+				case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
+					tuple.SrcIP = net.IP(ipAttr.Value)
+				case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
+					tuple.DstIP = net.IP(ipAttr.Value)
+				}
+			}
+		case nl.CTA_TUPLE_PROTO:
+			for _, protoAttr := range nested {
+				switch {
+				case protoAttr.Attr.Type == nl.CTA_PROTO_NUM && len(protoAttr.Value) >= 1:
+					tuple.Protocol = protoAttr.Value[0]
+				case protoAttr.Attr.Type == nl.CTA_PROTO_SRC_PORT && len(protoAttr.Value) >= 2:
+					tuple.SrcPort = binary.BigEndian.Uint16(protoAttr.Value)
+				case protoAttr.Attr.Type == nl.CTA_PROTO_DST_PORT && len(protoAttr.Value) >= 2:
+					tuple.DstPort = binary.BigEndian.Uint16(protoAttr.Value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func parseConntrackCounters(data []byte, tuple *netlink.IPTuple) error {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return err
+	}
+	for _, attr := range attrs {
+		if len(attr.Value) < 8 {
+			continue
+		}
+		switch attr.Attr.Type {
+		case ctaCountersPackets:
+			tuple.Packets = binary.BigEndian.Uint64(attr.Value)
+		case ctaCountersBytes:
+			tuple.Bytes = binary.BigEndian.Uint64(attr.Value)
+		}
+	}
+	return nil
+}
+
+func addCounterDelta(counter prometheus.Counter, previous, current uint64) {
+	if current > previous {
+		counter.Add(float64(current - previous))
+	}
+}
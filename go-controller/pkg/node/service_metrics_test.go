@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package node
+
+import (
+	"net"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/metrics"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+	util_mocks "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util/mocks"
+)
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	require.NoError(t, counter.Write(m))
+	return m.GetCounter().GetValue()
+}
+
+func TestServiceMetricsCollector(t *testing.T) {
+	mockNetLinkOps := new(util_mocks.NetLinkOps)
+	util.SetNetLinkOpMockInst(mockNetLinkOps)
+	defer util.ResetNetLinkOpMockInst()
+
+	oldIPv4Mode, oldIPv6Mode := config.IPv4Mode, config.IPv6Mode
+	oldServiceCIDRs := config.Kubernetes.ServiceCIDRs
+	defer func() {
+		config.IPv4Mode, config.IPv6Mode = oldIPv4Mode, oldIPv6Mode
+		config.Kubernetes.ServiceCIDRs = oldServiceCIDRs
+	}()
+	config.IPv4Mode, config.IPv6Mode = true, false
+	_, serviceCIDR, _ := net.ParseCIDR("10.96.0.0/16")
+	config.Kubernetes.ServiceCIDRs = []*net.IPNet{serviceCIDR}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "metrics-ns"},
+		Spec: corev1.ServiceSpec{
+			Type:       corev1.ServiceTypeNodePort,
+			ClusterIP:  "10.96.0.10",
+			ClusterIPs: []string{"10.96.0.10"},
+			Ports: []corev1.ServicePort{
+				{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080},
+			},
+		},
+	}
+	services := []*corev1.Service{service}
+	nodeIPs := []net.IP{net.ParseIP("172.18.0.2")}
+	collector := newServiceMetricsCollector(func() ([]*corev1.Service, error) { return services, nil },
+		func() []net.IP { return nodeIPs })
+
+	flow := func(dstIP string, dstPort uint16, backendIP string, backendPort uint16, packets, bytes uint64) *netlink.ConntrackFlow {
+		return &netlink.ConntrackFlow{
+			Forward: netlink.IPTuple{
+				Protocol: unix.IPPROTO_TCP,
+				SrcIP:    net.ParseIP("10.244.0.5"),
+				SrcPort:  40000,
+				DstIP:    net.ParseIP(dstIP),
+				DstPort:  dstPort,
+				Packets:  packets,
+				Bytes:    bytes,
+			},
+			Reverse: netlink.IPTuple{
+				Protocol: unix.IPPROTO_TCP,
+				SrcIP:    net.ParseIP(backendIP),
+				SrcPort:  backendPort,
+				DstIP:    net.ParseIP("10.244.0.5"),
+				DstPort:  40000,
+				Packets:  packets,
+				Bytes:    bytes,
+			},
+		}
+	}
+	clusterIPLabels := []string{"metrics-ns", "svc", "10.96.0.10", "80", "TCP"}
+	nodePortLabels := []string{"metrics-ns", "svc", "172.18.0.2", "30080", "TCP"}
+	otherNodePortLabels := []string{"metrics-ns", "svc", "172.18.0.3", "30080", "TCP"}
+
+	// an entry was already there when the collector started, its traffic is counted but not the connection
+	mockNetLinkOps.On("ConntrackTableList", netlink.ConntrackTableType(netlink.ConntrackTable), netlink.InetFamily(netlink.FAMILY_V4)).Return(
+		[]*netlink.ConntrackFlow{
+			flow("10.96.0.10", 80, "10.244.1.3", 8080, 2, 100),
+		}, nil).Once()
+	require.NoError(t, collector.collect())
+	assert.Equal(t, 0.0, counterValue(t, metrics.MetricServiceConnections.WithLabelValues(clusterIPLabels...)))
+	assert.Equal(t, 4.0, counterValue(t, metrics.MetricServicePackets.WithLabelValues(clusterIPLabels...)))
+	assert.Equal(t, 200.0, counterValue(t, metrics.MetricServiceBytes.WithLabelValues(clusterIPLabels...)))
+
+	// it is destroyed with more traffic
+	collector.handleConntrackEvent(true, flow("10.96.0.10", 80, "10.244.1.3", 8080, 3, 150))
+	assert.Equal(t, 6.0, counterValue(t, metrics.MetricServicePackets.WithLabelValues(clusterIPLabels...)))
+	assert.Equal(t, 300.0, counterValue(t, metrics.MetricServiceBytes.WithLabelValues(clusterIPLabels...)))
+
+	// a short-lived connection to the cluster IP is counted even if it never shows up in a collection,
+	// so are the connections to the node port on a node IP, but not on other IPs, and not the entries
+	// that were not DNATed
+	collector.handleConntrackEvent(false, flow("10.96.0.10", 80, "10.244.1.3", 8080, 0, 0))
+	collector.handleConntrackEvent(false, flow("172.18.0.2", 30080, "10.244.1.3", 8080, 0, 0))
+	collector.handleConntrackEvent(false, flow("172.18.0.3", 30080, "10.244.1.3", 8080, 0, 0))
+	collector.handleConntrackEvent(false, flow("10.96.0.10", 80, "10.96.0.10", 80, 0, 0))
+	collector.handleConntrackEvent(true, flow("10.96.0.10", 80, "10.244.1.3", 8080, 1, 60))
+	assert.Equal(t, 1.0, counterValue(t, metrics.MetricServiceConnections.WithLabelValues(clusterIPLabels...)))
+	assert.Equal(t, 8.0, counterValue(t, metrics.MetricServicePackets.WithLabelValues(clusterIPLabels...)))
+	assert.Equal(t, 420.0, counterValue(t, metrics.MetricServiceBytes.WithLabelValues(clusterIPLabels...)))
+	assert.Equal(t, 1.0, counterValue(t, metrics.MetricServiceConnections.WithLabelValues(nodePortLabels...)))
+	assert.False(t, metrics.MetricServiceConnections.DeleteLabelValues(otherNodePortLabels...))
+
+	// the node port connection is still there with some traffic
+	mockNetLinkOps.On("ConntrackTableList", netlink.ConntrackTableType(netlink.ConntrackTable), netlink.InetFamily(netlink.FAMILY_V4)).Return(
+		[]*netlink.ConntrackFlow{
+			flow("172.18.0.2", 30080, "10.244.1.3", 8080, 5, 300),
+		}, nil).Once()
+	require.NoError(t, collector.collect())
+	assert.Equal(t, 1.0, counterValue(t, metrics.MetricServiceConnections.WithLabelValues(nodePortLabels...)))
+	assert.Equal(t, 10.0, counterValue(t, metrics.MetricServicePackets.WithLabelValues(nodePortLabels...)))
+	assert.Equal(t, 600.0, counterValue(t, metrics.MetricServiceBytes.WithLabelValues(nodePortLabels...)))
+
+	// the node port connection is destroyed while the conntrack entries are listed, its stale entry
+	// is not counted again
+	mockNetLinkOps.On("ConntrackTableList", netlink.ConntrackTableType(netlink.ConntrackTable), netlink.InetFamily(netlink.FAMILY_V4)).Return(
+		[]*netlink.ConntrackFlow{
+			flow("172.18.0.2", 30080, "10.244.1.3", 8080, 6, 360),
+		}, nil).Run(func(mock.Arguments) {
+		collector.handleConntrackEvent(true, flow("172.18.0.2", 30080, "10.244.1.3", 8080, 7, 420))
+	}).Once()
+	require.NoError(t, collector.collect())
+	assert.Equal(t, 14.0, counterValue(t, metrics.MetricServicePackets.WithLabelValues(nodePortLabels...)))
+	assert.Equal(t, 840.0, counterValue(t, metrics.MetricServiceBytes.WithLabelValues(nodePortLabels...)))
+	assert.Empty(t, collector.connections)
+
+	// the service is deleted, its metrics are removed
+	services = nil
+	mockNetLinkOps.On("ConntrackTableList", netlink.ConntrackTableType(netlink.ConntrackTable), netlink.InetFamily(netlink.FAMILY_V4)).Return(
+		[]*netlink.ConntrackFlow{}, nil).Once()
+	require.NoError(t, collector.collect())
+	assert.False(t, metrics.MetricServiceConnections.DeleteLabelValues(clusterIPLabels...))
+	assert.False(t, metrics.MetricServiceConnections.DeleteLabelValues(nodePortLabels...))
+	mockNetLinkOps.AssertExpectations(t)
+}
+
+func TestParseConntrackEvent(t *testing.T) {
+	tuple := func(attrType int, srcIP, dstIP string, srcPort, dstPort uint16) *nl.RtAttr {
+		tupleAttr := nl.NewRtAttr(unix.NLA_F_NESTED|attrType, nil)
+		ipAttr := tupleAttr.AddRtAttr(unix.NLA_F_NESTED|nl.CTA_TUPLE_IP, nil)
+		ipAttr.AddRtAttr(nl.CTA_IP_V4_SRC, net.ParseIP(srcIP).To4())
+		ipAttr.AddRtAttr(nl.CTA_IP_V4_DST, net.ParseIP(dstIP).To4())
+		protoAttr := tupleAttr.AddRtAttr(unix.NLA_F_NESTED|nl.CTA_TUPLE_PROTO, nil)
+		protoAttr.AddRtAttr(nl.CTA_PROTO_NUM, []byte{unix.IPPROTO_UDP})
+		protoAttr.AddRtAttr(nl.CTA_PROTO_SRC_PORT, nl.BEUint16Attr(srcPort))
+		protoAttr.AddRtAttr(nl.CTA_PROTO_DST_PORT, nl.BEUint16Attr(dstPort))
+		return tupleAttr
+	}
+	counters := func(attrType int, packets, bytes uint64) *nl.RtAttr {
+		countersAttr := nl.NewRtAttr(unix.NLA_F_NESTED|attrType, nil)
+		countersAttr.AddRtAttr(ctaCountersPackets, nl.BEUint64Attr(packets))
+		countersAttr.AddRtAttr(ctaCountersBytes, nl.BEUint64Attr(bytes))
+		return countersAttr
+	}
+	data := []byte{unix.AF_INET, nl.NFNETLINK_V0, 0, 0}
+	for _, attr := range []*nl.RtAttr{
+		tuple(nl.CTA_TUPLE_ORIG, "10.244.0.5", "10.96.0.10", 40000, 53),
+		tuple(nl.CTA_TUPLE_REPLY, "10.244.1.3", "10.244.0.5", 5353, 40000),
+		nl.NewRtAttr(nl.CTA_ZONE, nl.BEUint16Attr(12)),
+		counters(nl.CTA_COUNTERS_ORIG, 1, 60),
+		counters(nl.CTA_COUNTERS_REPLY, 2, 200),
+	} {
+		data = append(data, attr.Serialize()...)
+	}
+
+	flow, err := parseConntrackEvent(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(unix.AF_INET), flow.FamilyType)
+	assert.Equal(t, uint16(12), flow.Zone)
+	assert.Equal(t, netlink.IPTuple{
+		Protocol: unix.IPPROTO_UDP,
+		SrcIP:    net.ParseIP("10.244.0.5").To4(),
+		SrcPort:  40000,
+		DstIP:    net.ParseIP("10.96.0.10").To4(),
+		DstPort:  53,
+		Packets:  1,
+		Bytes:    60,
+	}, flow.Forward)
+	assert.Equal(t, netlink.IPTuple{
+		Protocol: unix.IPPROTO_UDP,
+		SrcIP:    net.ParseIP("10.244.1.3").To4(),
+		SrcPort:  5353,
+		DstIP:    net.ParseIP("10.244.0.5").To4(),
+		DstPort:  40000,
+		Packets:  2,
+		Bytes:    200,
+	}, flow.Reverse)
+
+	_, err = parseConntrackEvent([]byte{unix.AF_INET})
+	assert.Error(t, err)
+}
@@ -8,16 +8,25 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	libovsdbclient "github.com/ovn-kubernetes/libovsdb/client"
 	"github.com/ovn-kubernetes/libovsdb/ovsdb"
 
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/controller"
+	observabilityv1 "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1"
+	observabilityinformer "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1/apis/informers/externalversions/observability/v1"
+	observabilitylister "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1/apis/listers/observability/v1"
 	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util/batching"
 )
 
 // OVN observ app IDs. Make sure to always add new apps in the end.
@@ -27,13 +36,16 @@ const (
 	ACLEstTrafficSamplingID
 )
 
-// temporary const, until we have dynamic config
+// DefaultObservabilityCollectorSetID is used for all features when no SamplingConfig exists.
 const DefaultObservabilityCollectorSetID = 42
 
 // this is inferred from nbdb schema, check Sample_Collector.id
 const maxCollectorID = 255
 const collectorFeaturesExternalID = "sample-features"
 
+// aclResampleBatchSize limits the number of ACLs updated in one transaction when the sampling config changes.
+const aclResampleBatchSize = 500
+
 // collectorConfig holds the configuration for a collector.
 // It is allowed to set different probabilities for every feature.
 // collectorSetID is used to set up sampling via OVSDB.
@@ -41,16 +53,20 @@ type collectorConfig struct {
 	collectorSetID int
 	// probability in percent, 0 to 100
 	featuresProbability map[libovsdbops.SampleFeature]int
+	// scope limits sampling to the objects of given namespaces and networks, nil means no limits.
+	scope *libovsdbops.SamplingScope
 }
 
 type Manager struct {
 	nbClient       libovsdbclient.Client
-	sampConfig     *libovsdbops.SamplingConfig
+	sampConfig     atomic.Pointer[libovsdbops.SamplingConfig]
 	collectorsLock sync.Mutex
 	// nbdb Collectors have probability. To allow different probabilities for different features,
 	// multiple nbdb Collectors will be created, one per probability.
 	// getCollectorKey() => collector.UUID
 	dbCollectors map[string]string
+	// getCollectorKey() => collector.ID
+	dbCollectorIDs map[string]int
 	// cleaning up unused collectors may take time and multiple retries, as all referencing samples must be removed first.
 	// Therefore, we need to save state between those retries.
 	// getCollectorKey() => collector.SetID
@@ -60,26 +76,70 @@ type Manager struct {
 	// Only maxCollectorID collectors are allowed, each should have unique ID.
 	// this set is tracking already assigned IDs.
 	takenCollectorIDs sets.Set[int]
+
+	// samplingConfigLister is nil when SamplingConfigs are not watched, then the default config is used.
+	samplingConfigLister     observabilitylister.SamplingConfigLister
+	samplingConfigController controller.Controller
+	// configLock serializes sampling config updates
+	configLock sync.Mutex
 }
 
-func NewManager(nbClient libovsdbclient.Client) *Manager {
-	return &Manager{
+// NewManager creates a new observability Manager. When samplingConfigInformer is nil, the default config
+// is used, otherwise the config is built from all SamplingConfigs and updated on every change.
+func NewManager(nbClient libovsdbclient.Client, samplingConfigInformer observabilityinformer.SamplingConfigInformer) *Manager {
+	m := &Manager{
 		nbClient:                      nbClient,
 		collectorsLock:                sync.Mutex{},
 		dbCollectors:                  make(map[string]string),
+		dbCollectorIDs:                make(map[string]int),
 		unusedCollectors:              make(map[string]int),
 		unusedCollectorsRetryInterval: time.Minute,
 		takenCollectorIDs:             sets.New[int](),
 	}
+	if samplingConfigInformer != nil {
+		m.samplingConfigLister = samplingConfigInformer.Lister()
+		m.samplingConfigController = controller.NewController[observabilityv1.SamplingConfig](
+			"observability-sampling-config",
+			&controller.ControllerConfig[observabilityv1.SamplingConfig]{
+				RateLimiter:    controller.DefaultRateLimiter[string](),
+				Informer:       samplingConfigInformer.Informer(),
+				Lister:         samplingConfigInformer.Lister().List,
+				Reconcile:      m.reconcileSamplingConfig,
+				ObjNeedsUpdate: samplingConfigNeedsUpdate,
+				Threadiness:    1,
+			},
+		)
+	}
+	return m
 }
 
 func (m *Manager) SamplingConfig() *libovsdbops.SamplingConfig {
-	return m.sampConfig
+	return m.sampConfig.Load()
 }
 
 func (m *Manager) Init() error {
-	// this will be read from the kube-api in the future
-	currentConfig := &collectorConfig{
+	if m.samplingConfigLister == nil {
+		return m.initWithConfig(defaultCollectorConfig())
+	}
+	configs, err := m.getCollectorConfigs()
+	if err != nil {
+		return err
+	}
+	if err = m.initWithConfigs(configs); err != nil {
+		return err
+	}
+	return controller.Start(m.samplingConfigController)
+}
+
+// Stop stops watching SamplingConfigs.
+func (m *Manager) Stop() {
+	if m.samplingConfigController != nil {
+		controller.Stop(m.samplingConfigController)
+	}
+}
+
+func defaultCollectorConfig() *collectorConfig {
+	return &collectorConfig{
 		collectorSetID: DefaultObservabilityCollectorSetID,
 		featuresProbability: map[libovsdbops.SampleFeature]int{
 			libovsdbops.EgressFirewallSample:     100,
@@ -89,11 +149,13 @@ func (m *Manager) Init() error {
 			libovsdbops.UDNIsolationSample:       100,
 		},
 	}
-
-	return m.initWithConfig(currentConfig)
 }
 
 func (m *Manager) initWithConfig(config *collectorConfig) error {
+	return m.initWithConfigs([]*collectorConfig{config})
+}
+
+func (m *Manager) initWithConfigs(configs []*collectorConfig) error {
 	if err := m.setSamplingAppIDs(); err != nil {
 		return err
 	}
@@ -101,17 +163,141 @@ func (m *Manager) initWithConfig(config *collectorConfig) error {
 		return err
 	}
 
-	featuresConfig, err := m.addCollector(config)
+	sampConfig, err := m.addCollectors(configs)
 	if err != nil {
 		return err
 	}
-	m.sampConfig = libovsdbops.NewSamplingConfig(featuresConfig)
+	m.sampConfig.Store(sampConfig)
 
 	// now cleanup stale collectors
 	m.deleteStaleCollectorsWithRetry()
 	return nil
 }
 
+// reconcileSamplingConfig is called on every SamplingConfig change. SamplingConfigs are merged, so the whole config
+// is rebuilt, applied to the existing ACLs, and the collectors that are not used anymore are deleted.
+func (m *Manager) reconcileSamplingConfig(key string) error {
+	m.configLock.Lock()
+	defer m.configLock.Unlock()
+	klog.V(5).Infof("Reconciling observability config on SamplingConfig %s change", key)
+
+	configs, err := m.getCollectorConfigs()
+	if err != nil {
+		return err
+	}
+	m.collectorsLock.Lock()
+	// all collectors are unused, until we update existing configs
+	for collectorKey, collectorID := range m.dbCollectorIDs {
+		m.unusedCollectors[collectorKey] = collectorID
+	}
+	m.collectorsLock.Unlock()
+
+	sampConfig, err := m.addCollectors(configs)
+	if err != nil {
+		return err
+	}
+	m.sampConfig.Store(sampConfig)
+
+	if err = m.resampleACLs(sampConfig); err != nil {
+		return err
+	}
+	m.deleteStaleCollectorsWithRetry()
+	return nil
+}
+
+func samplingConfigNeedsUpdate(oldObj, newObj *observabilityv1.SamplingConfig) bool {
+	if oldObj == nil || newObj == nil {
+		return true
+	}
+	return oldObj.Generation != newObj.Generation
+}
+
+// getCollectorConfigs builds collector configs from all SamplingConfigs. SamplingConfigs are processed in name order,
+// if a collector set is configured more than once, only the first config is used.
+func (m *Manager) getCollectorConfigs() ([]*collectorConfig, error) {
+	samplingConfigs, err := m.samplingConfigLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SamplingConfigs: %w", err)
+	}
+	if len(samplingConfigs) == 0 {
+		return []*collectorConfig{defaultCollectorConfig()}, nil
+	}
+	slices.SortFunc(samplingConfigs, func(a, b *observabilityv1.SamplingConfig) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	configs := []*collectorConfig{}
+	collectorSetIDs := sets.New[int]()
+	for _, samplingConfig := range samplingConfigs {
+		for _, collector := range samplingConfig.Spec.Collectors {
+			collectorSetID := int(collector.CollectorSetID)
+			if collectorSetIDs.Has(collectorSetID) {
+				klog.Errorf("Ignoring collector set %d in SamplingConfig %s: collector set is already configured",
+					collectorSetID, samplingConfig.Name)
+				continue
+			}
+			collectorSetIDs.Insert(collectorSetID)
+			config := &collectorConfig{
+				collectorSetID:      collectorSetID,
+				featuresProbability: map[libovsdbops.SampleFeature]int{},
+			}
+			for _, feature := range collector.Features {
+				probability := int(feature.Probability)
+				if probability == 0 {
+					probability = 100
+				}
+				config.featuresProbability[libovsdbops.SampleFeature(feature.Feature)] = probability
+			}
+			if len(collector.Namespaces) > 0 || len(collector.Networks) > 0 {
+				config.scope = &libovsdbops.SamplingScope{
+					Namespaces: sets.New(collector.Namespaces...),
+					Networks:   sets.New[string](),
+				}
+				for _, network := range collector.Networks {
+					config.scope.Networks.Insert(getNetworkName(network))
+				}
+			}
+			configs = append(configs, config)
+		}
+	}
+	return configs, nil
+}
+
+// getNetworkName converts the network reference used by SamplingConfig to the network name.
+func getNetworkName(network string) string {
+	if network == types.DefaultNetworkName {
+		return network
+	}
+	if namespace, name, found := strings.Cut(network, "/"); found {
+		return util.GenerateUDNNetworkName(namespace, name)
+	}
+	return util.GenerateCUDNNetworkName(network)
+}
+
+// resampleACLs updates samples of all ACLs owned by sampled features according to the given config.
+// Controllers apply the latest config on every ACL update, this makes sure that unchanged ACLs are updated too.
+func (m *Manager) resampleACLs(sampConfig *libovsdbops.SamplingConfig) error {
+	acls, err := libovsdbops.FindACLsWithPredicate(m.nbClient, func(acl *nbdb.ACL) bool {
+		return libovsdbops.GetACLSampleFeature(acl) != ""
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find sampled ACLs: %w", err)
+	}
+	// don't modify cached objects
+	aclsCopy := make([]*nbdb.ACL, 0, len(acls))
+	for _, acl := range acls {
+		aclsCopy = append(aclsCopy, acl.DeepCopy())
+	}
+	return batching.Batch[*nbdb.ACL](aclResampleBatchSize, aclsCopy, func(batchACLs []*nbdb.ACL) error {
+		ops, err := libovsdbops.UpdateACLsSamplesOps(m.nbClient, nil, sampConfig, batchACLs...)
+		if err != nil {
+			return fmt.Errorf("failed to update ACL samples: %w", err)
+		}
+		_, err = libovsdbops.TransactAndCheck(m.nbClient, ops)
+		return err
+	})
+}
+
 func (m *Manager) setDbCollectors() error {
 	m.collectorsLock.Lock()
 	defer m.collectorsLock.Unlock()
@@ -123,6 +309,7 @@ func (m *Manager) setDbCollectors() error {
 	for _, collector := range collectors {
 		collectorKey := getCollectorKey(collector.SetID, collector.Probability)
 		m.dbCollectors[collectorKey] = collector.UUID
+		m.dbCollectorIDs[collectorKey] = collector.ID
 		m.takenCollectorIDs.Insert(collector.ID)
 		// all collectors are unused, until we update existing configs
 		m.unusedCollectors[collectorKey] = collector.ID
@@ -166,6 +353,7 @@ func (m *Manager) deleteStaleCollectors() error {
 		}
 		delete(m.unusedCollectors, collectorKey)
 		delete(m.dbCollectors, collectorKey)
+		delete(m.dbCollectorIDs, collectorKey)
 		delete(m.takenCollectorIDs, collectorSetID)
 	}
 	return lastErr
@@ -247,6 +435,31 @@ func (m *Manager) getFreeCollectorID() (int, error) {
 	return 0, fmt.Errorf("no free collector IDs")
 }
 
+// addCollectors creates or updates nbdb collectors for all given configs, and returns the resulting SamplingConfig.
+func (m *Manager) addCollectors(configs []*collectorConfig) (*libovsdbops.SamplingConfig, error) {
+	featureCollectors := make(map[libovsdbops.SampleFeature][]string)
+	collectorScopes := make(map[string]*libovsdbops.SamplingScope)
+	for _, conf := range configs {
+		featuresConfig, err := m.addCollector(conf)
+		if err != nil {
+			return nil, err
+		}
+		for feature, collectors := range featuresConfig {
+			featureCollectors[feature] = append(featureCollectors[feature], collectors...)
+			if conf.scope == nil {
+				continue
+			}
+			for _, collectorUUID := range collectors {
+				collectorScopes[collectorUUID] = conf.scope
+			}
+		}
+	}
+	if len(collectorScopes) == 0 {
+		return libovsdbops.NewSamplingConfig(featureCollectors), nil
+	}
+	return libovsdbops.NewScopedSamplingConfig(featureCollectors, collectorScopes), nil
+}
+
 func (m *Manager) addCollector(conf *collectorConfig) (map[libovsdbops.SampleFeature][]string, error) {
 	m.collectorsLock.Lock()
 	defer m.collectorsLock.Unlock()
@@ -279,6 +492,7 @@ func (m *Manager) addCollector(conf *collectorConfig) (map[libovsdbops.SampleFea
 			}
 			collectorUUID = collector.UUID
 			m.dbCollectors[collectorKey] = collectorUUID
+			m.dbCollectorIDs[collectorKey] = collectorID
 			m.takenCollectorIDs.Insert(collectorID)
 		} else {
 			// update collector's features
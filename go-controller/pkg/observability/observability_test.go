@@ -4,11 +4,17 @@
 package observability
 
 import (
+	"context"
 	"strings"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	libovsdbclient "github.com/ovn-kubernetes/libovsdb/client"
 
+	observabilityv1 "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1"
+	observabilityfake "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1/apis/clientset/versioned/fake"
+	observabilityinformerfactory "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1/apis/informers/externalversions"
 	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
 	libovsdbtest "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
@@ -33,7 +39,7 @@ var _ = Describe("Observability Manager", func() {
 		nbClient, _, libovsdbCleanup, err = libovsdbtest.NewNBSBTestHarness(libovsdbtest.TestSetup{
 			NBData: data})
 		Expect(err).NotTo(HaveOccurred())
-		manager = NewManager(nbClient)
+		manager = NewManager(nbClient, nil)
 		err = manager.Init()
 		Expect(err).NotTo(HaveOccurred())
 	}
@@ -262,7 +268,7 @@ var _ = Describe("Observability Manager", func() {
 			nbClient, _, libovsdbCleanup, err = libovsdbtest.NewNBSBTestHarness(libovsdbtest.TestSetup{
 				NBData: data})
 			Expect(err).NotTo(HaveOccurred())
-			manager = NewManager(nbClient)
+			manager = NewManager(nbClient, nil)
 			// tweak retry interval for testing
 			manager.unusedCollectorsRetryInterval = time.Second
 			err = manager.initWithConfig(config)
@@ -376,4 +382,164 @@ var _ = Describe("Observability Manager", func() {
 			Eventually(nbClient, 2*manager.unusedCollectorsRetryInterval).Should(libovsdbtest.HaveData(expectedDB))
 		})
 	})
+
+	When("SamplingConfigs are watched", func() {
+		var (
+			fakeClient *observabilityfake.Clientset
+			stopCh     chan struct{}
+		)
+
+		startManagerWithSamplingConfigs := func(data []libovsdbtest.TestData, samplingConfigs ...*observabilityv1.SamplingConfig) {
+			var err error
+			nbClient, _, libovsdbCleanup, err = libovsdbtest.NewNBSBTestHarness(libovsdbtest.TestSetup{
+				NBData: data})
+			Expect(err).NotTo(HaveOccurred())
+			fakeClient = observabilityfake.NewSimpleClientset()
+			for _, samplingConfig := range samplingConfigs {
+				_, err = fakeClient.K8sV1().SamplingConfigs().Create(context.TODO(), samplingConfig, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			informerFactory := observabilityinformerfactory.NewSharedInformerFactory(fakeClient, 0)
+			samplingConfigInformer := informerFactory.K8s().V1().SamplingConfigs()
+			samplingConfigInformer.Informer()
+			stopCh = make(chan struct{})
+			informerFactory.Start(stopCh)
+			informerFactory.WaitForCacheSync(stopCh)
+
+			manager = NewManager(nbClient, samplingConfigInformer)
+			// tweak retry interval for testing
+			manager.unusedCollectorsRetryInterval = time.Second
+			err = manager.Init()
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		newSamplingConfig := func(name string, collectors ...observabilityv1.SamplingCollector) *observabilityv1.SamplingConfig {
+			return &observabilityv1.SamplingConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec:       observabilityv1.SamplingConfigSpec{Collectors: collectors},
+			}
+		}
+
+		newNetpolACL := func(uuid, namespace string) *nbdb.ACL {
+			return &nbdb.ACL{
+				UUID: uuid,
+				ExternalIDs: map[string]string{
+					libovsdbops.OwnerTypeKey.String():       libovsdbops.NetworkPolicyOwnerType,
+					libovsdbops.ObjectNameKey.String():      namespace + ":policy",
+					libovsdbops.OwnerControllerKey.String(): "default-network-controller",
+				},
+			}
+		}
+
+		AfterEach(func() {
+			manager.Stop()
+			close(stopCh)
+		})
+
+		It("should use the default config when no SamplingConfig exists", func() {
+			startManagerWithSamplingConfigs(nil)
+			Eventually(nbClient).Should(libovsdbtest.HaveData(initialDB))
+		})
+
+		It("should only sample ACLs in the configured namespaces", func() {
+			samplingConfig := newSamplingConfig("ns1-only", observabilityv1.SamplingCollector{
+				CollectorSetID: 10,
+				Features: []observabilityv1.FeatureSampling{
+					{Feature: observabilityv1.NetworkPolicySampling, Probability: 100},
+				},
+				Namespaces: []string{"ns1"},
+			})
+			startManagerWithSamplingConfigs(samplingApps, samplingConfig)
+			newCollector := &nbdb.SampleCollector{
+				UUID:        collectorUUID,
+				ID:          1,
+				SetID:       10,
+				Probability: 65535,
+				ExternalIDs: map[string]string{
+					collectorFeaturesExternalID: libovsdbops.NetworkPolicySample,
+				},
+			}
+
+			ns1ACL := newNetpolACL("acl1-uuid", "ns1")
+			ns2ACL := newNetpolACL("acl2-uuid", "ns2")
+			ops, err := libovsdbops.CreateOrUpdateACLsOps(nbClient, nil, manager.SamplingConfig(), ns1ACL, ns2ACL)
+			Expect(err).NotTo(HaveOccurred())
+			pg := &nbdb.PortGroup{
+				UUID: "pg-uuid",
+				ACLs: []string{ns1ACL.UUID, ns2ACL.UUID},
+			}
+			ops, err = libovsdbops.CreateOrUpdatePortGroupsOps(nbClient, ops, pg)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = libovsdbops.TransactAndCheck(nbClient, ops)
+			Expect(err).NotTo(HaveOccurred())
+
+			sample := &nbdb.Sample{
+				UUID:       "sample-uuid",
+				Metadata:   int(libovsdbops.GetACLSampleID(ns1ACL)),
+				Collectors: []string{newCollector.UUID},
+			}
+			ns1ACL.SampleNew = &sample.UUID
+			ns1ACL.SampleEst = &sample.UUID
+			expectedDB := append(samplingApps, newCollector, sample, pg, ns1ACL, ns2ACL)
+			Eventually(nbClient).Should(libovsdbtest.HaveData(expectedDB))
+		})
+
+		It("should update existing ACLs and cleanup stale collectors on SamplingConfig change", func() {
+			acl := newNetpolACL("acl-uuid", "ns1")
+			pg := &nbdb.PortGroup{
+				UUID: "pg-uuid",
+				ACLs: []string{acl.UUID},
+			}
+			sample := &nbdb.Sample{
+				UUID:       "sample-uuid",
+				Metadata:   int(libovsdbops.GetACLSampleID(acl)),
+				Collectors: []string{collectorUUID},
+			}
+			acl.SampleNew = &sample.UUID
+			acl.SampleEst = &sample.UUID
+			// start with the default config, acl is sampled by the default collector
+			startManagerWithSamplingConfigs(append(initialDB, sample, pg, acl))
+			Consistently(nbClient).Should(libovsdbtest.HaveData(append(initialDB, sample, pg, acl)))
+
+			samplingConfig := newSamplingConfig("netpol", observabilityv1.SamplingCollector{
+				CollectorSetID: 10,
+				Features: []observabilityv1.FeatureSampling{
+					{Feature: observabilityv1.NetworkPolicySampling, Probability: 50},
+				},
+			})
+			_, err := fakeClient.K8sV1().SamplingConfigs().Create(context.TODO(), samplingConfig, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			newCollector := &nbdb.SampleCollector{
+				UUID:        collectorUUID + "-2",
+				ID:          2,
+				SetID:       10,
+				Probability: 32767,
+				ExternalIDs: map[string]string{
+					collectorFeaturesExternalID: libovsdbops.NetworkPolicySample,
+				},
+			}
+			// the acl sample is updated without the netpol controller, so the default collector can be deleted
+			sample.Collectors = []string{newCollector.UUID}
+			expectedDB := append(samplingApps, newCollector, sample, pg, acl)
+			Eventually(nbClient, 2*manager.unusedCollectorsRetryInterval).Should(libovsdbtest.HaveData(expectedDB))
+
+			// deleting the last SamplingConfig restores the default config
+			err = fakeClient.K8sV1().SamplingConfigs().Delete(context.TODO(), samplingConfig.Name, metav1.DeleteOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			defaultCollector := &nbdb.SampleCollector{
+				UUID:        collectorUUID,
+				ID:          1,
+				SetID:       DefaultObservabilityCollectorSetID,
+				Probability: 65535,
+				ExternalIDs: map[string]string{
+					collectorFeaturesExternalID: strings.Join([]string{libovsdbops.AdminNetworkPolicySample, libovsdbops.EgressFirewallSample,
+						libovsdbops.MulticastSample, libovsdbops.NetworkPolicySample, libovsdbops.UDNIsolationSample}, ","),
+				},
+			}
+			sample.Collectors = []string{defaultCollector.UUID}
+			expectedDB = append(samplingApps, defaultCollector, sample, pg, acl)
+			Eventually(nbClient, 2*manager.unusedCollectorsRetryInterval).Should(libovsdbtest.HaveData(expectedDB))
+		})
+	})
 })
@@ -1607,6 +1607,109 @@ var _ = ginkgo.Describe("OVN ANP Operations", func() {
 			err := app.Run([]string{app.Name})
 			gomega.Expect(err).ToNot(gomega.HaveOccurred())
 		})
+		ginkgo.It("Dry-run mode for ANP", func() {
+			app.Action = func(*cli.Context) error {
+				config.IPv4Mode = true
+				config.IPv6Mode = true
+				fakeOVN.start()
+				fakeOVN.InitAndRunANPController()
+				fakeOVN.fakeClient.ANPClient.(*anpfake.Clientset).PrependReactor("update", "adminnetworkpolicies", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					update := action.(clienttesting.UpdateAction)
+					// see "ACL Logging for ANP" for why status updates are handled here
+					if action.GetSubresource() == "status" {
+						return true, update.GetObject(), nil
+					}
+					return false, update.GetObject(), nil
+				})
+				ginkgo.By("1. Create ANP in dry-run mode and ensure its rules pass traffic to the next tier")
+				anpSubject := newANPSubjectObject(
+					&metav1.LabelSelector{
+						MatchLabels: anpLabel,
+					},
+					nil,
+				)
+				anp := newANPObject("harry-potter", 75, anpSubject,
+					[]anpapi.AdminNetworkPolicyIngressRule{
+						{
+							Name:   "deny-traffic-from-slytherin-to-gryffindor",
+							Action: anpapi.AdminNetworkPolicyRuleActionDeny,
+							From: []anpapi.AdminNetworkPolicyIngressPeer{
+								{
+									Namespaces: &metav1.LabelSelector{
+										MatchLabels: peerDenyLabel,
+									},
+								},
+							},
+						},
+					},
+					[]anpapi.AdminNetworkPolicyEgressRule{
+						{
+							Name:   "allow-traffic-to-hufflepuff-from-gryffindor",
+							Action: anpapi.AdminNetworkPolicyRuleActionAllow,
+							To: []anpapi.AdminNetworkPolicyEgressPeer{
+								{
+									Namespaces: &metav1.LabelSelector{
+										MatchLabels: peerAllowLabel,
+									},
+								},
+							},
+						},
+					},
+				)
+				anp.ResourceVersion = "1"
+				anp.Annotations = map[string]string{
+					types.PolicyDryRunAnnotation: "true",
+					util.AclLoggingAnnotation:    fmt.Sprintf(`{ "deny": "%s" }`, nbdb.ACLSeverityAlert),
+				}
+				anp, err := fakeOVN.fakeClient.ANPClient.PolicyV1alpha1().AdminNetworkPolicies().Create(context.TODO(), anp, metav1.CreateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				acls := getACLsForANPRules(anp)
+				dryRunACLs := make([]*nbdb.ACL, 0, len(acls))
+				for _, acl := range acls {
+					dryRunACL := *acl
+					dryRunACL.ExternalIDs = map[string]string{types.ACLDryRunActionExternalID: acl.Action}
+					for k, v := range acl.ExternalIDs {
+						dryRunACL.ExternalIDs[k] = v
+					}
+					dryRunACL.Action = nbdb.ACLActionPass
+					dryRunACL.Name = ptr.To(libovsdbutil.GetDryRunACLName(acl.Action, *acl.Name))
+					// logging follows the action the rule would have
+					if acl.Action == nbdb.ACLActionDrop {
+						dryRunACL.Log = true
+						dryRunACL.Severity = ptr.To(nbdb.ACLSeverityAlert)
+					}
+					dryRunACLs = append(dryRunACLs, &dryRunACL)
+				}
+				expectedDatabaseState := []libovsdbtest.TestData{getDefaultPGForANPSubject(anp.Name, []string{}, dryRunACLs, false)}
+				for _, acl := range dryRunACLs {
+					expectedDatabaseState = append(expectedDatabaseState, acl)
+				}
+				peerASIngressRule0v4, peerASIngressRule0v6 := buildANPAddressSets(anp, 0, []string{}, libovsdbutil.ACLIngress)
+				peerASEgressRule0v4, peerASEgressRule0v6 := buildANPAddressSets(anp, 0, []string{}, libovsdbutil.ACLEgress)
+				peerAddressSets := []libovsdbtest.TestData{peerASIngressRule0v4, peerASIngressRule0v6, peerASEgressRule0v4, peerASEgressRule0v6}
+				expectedDatabaseState = append(expectedDatabaseState, peerAddressSets...)
+				gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveDataIgnoringUUIDs(expectedDatabaseState))
+
+				ginkgo.By("2. Update ANP by deleting the dry-run annotation and ensure its rules are enforced")
+				anp.ResourceVersion = "2"
+				delete(anp.Annotations, types.PolicyDryRunAnnotation)
+				_, err = fakeOVN.fakeClient.ANPClient.PolicyV1alpha1().AdminNetworkPolicies().Update(context.TODO(), anp, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				expectedDatabaseState = []libovsdbtest.TestData{getDefaultPGForANPSubject(anp.Name, []string{}, acls, false)}
+				for _, acl := range acls {
+					if acl.Action == nbdb.ACLActionDrop {
+						acl.Log = true
+						acl.Severity = ptr.To(nbdb.ACLSeverityAlert)
+					}
+					expectedDatabaseState = append(expectedDatabaseState, acl)
+				}
+				expectedDatabaseState = append(expectedDatabaseState, peerAddressSets...)
+				gomega.Eventually(fakeOVN.nbClient).Should(libovsdbtest.HaveDataIgnoringUUIDs(expectedDatabaseState))
+				return nil
+			}
+			err := app.Run([]string{app.Name})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		})
 		ginkgo.It("egress node+network peers: should create/update/delete address-sets, acls, port-groups correctly", func() {
 			app.Action = func(*cli.Context) error {
 				anpNamespaceSubject := *testing.NewNamespaceWithLabels(anpSubjectNamespaceName, anpLabel)
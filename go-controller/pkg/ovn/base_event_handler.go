@@ -19,6 +19,7 @@ import (
 	egressfirewall "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/factory"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/metrics/recorders"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
 )
 
@@ -60,7 +61,8 @@ func (h *baseNetworkControllerEventHandler) areResourcesEqual(objType reflect.Ty
 			return false, fmt.Errorf("could not cast obj2 of type %T to *knet.NetworkPolicy", obj2)
 		}
 		areEqual := apiequality.Semantic.DeepEqual(np1.Spec, np2.Spec) &&
-			np1.Annotations[ovnStatelessNetPolAnnotationName] == np2.Annotations[ovnStatelessNetPolAnnotationName]
+			np1.Annotations[ovnStatelessNetPolAnnotationName] == np2.Annotations[ovnStatelessNetPolAnnotationName] &&
+			np1.Annotations[types.PolicyDryRunAnnotation] == np2.Annotations[types.PolicyDryRunAnnotation]
 		return areEqual, nil
 
 	case factory.NodeType:
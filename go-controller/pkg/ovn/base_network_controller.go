@@ -571,11 +571,11 @@ func (bnc *BaseNetworkController) createNodeLogicalSwitch(nodeName string, hostS
 
 	// If supported, enable IGMP/MLD snooping and querier on the node.
 	if bnc.multicastSupport {
-		logicalSwitch.OtherConfig["mcast_snoop"] = "true"
+		setMulticastSnoopOptions(logicalSwitch.OtherConfig)
 
-		// Configure IGMP/MLD querier if the gateway IP address is known.
-		// Otherwise disable it.
-		if v4Gateway != nil || v6Gateway != nil {
+		// Configure IGMP/MLD querier if the gateway IP address is known and
+		// the network doesn't have its own querier. Otherwise disable it.
+		if (v4Gateway != nil || v6Gateway != nil) && !config.OVNKubernetesFeature.DisableMulticastQuerier {
 			logicalSwitch.OtherConfig["mcast_querier"] = "true"
 			logicalSwitch.OtherConfig["mcast_eth_src"] = nodeLRPMAC.String()
 			if v4Gateway != nil {
@@ -5,15 +5,21 @@ package ovn
 
 import (
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
+	utilnet "k8s.io/utils/net"
 
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
 	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
 	libovsdbutil "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/util"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
 )
 
 type defaultMcastACLTypeID string
@@ -47,50 +53,245 @@ func getMulticastACLMatch() string {
 	return "(ip4.mcast || mldv1 || mldv2 || " + ipv6DynamicMulticastMatch + ")"
 }
 
-// Allow IGMP traffic (e.g., IGMP queries) and namespace multicast traffic
-// towards pods.
-func getMulticastACLIgrMatchV4(addrSetName string) string {
-	return "(igmp || (ip4.src == $" + addrSetName + " && ip4.mcast))"
+// namespaceMulticastPolicy is the multicast policy of a namespace with multicast enabled,
+// parsed from the namespace annotations.
+type namespaceMulticastPolicy struct {
+	// restrictGroups is set when the allowed groups annotation is set, multicast traffic is then only
+	// allowed to and from the groupsV4 and groupsV6 IPs/CIDRs
+	restrictGroups     bool
+	groupsV4, groupsV6 []string
+	// sourceNamespaceSelector selects the namespaces whose pods are allowed to send multicast traffic
+	// to the namespace pods, in addition to the namespace pods. nil if not set.
+	sourceNamespaceSelector *metav1.LabelSelector
 }
 
-// Allow MLD traffic (e.g., MLD queries) and namespace multicast traffic
-// towards pods.
-func getMulticastACLIgrMatchV6(addrSetName string) string {
-	return "(mldv1 || mldv2 || (ip6.src == $" + addrSetName + " && " + ipv6DynamicMulticastMatch + "))"
+// parseNamespaceMulticastPolicy parses the multicast policy of a namespace from its annotations
+func parseNamespaceMulticastPolicy(annotations map[string]string) (*namespaceMulticastPolicy, error) {
+	policy := &namespaceMulticastPolicy{}
+	if groups, ok := annotations[util.NsMulticastAllowedGroupsAnnotation]; ok {
+		policy.restrictGroups = true
+		for _, group := range strings.Split(groups, ",") {
+			group = strings.TrimSpace(group)
+			if group == "" {
+				continue
+			}
+			parsedGroup, isIPv6, err := parseMulticastGroup(group)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s annotation %q: %w", util.NsMulticastAllowedGroupsAnnotation, groups, err)
+			}
+			if isIPv6 {
+				policy.groupsV6 = append(policy.groupsV6, parsedGroup)
+			} else {
+				policy.groupsV4 = append(policy.groupsV4, parsedGroup)
+			}
+		}
+	}
+	if selector := strings.TrimSpace(annotations[util.NsMulticastSourceNamespaceSelectorAnnotation]); selector != "" {
+		labelSelector, err := metav1.ParseToLabelSelector(selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation %q: %w", util.NsMulticastSourceNamespaceSelectorAnnotation, selector, err)
+		}
+		policy.sourceNamespaceSelector = labelSelector
+	}
+	return policy, nil
+}
+
+// parseMulticastGroup parses a multicast group IP or CIDR, that must be within 224.0.0.0/4 or ff00::/8
+func parseMulticastGroup(group string) (string, bool, error) {
+	if strings.Contains(group, "/") {
+		ip, ipNet, err := net.ParseCIDR(group)
+		if err != nil {
+			return "", false, err
+		}
+		isIPv6 := utilnet.IsIPv6(ip)
+		minPrefixLength := 4
+		if isIPv6 {
+			minPrefixLength = 8
+		}
+		if ones, _ := ipNet.Mask.Size(); !ip.IsMulticast() || ones < minPrefixLength {
+			return "", false, fmt.Errorf("%s is not a multicast CIDR", group)
+		}
+		return ipNet.String(), isIPv6, nil
+	}
+	ip := net.ParseIP(group)
+	if ip == nil {
+		return "", false, fmt.Errorf("%s is not a valid IP or CIDR", group)
+	}
+	if !ip.IsMulticast() {
+		return "", false, fmt.Errorf("%s is not a multicast IP", group)
+	}
+	return ip.String(), utilnet.IsIPv6(ip), nil
+}
+
+// getMulticastGroupsMatch returns the match on the multicast traffic destined to the given groups
+func getMulticastGroupsMatch(dstField string, groups []string) string {
+	if len(groups) == 1 {
+		return dstField + " == " + groups[0]
+	}
+	return dstField + " == {" + strings.Join(groups, ", ") + "}"
+}
+
+// getMulticastSourceMatch returns the match on the traffic from the IPs of the given address sets
+func getMulticastSourceMatch(srcField string, addrSetNames ...string) string {
+	matches := make([]string, 0, len(addrSetNames))
+	for _, addrSetName := range addrSetNames {
+		if addrSetName != "" {
+			matches = append(matches, srcField+" == $"+addrSetName)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0]
+	}
+	return "(" + strings.Join(matches, " || ") + ")"
+}
+
+// Allow IGMP traffic (e.g., IGMP queries) and multicast traffic matching
+// srcMatch to the groups allowed by the namespace policy towards pods.
+func getMulticastACLIgrMatchV4(srcMatch string, policy *namespaceMulticastPolicy) string {
+	if !policy.restrictGroups {
+		return "(igmp || (" + srcMatch + " && ip4.mcast))"
+	}
+	if len(policy.groupsV4) == 0 {
+		return "igmp"
+	}
+	return "(igmp || (" + srcMatch + " && ip4.mcast && " + getMulticastGroupsMatch("ip4.dst", policy.groupsV4) + "))"
+}
+
+// Allow MLD traffic (e.g., MLD queries) and multicast traffic matching
+// srcMatch to the groups allowed by the namespace policy towards pods.
+func getMulticastACLIgrMatchV6(srcMatch string, policy *namespaceMulticastPolicy) string {
+	if !policy.restrictGroups {
+		return "(mldv1 || mldv2 || (" + srcMatch + " && " + ipv6DynamicMulticastMatch + "))"
+	}
+	if len(policy.groupsV6) == 0 {
+		return "(mldv1 || mldv2)"
+	}
+	return "(mldv1 || mldv2 || (" + srcMatch + " && " + ipv6DynamicMulticastMatch + " && " +
+		getMulticastGroupsMatch("ip6.dst", policy.groupsV6) + "))"
 }
 
 func getMulticastAddrsetBackref(namespace string) string {
 	return fmt.Sprintf("%v/%v", "Multicast", namespace)
 }
 
+func getMulticastSourceAddrsetBackref(namespace string) string {
+	return fmt.Sprintf("%v/%v", "MulticastSource", namespace)
+}
+
 // Creates the match string used for ACLs allowing incoming multicast into a
-// namespace, that is, from IPs that are in the namespace's address set.
-func (bnc *BaseNetworkController) getMulticastACLIgrMatch(addrSetNameV4, addrSetNameV6 string) string {
+// namespace, that is, from IPs that are in the namespace's address set or in
+// the address set of the namespaces selected as multicast sources.
+func (bnc *BaseNetworkController) getMulticastACLIgrMatch(nsInfo *namespaceInfo, policy *namespaceMulticastPolicy) string {
 	var ipv4Match, ipv6Match string
 	ipv4Mode, ipv6Mode := bnc.IPMode()
 	if ipv4Mode {
-		ipv4Match = getMulticastACLIgrMatchV4(addrSetNameV4)
+		srcMatch := getMulticastSourceMatch("ip4.src", nsInfo.addrSetNameV4, nsInfo.mcastSourceAddrSetNameV4)
+		ipv4Match = getMulticastACLIgrMatchV4(srcMatch, policy)
 	}
 	if ipv6Mode {
-		ipv6Match = getMulticastACLIgrMatchV6(addrSetNameV6)
+		srcMatch := getMulticastSourceMatch("ip6.src", nsInfo.addrSetNameV6, nsInfo.mcastSourceAddrSetNameV6)
+		ipv6Match = getMulticastACLIgrMatchV6(srcMatch, policy)
 	}
 	return getACLMatchAF(ipv4Match, ipv6Match, ipv4Mode, ipv6Mode)
 }
 
 // Creates the match string used for ACLs allowing outgoing multicast from a
-// namespace.
-func (bnc *BaseNetworkController) getMulticastACLEgrMatch() string {
+// namespace. IGMP/MLD reports are always allowed, so that pods can join groups.
+func (bnc *BaseNetworkController) getMulticastACLEgrMatch(policy *namespaceMulticastPolicy) string {
 	var ipv4Match, ipv6Match string
 	ipv4Mode, ipv6Mode := bnc.IPMode()
 	if ipv4Mode {
 		ipv4Match = "ip4.mcast"
+		if policy.restrictGroups {
+			ipv4Match = "igmp"
+			if len(policy.groupsV4) > 0 {
+				ipv4Match = "(igmp || (ip4.mcast && " + getMulticastGroupsMatch("ip4.dst", policy.groupsV4) + "))"
+			}
+		}
 	}
 	if ipv6Mode {
 		ipv6Match = "(mldv1 || mldv2 || " + ipv6DynamicMulticastMatch + ")"
+		if policy.restrictGroups {
+			ipv6Match = "(mldv1 || mldv2)"
+			if len(policy.groupsV6) > 0 {
+				ipv6Match = "(mldv1 || mldv2 || (" + ipv6DynamicMulticastMatch + " && " +
+					getMulticastGroupsMatch("ip6.dst", policy.groupsV6) + "))"
+			}
+		}
 	}
 	return getACLMatchAF(ipv4Match, ipv6Match, ipv4Mode, ipv6Mode)
 }
 
+// setMulticastSnoopOptions enables IGMP/MLD snooping in the logical switch other_config
+// and applies the configured snooping tuning.
+func setMulticastSnoopOptions(otherConfig map[string]string) {
+	otherConfig["mcast_snoop"] = "true"
+	if config.OVNKubernetesFeature.MulticastIdleTimeout > 0 {
+		otherConfig["mcast_idle_timeout"] = strconv.Itoa(config.OVNKubernetesFeature.MulticastIdleTimeout)
+	}
+	if config.OVNKubernetesFeature.MulticastQueryInterval > 0 {
+		otherConfig["mcast_query_interval"] = strconv.Itoa(config.OVNKubernetesFeature.MulticastQueryInterval)
+	}
+	if config.OVNKubernetesFeature.MulticastQueryMaxResponse > 0 {
+		otherConfig["mcast_query_max_response"] = strconv.Itoa(config.OVNKubernetesFeature.MulticastQueryMaxResponse)
+	}
+	if config.OVNKubernetesFeature.MulticastTableSize > 0 {
+		otherConfig["mcast_table_size"] = strconv.Itoa(config.OVNKubernetesFeature.MulticastTableSize)
+	}
+	if config.OVNKubernetesFeature.MulticastFloodUnregistered {
+		otherConfig["mcast_flood_unregistered"] = "true"
+	}
+}
+
+// mcastSnoopTuningOptions are the logical switch other_config options set by setMulticastSnoopOptions from the
+// snooping tuning configuration.
+var mcastSnoopTuningOptions = []string{"mcast_idle_timeout", "mcast_query_interval", "mcast_query_max_response",
+	"mcast_table_size", "mcast_flood_unregistered"}
+
+// syncMulticastSnoopOptions applies the snooping tuning configuration to the existing logical switches of the
+// network with snooping enabled, since the switches are only updated on node changes.
+func (bnc *BaseNetworkController) syncMulticastSnoopOptions() error {
+	snoopOptions := map[string]string{}
+	for _, option := range mcastSnoopTuningOptions {
+		// options that are not configured anymore are removed
+		snoopOptions[option] = ""
+	}
+	setMulticastSnoopOptions(snoopOptions)
+	if bnc.Transport() == types.NetworkTransportEVPN {
+		// always set on the switches connected to the EVPN macvrf
+		snoopOptions["mcast_flood_unregistered"] = "true"
+	}
+	networkName := ""
+	if bnc.IsUserDefinedNetwork() {
+		networkName = bnc.GetNetworkName()
+	}
+	p := func(item *nbdb.LogicalSwitch) bool {
+		if item.OtherConfig["mcast_snoop"] != "true" || item.ExternalIDs[types.NetworkExternalID] != networkName {
+			return false
+		}
+		for option, value := range snoopOptions {
+			if item.OtherConfig[option] != value {
+				return true
+			}
+		}
+		return false
+	}
+	switches, err := libovsdbops.FindLogicalSwitchesWithPredicate(bnc.nbClient, p)
+	if err != nil {
+		return fmt.Errorf("unable to find logical switches with stale multicast snooping options: %v", err)
+	}
+	for _, sw := range switches {
+		err = libovsdbops.UpdateLogicalSwitchSetOtherConfig(bnc.nbClient, &nbdb.LogicalSwitch{Name: sw.Name, OtherConfig: snoopOptions})
+		if err != nil {
+			return fmt.Errorf("unable to update the multicast snooping options of logical switch %s: %v", sw.Name, err)
+		}
+	}
+	if len(switches) > 0 {
+		klog.Infof("Sync multicast updated the snooping options of %d logical switches", len(switches))
+	}
+	return nil
+}
+
 func getDefaultMcastACLDbIDs(mcastType defaultMcastACLTypeID, aclDir libovsdbutil.ACLDirection, controller string) *libovsdbops.DbObjectIDs {
 	// there are 2 types of default multicast ACLs in every direction (Ingress/Egress)
 	// DefaultDeny = deny multicast by default
@@ -115,11 +316,17 @@ func getNamespaceMcastACLDbIDs(ns string, aclDir libovsdbutil.ACLDirection, cont
 // Creates a policy to allow multicast traffic within 'ns':
 //   - a port group containing all logical ports associated with 'ns'
 //   - one "from-lport" ACL allowing egress multicast traffic from the pods
-//     in 'ns'
+//     in 'ns', to the allowed groups if the namespace restricts them
 //   - one "to-lport" ACL allowing ingress multicast traffic to pods in 'ns'.
 //     This matches only traffic originated by pods in 'ns' (based on the
-//     namespace address set).
+//     namespace address set) or by pods in the namespaces selected by the
+//     multicast source namespace selector, to the allowed groups if the
+//     namespace restricts them.
 func (bnc *BaseNetworkController) createMulticastAllowPolicy(ns string, nsInfo *namespaceInfo) error {
+	policy := nsInfo.multicastPolicy
+	if policy == nil {
+		policy = &namespaceMulticastPolicy{}
+	}
 	portGroupName := bnc.getNamespacePortGroupName(ns)
 	// we use legacyNetpolMode to avoid adding hostNetwork pods (aka node) IPs.
 	// Another side-effect of using legacyNetpolMode is that HostNetworkNamespace could be matched,
@@ -136,14 +343,36 @@ func (bnc *BaseNetworkController) createMulticastAllowPolicy(ns string, nsInfo *
 		return fmt.Errorf("unable to ensure address set for namespace %s: %v", ns, err)
 	}
 
+	// the address set of the previous source namespace selector is still referenced by the
+	// ingress ACL, it is deleted once the ACL is updated.
+	staleSourceAsKey := nsInfo.mcastSourceAddrSetOwnerBackref
+	nsInfo.mcastSourceAddrSetNameV4 = ""
+	nsInfo.mcastSourceAddrSetNameV6 = ""
+	if policy.sourceNamespaceSelector != nil {
+		sourceAsKey, sourceAddrSetNameV4, sourceAddrSetNameV6, err := bnc.addressSetManager.EnsureAddressSet(
+			&metav1.LabelSelector{}, policy.sourceNamespaceSelector, nil, ns, getMulticastSourceAddrsetBackref(ns),
+			bnc.controllerName, bnc.GetNetInfo(), true)
+		if err != nil {
+			return fmt.Errorf("unable to ensure multicast source address set for namespace %s: %v", ns, err)
+		}
+		if sourceAsKey == staleSourceAsKey {
+			staleSourceAsKey = ""
+		}
+		nsInfo.mcastSourceAddrSetOwnerBackref = sourceAsKey
+		nsInfo.mcastSourceAddrSetNameV4 = sourceAddrSetNameV4
+		nsInfo.mcastSourceAddrSetNameV6 = sourceAddrSetNameV6
+	} else {
+		nsInfo.mcastSourceAddrSetOwnerBackref = ""
+	}
+
 	aclDir := libovsdbutil.ACLEgress
-	egressMatch := libovsdbutil.GetACLMatch(portGroupName, bnc.getMulticastACLEgrMatch(), aclDir)
+	egressMatch := libovsdbutil.GetACLMatch(portGroupName, bnc.getMulticastACLEgrMatch(policy), aclDir)
 	dbIDs := getNamespaceMcastACLDbIDs(ns, aclDir, bnc.controllerName)
 	aclPipeline := libovsdbutil.ACLDirectionToACLPipeline(aclDir)
 	egressACL := libovsdbutil.BuildACLWithDefaultTier(dbIDs, types.DefaultMcastAllowPriority, egressMatch, nbdb.ACLActionAllow, nil, aclPipeline)
 
 	aclDir = libovsdbutil.ACLIngress
-	ingressMatch := libovsdbutil.GetACLMatch(portGroupName, bnc.getMulticastACLIgrMatch(addrSetNameV4, addrSetNameV6), aclDir)
+	ingressMatch := libovsdbutil.GetACLMatch(portGroupName, bnc.getMulticastACLIgrMatch(nsInfo, policy), aclDir)
 	dbIDs = getNamespaceMcastACLDbIDs(ns, aclDir, bnc.controllerName)
 	aclPipeline = libovsdbutil.ACLDirectionToACLPipeline(aclDir)
 	ingressACL := libovsdbutil.BuildACLWithDefaultTier(dbIDs, types.DefaultMcastAllowPriority, ingressMatch, nbdb.ACLActionAllow, nil, aclPipeline)
@@ -164,6 +393,13 @@ func (bnc *BaseNetworkController) createMulticastAllowPolicy(ns string, nsInfo *
 		return err
 	}
 
+	if staleSourceAsKey != "" {
+		if err := bnc.addressSetManager.DeleteAddressSet(staleSourceAsKey, getMulticastSourceAddrsetBackref(ns)); err != nil {
+			// the key is not referenced anymore, the address set will be cleaned up on restart
+			klog.Errorf("Unable to delete stale multicast source address set for namespace %s: %v", ns, err)
+		}
+	}
+
 	return nil
 }
 
@@ -195,6 +431,15 @@ func (bnc *BaseNetworkController) deleteMulticastAllowPolicy(ns string, nsInfo *
 		nsInfo.addrSetOwnerBackref = ""
 		nsInfo.addrSetNameV4 = ""
 		nsInfo.addrSetNameV6 = ""
+		if nsInfo.mcastSourceAddrSetOwnerBackref != "" {
+			if err := bnc.addressSetManager.DeleteAddressSet(nsInfo.mcastSourceAddrSetOwnerBackref,
+				getMulticastSourceAddrsetBackref(ns)); err != nil {
+				return fmt.Errorf("unable to delete multicast source address set for namespace %s: %v", ns, err)
+			}
+			nsInfo.mcastSourceAddrSetOwnerBackref = ""
+			nsInfo.mcastSourceAddrSetNameV4 = ""
+			nsInfo.mcastSourceAddrSetNameV6 = ""
+		}
 	}
 
 	return nil
@@ -374,6 +619,10 @@ func (bnc *BaseNetworkController) syncDefaultMulticastPolicies() error {
 			klog.Errorf("Failed to create default deny multicast policy, error: %v", err)
 			return err
 		}
+
+		if err := bnc.syncMulticastSnoopOptions(); err != nil {
+			return err
+		}
 	} else {
 		if err := bnc.disableMulticast(); err != nil {
 			return fmt.Errorf("failed to delete default multicast policy, error: %v", err)
@@ -30,10 +30,10 @@ func (bsnc *BaseUserDefinedNetworkController) syncMultiNetworkPolicies(multiPoli
 			continue
 		}
 		if nsMap, ok := expectedPolicies[policy.Namespace]; ok {
-			nsMap[policy.Name] = true
+			nsMap[policy.Name] = isDryRunPolicy(policy.Annotations)
 		} else {
 			expectedPolicies[policy.Namespace] = map[string]bool{
-				policy.Name: true,
+				policy.Name: isDryRunPolicy(policy.Annotations),
 			}
 		}
 	}
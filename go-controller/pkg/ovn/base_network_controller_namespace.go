@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
@@ -56,6 +57,12 @@ type namespaceInfo struct {
 	routingExternalPodGWs map[string]gatewayInfo
 
 	multicastEnabled bool
+	// multicastPolicy is the multicast policy parsed from the namespace annotations, nil when multicast is disabled
+	multicastPolicy *namespaceMulticastPolicy
+	// address set of the pods in the namespaces selected by the multicast source namespace selector,
+	// managed by multicast
+	mcastSourceAddrSetOwnerBackref                     string
+	mcastSourceAddrSetNameV4, mcastSourceAddrSetNameV6 string
 
 	// If not empty, then it has to be set to a logging a severity level, e.g. "notice", "alert", etc
 	aclLogging libovsdbutil.ACLLoggingLevels
@@ -188,21 +195,33 @@ func (bnc *BaseNetworkController) syncNamespaces(namespaces []interface{}) error
 }
 
 // Creates an explicit "allow" policy for multicast traffic within the
-// namespace if multicast is enabled. Otherwise, removes the "allow" policy.
+// namespace if multicast is enabled, or updates it when the namespace
+// multicast policy annotations change. Otherwise, removes the "allow" policy.
 // Traffic will be dropped by the default multicast deny ACL.
+// If the multicast policy annotations are invalid, multicast is denied in the
+// namespace until they are fixed.
 func (bnc *BaseNetworkController) multicastUpdateNamespace(ns *corev1.Namespace, nsInfo *namespaceInfo) error {
 	if !bnc.multicastSupport {
 		return nil
 	}
 
 	enabled := isNamespaceMulticastEnabled(ns.Annotations)
-	enabledOld := nsInfo.multicastEnabled
-	if enabledOld == enabled {
+	var policy *namespaceMulticastPolicy
+	if enabled {
+		var err error
+		policy, err = parseNamespaceMulticastPolicy(ns.Annotations)
+		if err != nil {
+			klog.Errorf("Denying multicast in namespace %s: %v", ns.Name, err)
+			enabled = false
+		}
+	}
+	if enabled == nsInfo.multicastEnabled && reflect.DeepEqual(policy, nsInfo.multicastPolicy) {
 		return nil
 	}
 
 	var err error
 	nsInfo.multicastEnabled = enabled
+	nsInfo.multicastPolicy = policy
 	if enabled {
 		err = bnc.createMulticastAllowPolicy(ns.Name, nsInfo)
 	} else {
@@ -219,6 +238,7 @@ func (bnc *BaseNetworkController) multicastUpdateNamespace(ns *corev1.Namespace,
 func (bnc *BaseNetworkController) multicastDeleteNamespace(ns *corev1.Namespace, nsInfo *namespaceInfo) error {
 	if nsInfo.multicastEnabled {
 		nsInfo.multicastEnabled = false
+		nsInfo.multicastPolicy = nil
 		if err := bnc.deleteMulticastAllowPolicy(ns.Name, nsInfo); err != nil {
 			return err
 		}
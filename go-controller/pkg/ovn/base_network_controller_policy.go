@@ -39,6 +39,8 @@ const (
 	defaultDenyACL netpolDefaultDenyACLType = "defaultDeny"
 	arpAllowACL    netpolDefaultDenyACLType = "arpAllow"
 	icmpAllowACL   netpolDefaultDenyACLType = "icmpAllow"
+	// dryRunDenyACL is the would-deny ACL of the port groups shared by dry-run network policies
+	dryRunDenyACL netpolDefaultDenyACLType = "dryRunDeny"
 
 	// icmpAllowPolicyMatch is the match used when creating default allow ICMP and ICMPv6 ACLs for a namespace
 	icmpAllowPolicyMatch = "(icmp || icmp6)"
@@ -48,6 +50,9 @@ const (
 	// ovnStatelessNetPolAnnotationName is an annotation on K8s Network Policy resource to specify that all
 	// the resulting OVN ACLs must be created as stateless
 	ovnStatelessNetPolAnnotationName = "k8s.ovn.org/acl-stateless"
+	// dryRunSharedPortGroupsKeySuffix is appended to the namespace to build the sharedNetpolPortGroups key
+	// of the port groups shared by dry-run network policies
+	dryRunSharedPortGroupsKeySuffix = "/dry-run"
 )
 
 // defaultDenyPortGroups is a shared object and should be used by only 1 thread at a time
@@ -164,6 +169,10 @@ type networkPolicy struct {
 	egressPolicies  []*gressPolicy
 	isIngress       bool
 	isEgress        bool
+	// isDryRun is set for policies annotated with types.PolicyDryRunAnnotation: their local pods are added
+	// to the namespace dry-run port groups instead of the default deny port groups, so that the policy
+	// doesn't isolate them, and traffic it would deny is allowed and tagged as would-deny.
+	isDryRun bool
 
 	// network policy owns only 1 local pod handler
 	localPodHandler *factory.Handler
@@ -204,6 +213,7 @@ func NewNetworkPolicy(policy *knet.NetworkPolicy) *networkPolicy {
 		egressPolicies:  make([]*gressPolicy, 0),
 		isIngress:       policyTypeIngress,
 		isEgress:        policyTypeEgress,
+		isDryRun:        isDryRunPolicy(policy.Annotations),
 		localPods:       sync.Map{},
 	}
 	return np
@@ -217,10 +227,10 @@ func (bnc *BaseNetworkController) syncNetworkPolicies(networkPolicies []interfac
 			return fmt.Errorf("spurious object in syncNetworkPolicies: %v", npInterface)
 		}
 		if nsMap, ok := expectedPolicies[policy.Namespace]; ok {
-			nsMap[policy.Name] = true
+			nsMap[policy.Name] = isDryRunPolicy(policy.Annotations)
 		} else {
 			expectedPolicies[policy.Namespace] = map[string]bool{
-				policy.Name: true,
+				policy.Name: isDryRunPolicy(policy.Annotations),
 			}
 		}
 	}
@@ -280,14 +290,30 @@ func (bnc *BaseNetworkController) addHairpinAllowACL() error {
 	return nil
 }
 
+// isDryRunPolicy returns true if the policy annotations make it a dry-run policy
+func isDryRunPolicy(annotations map[string]string) bool {
+	return annotations[types.PolicyDryRunAnnotation] == "true"
+}
+
 // syncNetworkPoliciesCommon syncs logical entities associated with existing network policies.
-// It serves both networkpolicies (for default network) and multi-networkpolicies (for secondary networks)
+// It serves both networkpolicies (for default network) and multi-networkpolicies (for secondary networks).
+// expectedPolicies maps the namespaces to their policies, and the policies to whether they are dry-run policies.
 func (bnc *BaseNetworkController) syncNetworkPoliciesCommon(expectedPolicies map[string]map[string]bool) error {
 	// find network policies that don't exist in k8s anymore, but still present in the dbs, and cleanup.
 	// Peer address sets and network policy's port groups (together with acls) will be cleaned up.
 	// Delete port groups with acls first, since address sets may be referenced in these acls, and
 	// cause SyntaxError in ovn-controller, if address sets deleted first, but acls still reference them.
 
+	// hasPolicies returns true if the namespace has dry-run policies, or enforced policies
+	hasPolicies := func(namespace string, dryRun bool) bool {
+		for _, isDryRun := range expectedPolicies[namespace] {
+			if isDryRun == dryRun {
+				return true
+			}
+		}
+		return false
+	}
+
 	// cleanup port groups
 	// netpol-owned port groups first
 	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.PortGroupNetworkPolicy, bnc.controllerName, nil)
@@ -299,7 +325,8 @@ func (bnc *BaseNetworkController) syncNetworkPoliciesCommon(expectedPolicies map
 			return false
 		}
 		// delete if policy is not present in expectedPolicies
-		return !expectedPolicies[namespace][policyName]
+		_, ok := expectedPolicies[namespace][policyName]
+		return !ok
 	})
 	if err := libovsdbops.DeletePortGroupsWithPredicate(bnc.nbClient, p); err != nil {
 		return fmt.Errorf("cannot delete namespace NetworkPolicy port groups: %v", err)
@@ -309,13 +336,23 @@ func (bnc *BaseNetworkController) syncNetworkPoliciesCommon(expectedPolicies map
 	predicateIDs = libovsdbops.NewDbObjectIDs(libovsdbops.PortGroupNetpolNamespace, bnc.controllerName, nil)
 	p = libovsdbops.GetPredicate[*nbdb.PortGroup](predicateIDs, func(item *nbdb.PortGroup) bool {
 		namespace := item.ExternalIDs[libovsdbops.ObjectNameKey.String()]
-		_, ok := expectedPolicies[namespace]
-		// delete default deny port group if no policies in that namespace are found
-		return !ok
+		// delete default deny port group if no enforced policies in that namespace are found
+		return !hasPolicies(namespace, false)
 	})
 	if err := libovsdbops.DeletePortGroupsWithPredicate(bnc.nbClient, p); err != nil {
 		return fmt.Errorf("cannot find default deny NetworkPolicy port groups: %v", err)
 	}
+
+	// netpol-namespace-owned dry-run port groups
+	predicateIDs = libovsdbops.NewDbObjectIDs(libovsdbops.PortGroupNetpolNamespaceDryRun, bnc.controllerName, nil)
+	p = libovsdbops.GetPredicate[*nbdb.PortGroup](predicateIDs, func(item *nbdb.PortGroup) bool {
+		namespace := item.ExternalIDs[libovsdbops.ObjectNameKey.String()]
+		// delete dry-run port group if no dry-run policies in that namespace are found
+		return !hasPolicies(namespace, true)
+	})
+	if err := libovsdbops.DeletePortGroupsWithPredicate(bnc.nbClient, p); err != nil {
+		return fmt.Errorf("cannot delete dry-run NetworkPolicy port groups: %v", err)
+	}
 	return nil
 }
 
@@ -384,6 +421,47 @@ func (bnc *BaseNetworkController) defaultDenyPortGroupName(namespace string, acl
 	return libovsdbutil.GetPortGroupName(bnc.getDefaultDenyPolicyPortGroupIDs(namespace, aclDir))
 }
 
+func (bnc *BaseNetworkController) getDryRunPolicyPortGroupIDs(ns string, aclDir libovsdbutil.ACLDirection) *libovsdbops.DbObjectIDs {
+	return libovsdbops.NewDbObjectIDs(libovsdbops.PortGroupNetpolNamespaceDryRun, bnc.controllerName,
+		map[libovsdbops.ExternalIDKey]string{
+			libovsdbops.ObjectNameKey:      ns,
+			libovsdbops.PolicyDirectionKey: string(aclDir),
+		})
+}
+
+func (bnc *BaseNetworkController) dryRunPortGroupName(namespace string, aclDir libovsdbutil.ACLDirection) string {
+	return libovsdbutil.GetPortGroupName(bnc.getDryRunPolicyPortGroupIDs(namespace, aclDir))
+}
+
+// sharedPortGroupName returns the name of the shared port group the local pods of the given policy are added to:
+// the namespace dry-run port group for dry-run policies, the namespace default deny port group otherwise.
+func (bnc *BaseNetworkController) sharedPortGroupName(np *networkPolicy, aclDir libovsdbutil.ACLDirection) string {
+	if np.isDryRun {
+		return bnc.dryRunPortGroupName(np.namespace, aclDir)
+	}
+	return bnc.defaultDenyPortGroupName(np.namespace, aclDir)
+}
+
+// buildDryRunDenyACLs builds the ACLs of a dry-run port group: traffic that is not allowed by any dry-run policy
+// is allowed and tagged as would-deny. ARP and, if allowed by config, ICMP are not tagged since the default deny
+// port groups always allow them.
+// The ACL priority is lower than the default deny priority, so that traffic of pods also selected by enforced
+// policies is handled by the default deny port groups ACLs.
+func (bnc *BaseNetworkController) buildDryRunDenyACLs(namespace, pgName string, aclLogging *libovsdbutil.ACLLoggingLevels,
+	aclDir libovsdbutil.ACLDirection) []*nbdb.ACL {
+	match := "!" + arpAllowPolicyMatch
+	if config.OVNKubernetesFeature.AllowICMPNetworkPolicy {
+		match += " && !" + icmpAllowPolicyMatch
+	}
+	wouldDenyMatch := libovsdbutil.GetACLMatch(pgName, match, aclDir)
+	aclPipeline := libovsdbutil.ACLDirectionToACLPipeline(aclDir)
+	return []*nbdb.ACL{
+		libovsdbutil.BuildDryRunACL(bnc.getDefaultDenyPolicyACLIDs(namespace, aclDir, dryRunDenyACL),
+			types.DryRunDenyPriority, wouldDenyMatch, nbdb.ACLActionDrop, nbdb.ACLActionAllowRelated, aclLogging,
+			aclPipeline, types.DefaultACLTier),
+	}
+}
+
 func (bnc *BaseNetworkController) buildDenyACLs(namespace, pgName string, aclLogging *libovsdbutil.ACLLoggingLevels,
 	aclDir libovsdbutil.ACLDirection) []*nbdb.ACL {
 	denyMatch := libovsdbutil.GetACLMatch(pgName, "", aclDir)
@@ -403,8 +481,16 @@ func (bnc *BaseNetworkController) buildDenyACLs(namespace, pgName string, aclLog
 	return acls
 }
 
+// sharedPortGroupsKey returns the sharedNetpolPortGroups key of the port groups the policy local pods are added to.
+func (np *networkPolicy) sharedPortGroupsKey() string {
+	if np.isDryRun {
+		return np.namespace + dryRunSharedPortGroupsKeySuffix
+	}
+	return np.namespace
+}
+
 func (bnc *BaseNetworkController) addPolicyToDefaultPortGroups(np *networkPolicy, aclLogging *libovsdbutil.ACLLoggingLevels) error {
-	return bnc.sharedNetpolPortGroups.DoWithLock(np.namespace, func(pgKey string) error {
+	return bnc.sharedNetpolPortGroups.DoWithLock(np.sharedPortGroupsKey(), func(pgKey string) error {
 		sharedPGs, loaded := bnc.sharedNetpolPortGroups.LoadOrStore(pgKey, &defaultDenyPortGroups{
 			ingressPortToPolicies: map[string]sets.Set[string]{},
 			egressPortToPolicies:  map[string]sets.Set[string]{},
@@ -412,7 +498,12 @@ func (bnc *BaseNetworkController) addPolicyToDefaultPortGroups(np *networkPolicy
 		})
 		if !loaded {
 			// create port groups with acls
-			err := bnc.createDefaultDenyPGAndACLs(np.namespace, np.name, aclLogging)
+			var err error
+			if np.isDryRun {
+				err = bnc.createDryRunPGAndACLs(np.namespace, np.name, aclLogging)
+			} else {
+				err = bnc.createDefaultDenyPGAndACLs(np.namespace, np.name, aclLogging)
+			}
 			if err != nil {
 				bnc.sharedNetpolPortGroups.Delete(pgKey)
 				return fmt.Errorf("failed to create default deny port groups: %v", err)
@@ -424,7 +515,7 @@ func (bnc *BaseNetworkController) addPolicyToDefaultPortGroups(np *networkPolicy
 }
 
 func (bnc *BaseNetworkController) delPolicyFromDefaultPortGroups(np *networkPolicy) error {
-	return bnc.sharedNetpolPortGroups.DoWithLock(np.namespace, func(pgKey string) error {
+	return bnc.sharedNetpolPortGroups.DoWithLock(np.sharedPortGroupsKey(), func(pgKey string) error {
 		sharedPGs, found := bnc.sharedNetpolPortGroups.Load(pgKey)
 		if !found {
 			return nil
@@ -432,7 +523,12 @@ func (bnc *BaseNetworkController) delPolicyFromDefaultPortGroups(np *networkPoli
 		delete(sharedPGs.policies, np.getKey())
 		if len(sharedPGs.policies) == 0 {
 			// last policy was deleted, delete port group
-			err := bnc.deleteDefaultDenyPGAndACLs(np.namespace)
+			var err error
+			if np.isDryRun {
+				err = bnc.deleteDryRunPGAndACLs(np.namespace)
+			} else {
+				err = bnc.deleteDefaultDenyPGAndACLs(np.namespace)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to delete defaul deny port group: %v", err)
 			}
@@ -478,6 +574,58 @@ func (bnc *BaseNetworkController) createDefaultDenyPGAndACLs(namespace, policy s
 	return nil
 }
 
+// createDryRunPGAndACLs creates the dry-run port groups and acls for a namespace
+// must be called with defaultDenyPortGroups lock
+func (bnc *BaseNetworkController) createDryRunPGAndACLs(namespace, policy string, aclLogging *libovsdbutil.ACLLoggingLevels) error {
+	ingressPGIDs := bnc.getDryRunPolicyPortGroupIDs(namespace, libovsdbutil.ACLIngress)
+	ingressACLs := bnc.buildDryRunDenyACLs(namespace, libovsdbutil.GetPortGroupName(ingressPGIDs), aclLogging, libovsdbutil.ACLIngress)
+	egressPGIDs := bnc.getDryRunPolicyPortGroupIDs(namespace, libovsdbutil.ACLEgress)
+	egressACLs := bnc.buildDryRunDenyACLs(namespace, libovsdbutil.GetPortGroupName(egressPGIDs), aclLogging, libovsdbutil.ACLEgress)
+	ops, err := libovsdbops.CreateOrUpdateACLsOps(bnc.nbClient, nil, bnc.GetSamplingConfig(), append(ingressACLs, egressACLs...)...)
+	if err != nil {
+		return err
+	}
+
+	ingressPG := libovsdbutil.BuildPortGroup(ingressPGIDs, nil, ingressACLs)
+	egressPG := libovsdbutil.BuildPortGroup(egressPGIDs, nil, egressACLs)
+	ops, err = libovsdbops.CreateOrUpdatePortGroupsOps(bnc.nbClient, ops, ingressPG, egressPG)
+	if err != nil {
+		return err
+	}
+
+	recordOps, txOkCallBack, _, err := bnc.AddConfigDurationRecord("networkpolicy", namespace, policy)
+	if err != nil {
+		klog.Errorf("Failed to record config duration: %v", err)
+	}
+	ops = append(ops, recordOps...)
+	_, err = libovsdbops.TransactAndCheck(bnc.nbClient, ops)
+	if err != nil {
+		return err
+	}
+	txOkCallBack()
+
+	return nil
+}
+
+// deleteDryRunPGAndACLs deletes the dry-run port groups and acls for a namespace
+// must be called with defaultDenyPortGroups lock
+func (bnc *BaseNetworkController) deleteDryRunPGAndACLs(namespace string) error {
+	ingressPGName := bnc.dryRunPortGroupName(namespace, libovsdbutil.ACLIngress)
+	egressPGName := bnc.dryRunPortGroupName(namespace, libovsdbutil.ACLEgress)
+
+	ops, err := libovsdbops.DeletePortGroupsOps(bnc.nbClient, nil, ingressPGName, egressPGName)
+	if err != nil {
+		return err
+	}
+	// No need to delete ACLs, since they will be garbage collected with deleted port groups
+	_, err = libovsdbops.TransactAndCheck(bnc.nbClient, ops)
+	if err != nil {
+		return fmt.Errorf("failed to transact deleteDryRunPGAndACLs: %v", err)
+	}
+
+	return nil
+}
+
 // deleteDefaultDenyPGAndACLs deletes the default port groups and acls for a namespace
 // must be called with defaultDenyPortGroups lock
 func (bnc *BaseNetworkController) deleteDefaultDenyPGAndACLs(namespace string) error {
@@ -514,7 +662,17 @@ func (bnc *BaseNetworkController) updateACLLoggingForPolicy(np *networkPolicy, a
 }
 
 func (bnc *BaseNetworkController) updateACLLoggingForDefaultACLs(ns string, nsInfo *namespaceInfo) error {
-	return bnc.sharedNetpolPortGroups.DoWithLock(ns, func(pgKey string) error {
+	if err := bnc.updateACLLoggingForSharedPGACLs(ns, ns, defaultDenyACL, nsInfo); err != nil {
+		return err
+	}
+	return bnc.updateACLLoggingForSharedPGACLs(ns+dryRunSharedPortGroupsKeySuffix, ns, dryRunDenyACL, nsInfo)
+}
+
+// updateACLLoggingForSharedPGACLs updates the logging of the given type of ACLs of the shared port groups stored
+// in sharedNetpolPortGroups with the given key.
+func (bnc *BaseNetworkController) updateACLLoggingForSharedPGACLs(key, ns string, aclType netpolDefaultDenyACLType,
+	nsInfo *namespaceInfo) error {
+	return bnc.sharedNetpolPortGroups.DoWithLock(key, func(pgKey string) error {
 		_, loaded := bnc.sharedNetpolPortGroups.Load(pgKey)
 		if !loaded {
 			// shared port group doesn't exist, nothing to update
@@ -523,7 +681,7 @@ func (bnc *BaseNetworkController) updateACLLoggingForDefaultACLs(ns string, nsIn
 		predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.ACLNetpolNamespace, bnc.controllerName,
 			map[libovsdbops.ExternalIDKey]string{
 				libovsdbops.ObjectNameKey: ns,
-				libovsdbops.TypeKey:       string(defaultDenyACL),
+				libovsdbops.TypeKey:       string(aclType),
 			})
 		p := libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil)
 		defaultDenyACLs, err := libovsdbops.FindACLsWithPredicate(bnc.nbClient, p)
@@ -677,10 +835,10 @@ func (bnc *BaseNetworkController) getExistingLocalPolicyPorts(np *networkPolicy,
 // It only adds new ports that do not already exist in the deny port groups.
 func (bnc *BaseNetworkController) denyPGAddPorts(np *networkPolicy, portNamesToUUIDs map[string]string, ops []ovsdb.Operation) error {
 	var err error
-	ingressDenyPGName := bnc.defaultDenyPortGroupName(np.namespace, libovsdbutil.ACLIngress)
-	egressDenyPGName := bnc.defaultDenyPortGroupName(np.namespace, libovsdbutil.ACLEgress)
+	ingressDenyPGName := bnc.sharedPortGroupName(np, libovsdbutil.ACLIngress)
+	egressDenyPGName := bnc.sharedPortGroupName(np, libovsdbutil.ACLEgress)
 
-	pgKey := np.namespace
+	pgKey := np.sharedPortGroupsKey()
 	// this lock guarantees that sharedPortGroup counters will be updated atomically
 	// with adding port to port group in db.
 	bnc.sharedNetpolPortGroups.LockKey(pgKey)
@@ -741,10 +899,10 @@ func (bnc *BaseNetworkController) denyPGDeletePorts(np *networkPolicy, portNames
 		})
 	}
 	if len(portNamesToUUIDs) != 0 {
-		ingressDenyPGName := bnc.defaultDenyPortGroupName(np.namespace, libovsdbutil.ACLIngress)
-		egressDenyPGName := bnc.defaultDenyPortGroupName(np.namespace, libovsdbutil.ACLEgress)
+		ingressDenyPGName := bnc.sharedPortGroupName(np, libovsdbutil.ACLIngress)
+		egressDenyPGName := bnc.sharedPortGroupName(np, libovsdbutil.ACLEgress)
 
-		pgKey := np.namespace
+		pgKey := np.sharedPortGroupsKey()
 		// this lock guarantees that sharedPortGroup counters will be updated atomically
 		// with adding port to port group in db.
 		bnc.sharedNetpolPortGroups.LockKey(pgKey)
@@ -1026,6 +1184,10 @@ func (bnc *BaseNetworkController) createNetworkPolicy(policy *knet.NetworkPolicy
 			klog.Infof("ACL logging for network policy %s in namespace %s set to deny=%s, allow=%s",
 				policy.Name, policy.Namespace, aclLogging.Deny, aclLogging.Allow)
 		}
+		if np.isDryRun {
+			klog.Infof("Network policy %s in namespace %s is in dry-run mode, it won't be enforced",
+				policy.Name, policy.Namespace)
+		}
 
 		// 2. Build gress policies, create addressSets for peers
 
@@ -1043,6 +1205,7 @@ func (bnc *BaseNetworkController) createNetworkPolicy(policy *knet.NetworkPolicy
 			klog.V(5).Infof("Network policy ingress is %+v", ingressJSON)
 
 			ingress := newGressPolicy(knet.PolicyTypeIngress, i, policy.Namespace, policy.Name, bnc.controllerName, statelessNetPol, bnc.GetNetInfo())
+			ingress.isDryRun = np.isDryRun
 			// append ingress policy to be able to cleanup created address set
 			// see cleanupNetworkPolicy for details
 			np.ingressPolicies = append(np.ingressPolicies, ingress)
@@ -1066,6 +1229,7 @@ func (bnc *BaseNetworkController) createNetworkPolicy(policy *knet.NetworkPolicy
 			klog.V(5).Infof("Network policy egress is %+v", egressJSON)
 
 			egress := newGressPolicy(knet.PolicyTypeEgress, i, policy.Namespace, policy.Name, bnc.controllerName, statelessNetPol, bnc.GetNetInfo())
+			egress.isDryRun = np.isDryRun
 			// append ingress policy to be able to cleanup created address set
 			// see cleanupNetworkPolicy for details
 			np.egressPolicies = append(np.egressPolicies, egress)
@@ -219,7 +219,7 @@ func (oc *BaseLayer2UserDefinedNetworkController) initializeLogicalSwitch(switch
 	case oc.Transport() == types.NetworkTransportEVPN:
 		// enable IGMP snooping to send multicast traffic just to registered
 		// pods, flood unregistered
-		logicalSwitch.OtherConfig["mcast_snoop"] = "true"
+		setMulticastSnoopOptions(logicalSwitch.OtherConfig)
 		logicalSwitch.OtherConfig["mcast_flood_unregistered"] = "true"
 		logicalSwitch.OtherConfig["mcast_querier"] = "false"
 		// connect the switch to the EVPN macvrf
@@ -220,7 +220,7 @@ func (c *Controller) convertANPRulesToACLs(desiredANPState, currentANPState *adm
 		len(currentANPState.ingressRules) == len(desiredANPState.ingressRules) &&
 		len(currentANPState.egressRules) == len(desiredANPState.egressRules))
 	for i, ingressRule := range desiredANPState.ingressRules {
-		acl := c.convertANPRuleToACL(ingressRule, pgName, desiredANPState.name, desiredANPState.aclLoggingParams, isBanp)
+		acl := c.convertANPRuleToACL(ingressRule, pgName, desiredANPState.name, desiredANPState.aclLoggingParams, desiredANPState.dryRun, isBanp)
 		acls = append(acls, acl...)
 		if isAtLeastOneRuleUpdatedCheckRequired &&
 			!*atLeastOneRuleUpdated &&
@@ -232,7 +232,7 @@ func (c *Controller) convertANPRulesToACLs(desiredANPState, currentANPState *adm
 		}
 	}
 	for i, egressRule := range desiredANPState.egressRules {
-		acl := c.convertANPRuleToACL(egressRule, pgName, desiredANPState.name, desiredANPState.aclLoggingParams, isBanp)
+		acl := c.convertANPRuleToACL(egressRule, pgName, desiredANPState.name, desiredANPState.aclLoggingParams, desiredANPState.dryRun, isBanp)
 		acls = append(acls, acl...)
 		if isAtLeastOneRuleUpdatedCheckRequired &&
 			!*atLeastOneRuleUpdated &&
@@ -248,8 +248,11 @@ func (c *Controller) convertANPRulesToACLs(desiredANPState, currentANPState *adm
 }
 
 // convertANPRuleToACL takes the given gressRule and converts it into an ACL(0 ports rule) or
-// multiple ACLs(ports are set) and returns those ACLs for a given gressRule
-func (c *Controller) convertANPRuleToACL(rule *gressRule, pgName, anpName string, aclLoggingParams *libovsdbutil.ACLLoggingLevels, isBanp bool) []*nbdb.ACL {
+// multiple ACLs(ports are set) and returns those ACLs for a given gressRule.
+// In dry-run mode the ACLs pass the traffic to the next tier instead of applying the rule action,
+// which is recorded on the ACLs so that it can be observed through ACL logging and sampling.
+func (c *Controller) convertANPRuleToACL(rule *gressRule, pgName, anpName string, aclLoggingParams *libovsdbutil.ACLLoggingLevels,
+	dryRun, isBanp bool) []*nbdb.ACL {
 	klog.V(5).Infof("Creating ACL for rule %d/%s belonging to ANP %s", rule.priority, rule.gressPrefix, anpName)
 	// create match based on direction and address-set name
 	asIndex := GetANPPeerAddrSetDbIDs(anpName, rule.gressPrefix, fmt.Sprintf("%d", rule.gressIndex), c.controllerName, isBanp)
@@ -259,6 +262,14 @@ func (c *Controller) convertANPRuleToACL(rule *gressRule, pgName, anpName string
 	var match string
 	hasNamedPorts := len(rule.namedPorts) > 0
 	acls := []*nbdb.ACL{}
+	aclPipeline := libovsdbutil.ACLDirectionToACLPipeline(libovsdbutil.ACLDirection(rule.gressPrefix))
+	buildACL := func(dbIDs *libovsdbops.DbObjectIDs, match string) *nbdb.ACL {
+		if dryRun {
+			return libovsdbutil.BuildDryRunACL(dbIDs, int(rule.priority), match, rule.action, nbdb.ACLActionPass,
+				aclLoggingParams, aclPipeline, libovsdbutil.GetACLTier(dbIDs))
+		}
+		return libovsdbutil.BuildANPACL(dbIDs, int(rule.priority), match, rule.action, aclPipeline, aclLoggingParams)
+	}
 	// We will have
 	// - one single ACL if len(rule.ports) == 0 && len(rule.namedPorts) == 0
 	// - one ACL per protocol if len(rule.ports) > 0 and len(rule.namedPorts) == 0
@@ -276,13 +287,9 @@ func (c *Controller) convertANPRuleToACL(rule *gressRule, pgName, anpName string
 		} else {
 			match = fmt.Sprintf("%s && %s && %s", lportMatch, l3Match, l4Match)
 		}
-		acl := libovsdbutil.BuildANPACL(
+		acl := buildACL(
 			getANPRuleACLDbIDs(anpName, rule.gressPrefix, fmt.Sprintf("%d", rule.gressIndex), protocol, c.controllerName, isBanp),
-			int(rule.priority),
 			match,
-			rule.action,
-			libovsdbutil.ACLDirectionToACLPipeline(libovsdbutil.ACLDirection(rule.gressPrefix)),
-			aclLoggingParams,
 		)
 		acls = append(acls, acl)
 	}
@@ -293,13 +300,9 @@ func (c *Controller) convertANPRuleToACL(rule *gressRule, pgName, anpName string
 		} else {
 			match = fmt.Sprintf("%s && %s", lportMatch, l3l4Match)
 		}
-		acl := libovsdbutil.BuildANPACL(
+		acl := buildACL(
 			getANPRuleACLDbIDs(anpName, rule.gressPrefix, fmt.Sprintf("%d", rule.gressIndex), protocol+libovsdbutil.NamedPortL4MatchSuffix, c.controllerName, isBanp),
-			int(rule.priority),
 			match,
-			rule.action,
-			libovsdbutil.ACLDirectionToACLPipeline(libovsdbutil.ACLDirection(rule.gressPrefix)),
-			aclLoggingParams,
 		)
 		acls = append(acls, acl)
 	}
@@ -665,6 +668,7 @@ func (c *Controller) updateExistingANP(currentANPState, desiredANPState *adminNe
 	if !isBanp {
 		hasACLLoggingParamsChanged = hasACLLoggingParamsChanged || currentANPState.aclLoggingParams.Pass != desiredANPState.aclLoggingParams.Pass
 	}
+	hasDryRunChanged := currentANPState.dryRun != desiredANPState.dryRun
 	// The rules which didn't change -> those updates will be no-ops thanks to libovsdb
 	// The rules that changed in terms of their `getACLMutableFields`
 	// will be simply updated since externalIDs will remain the same for these ACLs
@@ -674,7 +678,8 @@ func (c *Controller) updateExistingANP(currentANPState, desiredANPState *adminNe
 	// (2) atLeastOneRuleUpdated=true which means the gress rules were of same lengths but action or ports changed on at least one rule
 	// (3) hasPriorityChanged=true which means we should update acl.Priority for every ACL
 	// (4) hasACLLoggingParamsChanged=true which means we should update acl.Severity/acl.Log for every ACL
-	if fullPeerRecompute || atLeastOneRuleUpdated || hasPriorityChanged || hasACLLoggingParamsChanged {
+	// (5) hasDryRunChanged=true which means we should update acl.Action/acl.Name/acl.ExternalIDs for every ACL
+	if fullPeerRecompute || atLeastOneRuleUpdated || hasPriorityChanged || hasACLLoggingParamsChanged || hasDryRunChanged {
 		klog.V(3).Infof("ANP %s with priority %d was updated", desiredANPState.name, desiredANPState.anpPriority)
 		// now update the acls to the desired ones
 		ops, err = libovsdbops.CreateOrUpdateACLsOps(c.nbClient, ops, c.GetSamplingConfig(), desiredACLs...)
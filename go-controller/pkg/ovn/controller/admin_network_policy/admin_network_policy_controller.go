@@ -26,11 +26,13 @@ import (
 
 	libovsdbclient "github.com/ovn-kubernetes/libovsdb/client"
 
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
 	controllerutil "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/controller"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/factory"
 	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/observability"
 	addressset "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
 )
 
@@ -104,6 +106,12 @@ type Controller struct {
 	anpNodeQueue  workqueue.TypedRateLimitingInterface[string]
 
 	observManager *observability.Manager
+
+	// getACLStats returns the counters of the given ACLs in the local zone, by ACL UUID, or false if they were
+	// not collected yet. nil when rule hits are disabled.
+	getACLStats func(aclUUIDs []string) (map[string]util.ACLStats, bool)
+	// ruleHits are the hit counts of the rules at the last rule hits update, only accessed by updateRuleHits
+	ruleHits map[anpRule]util.ACLStats
 }
 
 // NewController returns a new *Controller.
@@ -120,7 +128,8 @@ func NewController(
 	isPodScheduledinLocalZone func(*corev1.Pod) bool,
 	zone string,
 	recorder record.EventRecorder,
-	observManager *observability.Manager) (*Controller, error) {
+	observManager *observability.Manager,
+	aclStatsCollector *util.ACLStatsCollector) (*Controller, error) {
 
 	c := &Controller{
 		controllerName:            controllerName,
@@ -134,6 +143,9 @@ func NewController(
 		banpCache:                 &adminNetworkPolicyState{}, // safe to initialise pointer to empty struct than nil
 		observManager:             observManager,
 	}
+	if aclStatsCollector != nil {
+		c.getACLStats = aclStatsCollector.GetACLStats
+	}
 
 	klog.V(5).Info("Setting up event handlers for Admin Network Policy")
 	// setup anp informers, listers, queue
@@ -307,6 +319,15 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) {
 	}
 	c.setupMetricsCollector()
 
+	if config.OVNKubernetesFeature.PolicyRuleHitsInterval > 0 && c.getACLStats != nil {
+		klog.V(5).Info("Starting Admin Network Policy rule hits collection")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait.Until(c.updateRuleHits, config.OVNKubernetesFeature.PolicyRuleHitsInterval, stopCh)
+		}()
+	}
+
 	<-stopCh
 
 	klog.Infof("Shutting down controller %s", c.controllerName)
@@ -373,7 +394,8 @@ func (c *Controller) onANPUpdate(oldObj, newObj interface{}) {
 	}
 	oldANPACLAnnotation := oldANP.Annotations[util.AclLoggingAnnotation]
 	newANPACLAnnotation := newANP.Annotations[util.AclLoggingAnnotation]
-	if reflect.DeepEqual(oldANP.Spec, newANP.Spec) && oldANPACLAnnotation == newANPACLAnnotation {
+	if reflect.DeepEqual(oldANP.Spec, newANP.Spec) && oldANPACLAnnotation == newANPACLAnnotation &&
+		oldANP.Annotations[types.PolicyDryRunAnnotation] == newANP.Annotations[types.PolicyDryRunAnnotation] {
 		return
 	}
 	key, err := cache.MetaNamespaceKeyFunc(newObj)
@@ -421,7 +443,8 @@ func (c *Controller) onBANPUpdate(oldObj, newObj interface{}) {
 	}
 	oldBANPACLAnnotation := oldBANP.Annotations[util.AclLoggingAnnotation]
 	newBANPACLAnnotation := newBANP.Annotations[util.AclLoggingAnnotation]
-	if reflect.DeepEqual(oldBANP.Spec, newBANP.Spec) && oldBANPACLAnnotation == newBANPACLAnnotation {
+	if reflect.DeepEqual(oldBANP.Spec, newBANP.Spec) && oldBANPACLAnnotation == newBANPACLAnnotation &&
+		oldBANP.Annotations[types.PolicyDryRunAnnotation] == newBANP.Annotations[types.PolicyDryRunAnnotation] {
 		return
 	}
 
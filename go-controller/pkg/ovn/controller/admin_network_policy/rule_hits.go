@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package adminnetworkpolicy
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/metrics"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+)
+
+// The rules of (Baseline)Admin Network Policies that matched traffic in the zone are summarized in a condition
+// per zone, which is only updated when a rule gets its first hits:
+/* Sample Output ~~~~~~~~~~
+Status:
+  Conditions:
+    Last Transition Time:  2023-06-11T12:09:30Z
+    Message:               Hit by rules Egress/2, Ingress/0
+    Reason:                RulesHit
+    Status:                True
+    Type:                  Rules-Hit-In-Zone-ovn-worker
+*/
+
+// anpRule identifies an ingress or egress rule of an ANP or BANP
+type anpRule struct {
+	isBanp    bool
+	name      string
+	direction string
+	idx       int
+}
+
+func (r anpRule) String() string {
+	return fmt.Sprintf("%s/%d", r.direction, r.idx)
+}
+
+func (r anpRule) policyType() string {
+	if r.isBanp {
+		return "BANP"
+	}
+	return "ANP"
+}
+
+// anpPolicy identifies an ANP or BANP
+type anpPolicy struct {
+	isBanp bool
+	name   string
+}
+
+// updateRuleHits reads the hit counts of all ANP and BANP ACLs from the last ACL counters collection, adds the
+// new hits to the metrics and reports the rules that were hit in the policy status when a rule gets its first hits.
+func (c *Controller) updateRuleHits() {
+	var acls []*nbdb.ACL
+	for _, idType := range []*libovsdbops.ObjectIDsType{libovsdbops.ACLAdminNetworkPolicy, libovsdbops.ACLBaselineAdminNetworkPolicy} {
+		predicateIDs := libovsdbops.NewDbObjectIDs(idType, c.controllerName, nil)
+		typeACLs, err := libovsdbops.FindACLsWithPredicate(c.nbClient, libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil))
+		if err != nil {
+			klog.Errorf("Failed to find admin network policy ACLs: %v", err)
+			return
+		}
+		acls = append(acls, typeACLs...)
+	}
+	aclUUIDs := make([]string, 0, len(acls))
+	for _, acl := range acls {
+		aclUUIDs = append(aclUUIDs, acl.UUID)
+	}
+	aclStats, ok := c.getACLStats(aclUUIDs)
+	if !ok {
+		return
+	}
+	ruleHits := getANPRuleHits(acls, aclStats)
+
+	hitRules := map[anpPolicy][]anpRule{}
+	firstHitPolicies := map[anpPolicy]bool{}
+	for rule, stats := range ruleHits {
+		lastStats := c.ruleHits[rule]
+		newHits := stats.Since(lastStats)
+		metrics.AddAdminNetworkPolicyRuleHits(rule.policyType(), rule.name, rule.direction, rule.idx, newHits.Packets, newHits.Bytes)
+		if stats.Packets == 0 {
+			continue
+		}
+		policy := anpPolicy{isBanp: rule.isBanp, name: rule.name}
+		hitRules[policy] = append(hitRules[policy], rule)
+		if lastStats.Packets == 0 {
+			firstHitPolicies[policy] = true
+		}
+	}
+	for rule := range c.ruleHits {
+		if _, ok := ruleHits[rule]; !ok {
+			metrics.DeleteAdminNetworkPolicyRuleHits(rule.policyType(), rule.name, rule.direction, rule.idx)
+		}
+	}
+	c.ruleHits = ruleHits
+
+	for policy := range firstHitPolicies {
+		if err := c.updateRulesHitStatus(policy, hitRules[policy]); err != nil {
+			klog.Errorf("Failed to report rule hits in the status of %s: %v", policy.name, err)
+		}
+	}
+}
+
+// getANPRuleHits sums the hit counts of the given ANP and BANP ACLs per policy rule.
+// A rule may have multiple ACLs, one per protocol.
+func getANPRuleHits(acls []*nbdb.ACL, aclStats map[string]util.ACLStats) map[anpRule]util.ACLStats {
+	ruleHits := map[anpRule]util.ACLStats{}
+	for _, acl := range acls {
+		idx, err := strconv.Atoi(acl.ExternalIDs[libovsdbops.GressIdxKey.String()])
+		if err != nil {
+			continue
+		}
+		rule := anpRule{
+			isBanp:    acl.ExternalIDs[libovsdbops.OwnerTypeKey.String()] == string(libovsdbops.BaselineAdminNetworkPolicyOwnerType),
+			name:      acl.ExternalIDs[libovsdbops.ObjectNameKey.String()],
+			direction: acl.ExternalIDs[libovsdbops.PolicyDirectionKey.String()],
+			idx:       idx,
+		}
+		hits := ruleHits[rule]
+		hits.Packets += aclStats[acl.UUID].Packets
+		hits.Bytes += aclStats[acl.UUID].Bytes
+		ruleHits[rule] = hits
+	}
+	return ruleHits
+}
+
+// updateRulesHitStatus reports the given rules of the policy as hit in the zone status
+func (c *Controller) updateRulesHitStatus(policy anpPolicy, rules []anpRule) error {
+	slices.SortFunc(rules, func(a, b anpRule) int {
+		if a.direction != b.direction {
+			return strings.Compare(a.direction, b.direction)
+		}
+		return a.idx - b.idx
+	})
+	ruleNames := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ruleNames = append(ruleNames, rule.String())
+	}
+	message := fmt.Sprintf("Hit by rules %s", strings.Join(ruleNames, ", "))
+	if len(message) >= 32767 { // max length of message can be 32768
+		message = message[:32766]
+	}
+	hitCondition := metav1.Condition{
+		Type:    policyRulesHitStatusType + c.zone,
+		Status:  metav1.ConditionTrue,
+		Reason:  policyRulesHitReason,
+		Message: message,
+	}
+	var err error
+	if policy.isBanp {
+		err = c.updateBANPZoneStatusCondition(hitCondition, policy.name)
+	} else {
+		err = c.updateANPZoneStatusCondition(hitCondition, policy.name)
+	}
+	if apierrors.IsNotFound(err) {
+		// the policy was deleted, its ACLs will be removed
+		return nil
+	}
+	return err
+}
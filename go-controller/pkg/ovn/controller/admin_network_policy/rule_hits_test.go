@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package adminnetworkpolicy
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	anpapi "sigs.k8s.io/network-policy-api/apis/v1alpha1"
+	anpfake "sigs.k8s.io/network-policy-api/pkg/client/clientset/versioned/fake"
+	anplister "sigs.k8s.io/network-policy-api/pkg/client/listers/apis/v1alpha1"
+
+	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
+	libovsdbutil "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/util"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	libovsdbtest "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+)
+
+func TestUpdateRuleHits(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	const (
+		controllerName = "default-network-controller"
+		zone           = "targaryen"
+	)
+	ingress, egress := string(libovsdbutil.ACLIngress), string(libovsdbutil.ACLEgress)
+	ruleACL := func(uuid, name, direction, idx, protocol string, isBanp bool) *nbdb.ACL {
+		return &nbdb.ACL{
+			UUID:        uuid,
+			Action:      nbdb.ACLActionAllowRelated,
+			ExternalIDs: getANPRuleACLDbIDs(name, direction, idx, protocol, controllerName, isBanp).GetExternalIDs(),
+		}
+	}
+	nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(libovsdbtest.TestSetup{
+		NBData: []libovsdbtest.TestData{
+			ruleACL("acl1-UUID", initialANP.Name, ingress, "0", "tcp", false),
+			ruleACL("acl2-UUID", initialANP.Name, ingress, "0", "udp", false),
+			ruleACL("acl3-UUID", initialANP.Name, egress, "1", "None", false),
+			ruleACL("acl4-UUID", initialBANP.Name, ingress, "0", "None", true),
+			&nbdb.PortGroup{
+				UUID: "pg-UUID",
+				Name: "pg",
+				ACLs: []string{"acl1-UUID", "acl2-UUID", "acl3-UUID", "acl4-UUID"},
+			},
+		},
+	}, nil)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	t.Cleanup(cleanup.Cleanup)
+
+	readyCondition := metav1.Condition{
+		Type:    policyReadyStatusType + zone,
+		Status:  metav1.ConditionTrue,
+		Reason:  policyReadyReason,
+		Message: "Setting up OVN DB plumbing was successful",
+	}
+	anp := initialANP.DeepCopy()
+	anp.Status.Conditions = []metav1.Condition{readyCondition}
+	banp := initialBANP.DeepCopy()
+	banp.Status.Conditions = []metav1.Condition{readyCondition}
+	anpIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	g.Expect(anpIndexer.Add(anp)).To(gomega.Succeed())
+	banpIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	g.Expect(banpIndexer.Add(banp)).To(gomega.Succeed())
+
+	anpClient := anpfake.NewSimpleClientset(anp, banp)
+	var appliedANPConditions, appliedBANPConditions [][]metav1.Condition
+	anpClient.PrependReactor("patch", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch := action.(clienttesting.PatchAction).GetPatch()
+		switch action.GetResource().Resource {
+		case "adminnetworkpolicies":
+			applied := &anpapi.AdminNetworkPolicy{}
+			g.Expect(json.Unmarshal(patch, applied)).To(gomega.Succeed())
+			appliedANPConditions = append(appliedANPConditions, applied.Status.Conditions)
+			return true, applied, nil
+		default:
+			applied := &anpapi.BaselineAdminNetworkPolicy{}
+			g.Expect(json.Unmarshal(patch, applied)).To(gomega.Succeed())
+			appliedBANPConditions = append(appliedBANPConditions, applied.Status.Conditions)
+			return true, applied, nil
+		}
+	})
+
+	// the test server assigns new UUIDs, report hits by rule
+	packetsByRule := map[string]int64{"ANP/Ingress/0": 5}
+	c := &Controller{
+		controllerName: controllerName,
+		zone:           zone,
+		nbClient:       nbClient,
+		anpClientSet:   anpClient,
+		anpLister:      anplister.NewAdminNetworkPolicyLister(anpIndexer),
+		banpLister:     anplister.NewBaselineAdminNetworkPolicyLister(banpIndexer),
+		getACLStats: func(aclUUIDs []string) (map[string]util.ACLStats, bool) {
+			acls, err := libovsdbops.FindACLsWithPredicate(nbClient, func(*nbdb.ACL) bool { return true })
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			aclStats := map[string]util.ACLStats{}
+			for _, acl := range acls {
+				policyType := "ANP"
+				if acl.ExternalIDs[libovsdbops.OwnerTypeKey.String()] == string(libovsdbops.BaselineAdminNetworkPolicyOwnerType) {
+					policyType = "BANP"
+				}
+				ruleKey := strings.Join([]string{policyType, acl.ExternalIDs[libovsdbops.PolicyDirectionKey.String()],
+					acl.ExternalIDs[libovsdbops.GressIdxKey.String()]}, "/")
+				// only count the hits once per rule
+				if acl.ExternalIDs[libovsdbops.PortPolicyProtocolKey.String()] != "udp" {
+					aclStats[acl.UUID] = util.ACLStats{Packets: packetsByRule[ruleKey], Bytes: 100 * packetsByRule[ruleKey]}
+				}
+			}
+			return aclStats, true
+		},
+	}
+
+	// the rules that are already hit are reported on the first update
+	c.updateRuleHits()
+	g.Expect(appliedANPConditions).To(gomega.HaveLen(1))
+	g.Expect(appliedBANPConditions).To(gomega.BeEmpty())
+	// the ready condition owned by the zone is applied together with the rules hit condition
+	g.Expect(meta.FindStatusCondition(appliedANPConditions[0], readyCondition.Type)).NotTo(gomega.BeNil())
+	hitCondition := meta.FindStatusCondition(appliedANPConditions[0], policyRulesHitStatusType+zone)
+	g.Expect(hitCondition).NotTo(gomega.BeNil())
+	g.Expect(hitCondition.Reason).To(gomega.Equal(policyRulesHitReason))
+	g.Expect(hitCondition.Message).To(gomega.Equal("Hit by rules Ingress/0"))
+
+	packetsByRule["ANP/Egress/1"] = 3
+	packetsByRule["BANP/Ingress/0"] = 2
+	c.updateRuleHits()
+	g.Expect(appliedANPConditions).To(gomega.HaveLen(2))
+	g.Expect(appliedBANPConditions).To(gomega.HaveLen(1))
+	hitCondition = meta.FindStatusCondition(appliedANPConditions[1], policyRulesHitStatusType+zone)
+	g.Expect(hitCondition).NotTo(gomega.BeNil())
+	g.Expect(hitCondition.Message).To(gomega.Equal("Hit by rules Egress/1, Ingress/0"))
+	g.Expect(meta.FindStatusCondition(appliedBANPConditions[0], readyCondition.Type)).NotTo(gomega.BeNil())
+	hitCondition = meta.FindStatusCondition(appliedBANPConditions[0], policyRulesHitStatusType+zone)
+	g.Expect(hitCondition).NotTo(gomega.BeNil())
+	g.Expect(hitCondition.Message).To(gomega.Equal("Hit by rules Ingress/0"))
+
+	// no update without new hits, nor when the rules that were already hit get more hits
+	c.updateRuleHits()
+	packetsByRule["ANP/Ingress/0"] = 9
+	packetsByRule["ANP/Egress/1"] = 4
+	c.updateRuleHits()
+	g.Expect(appliedANPConditions).To(gomega.HaveLen(2))
+	g.Expect(appliedBANPConditions).To(gomega.HaveLen(1))
+
+	// the counters are reset when the rule is re-applied, the next hits are reported again
+	packetsByRule["BANP/Ingress/0"] = 0
+	c.updateRuleHits()
+	g.Expect(appliedBANPConditions).To(gomega.HaveLen(1))
+	packetsByRule["BANP/Ingress/0"] = 1
+	c.updateRuleHits()
+	g.Expect(appliedBANPConditions).To(gomega.HaveLen(2))
+	g.Expect(appliedANPConditions).To(gomega.HaveLen(2))
+}
@@ -47,8 +47,26 @@ const (
 	// Defined status.reason fields for (Baseline)Admin Network Policy
 	policyReadyReason    = "SetupSucceeded"
 	policyNotReadyReason = "SetupFailed"
+	// conditions.type of the summary of the rules that were hit in the zone, see rule_hits.go
+	policyRulesHitStatusType = "Rules-Hit-In-Zone-"
+	policyRulesHitReason     = "RulesHit"
 )
 
+// getOtherZoneConditions returns the conditions in the given list that are owned by the zone field manager,
+// other than the condition of the given type.
+func (c *Controller) getOtherZoneConditions(conditions []metav1.Condition, conditionType string) []metav1.Condition {
+	var zoneConditions []metav1.Condition
+	for _, zoneConditionType := range []string{policyReadyStatusType + c.zone, policyRulesHitStatusType + c.zone} {
+		if zoneConditionType == conditionType {
+			continue
+		}
+		if condition := meta.FindStatusCondition(conditions, zoneConditionType); condition != nil {
+			zoneConditions = append(zoneConditions, *condition)
+		}
+	}
+	return zoneConditions
+}
+
 // doesStatusNeedAnUpdate compares the existing condition with the new condition
 // and returns true if an update is needed, false if the status is already in the desired state.
 // This helps avoid unnecessary API server calls when the status hasn't changed.
@@ -124,8 +142,11 @@ func (c *Controller) updateANPZoneStatusCondition(newCondition metav1.Condition,
 		existingCondition.Message = newCondition.Message
 		newCondition = *existingCondition
 	}
+	// all the conditions owned by the zone field manager have to be applied together,
+	// otherwise server-side apply removes the ones that are left out
+	conditions := append([]metav1.Condition{newCondition}, c.getOtherZoneConditions(anp.Status.Conditions, newCondition.Type)...)
 	applyObj := anpapiapply.AdminNetworkPolicy(anpName).
-		WithStatus(anpapiapply.AdminNetworkPolicyStatus().WithConditions(newCondition))
+		WithStatus(anpapiapply.AdminNetworkPolicyStatus().WithConditions(conditions...))
 	_, err = c.anpClientSet.PolicyV1alpha1().AdminNetworkPolicies().
 		ApplyStatus(context.TODO(), applyObj, metav1.ApplyOptions{FieldManager: c.zone, Force: true})
 	if err == nil {
@@ -191,8 +212,11 @@ func (c *Controller) updateBANPZoneStatusCondition(newCondition metav1.Condition
 		existingCondition.Message = newCondition.Message
 		newCondition = *existingCondition
 	}
+	// all the conditions owned by the zone field manager have to be applied together,
+	// otherwise server-side apply removes the ones that are left out
+	conditions := append([]metav1.Condition{newCondition}, c.getOtherZoneConditions(banp.Status.Conditions, newCondition.Type)...)
 	applyObj := anpapiapply.BaselineAdminNetworkPolicy(banpName).
-		WithStatus(anpapiapply.BaselineAdminNetworkPolicyStatus().WithConditions(newCondition))
+		WithStatus(anpapiapply.BaselineAdminNetworkPolicyStatus().WithConditions(conditions...))
 	_, err = c.anpClientSet.PolicyV1alpha1().BaselineAdminNetworkPolicies().
 		ApplyStatus(context.TODO(), applyObj, metav1.ApplyOptions{FieldManager: c.zone, Force: true})
 	if err == nil {
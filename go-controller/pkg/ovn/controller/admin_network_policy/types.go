@@ -14,6 +14,7 @@ import (
 	anpapi "sigs.k8s.io/network-policy-api/apis/v1alpha1"
 
 	libovsdbutil "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/util"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 	utilerrors "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util/errors"
 )
 
@@ -97,6 +98,9 @@ type adminNetworkPolicyState struct {
 	// aclLoggingParams stores the log levels for the ACLs created for this ANP
 	// this is based off the "k8s.ovn.org/acl-logging" annotation set on the ANP's
 	aclLoggingParams *libovsdbutil.ACLLoggingLevels
+	// dryRun is set when the "k8s.ovn.org/policy-dry-run" annotation is set to "true" on the ANP,
+	// in which case the rules are rendered as pass ACLs tagged with the action they would have
+	dryRun bool
 }
 
 // newAdminNetworkPolicyState takes the provided ANP API object and creates a new corresponding
@@ -141,6 +145,7 @@ func newAdminNetworkPolicyState(raw *anpapi.AdminNetworkPolicy) (*adminNetworkPo
 	}
 	klog.V(5).Infof("Logging parameters for ANP %s are Allow=%s/Deny=%s/Pass=%s", raw.Name,
 		anp.aclLoggingParams.Allow, anp.aclLoggingParams.Deny, anp.aclLoggingParams.Pass)
+	anp.dryRun = raw.Annotations[types.PolicyDryRunAnnotation] == "true"
 	return anp, utilerrors.Join(errs...)
 }
 
@@ -398,6 +403,7 @@ func newBaselineAdminNetworkPolicyState(raw *anpapi.BaselineAdminNetworkPolicy)
 	}
 	klog.V(5).Infof("Logging parameters for BANP %s are Allow=%s/Deny=%s", raw.Name,
 		banp.aclLoggingParams.Allow, banp.aclLoggingParams.Deny)
+	banp.dryRun = raw.Annotations[types.PolicyDryRunAnnotation] == "true"
 	return banp, utilerrors.Join(errs...)
 }
 
@@ -18,6 +18,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
 	anpfake "sigs.k8s.io/network-policy-api/pkg/client/clientset/versioned/fake"
 
 	libovsdbclient "github.com/ovn-kubernetes/libovsdb/client"
@@ -101,7 +102,7 @@ var _ = ginkgo.Describe("OVN test basic functions", func() {
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		networkManager := &fakenetworkmanager.FakeNetworkManager{}
 		efController, err = NewEFController("test", "global", kubeInterface, nbClient, iFactory.NamespaceInformer().Lister(),
-			iFactory.NodeCoreInformer(), iFactory.EgressFirewallInformer(), networkManager, nil, nil)
+			iFactory.NodeCoreInformer(), iFactory.EgressFirewallInformer(), networkManager, nil, nil, nil)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		err = iFactory.Start()
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
@@ -162,6 +163,46 @@ var _ = ginkgo.Describe("OVN test basic functions", func() {
 				},
 				expectedMatch: "((udp && ( udp.dst == 400 )) || (tcp && ( tcp.dst == 100 || tcp.dst == 102 )) || (sctp && ( sctp.dst == 13 )))",
 			},
+			{
+				ports: []egressfirewallapi.EgressFirewallPort{
+					{
+						Protocol: "TCP",
+						Port:     30000,
+						EndPort:  ptr.To[int32](32767),
+					},
+					{
+						Protocol: "TCP",
+						Port:     80,
+					},
+					{
+						Protocol: "UDP",
+						Port:     53,
+						EndPort:  ptr.To[int32](53),
+					},
+				},
+				expectedMatch: "((udp && ( udp.dst == 53 )) || (tcp && ( 30000<=tcp.dst<=32767 || tcp.dst == 80 )))",
+			},
+			{
+				ports: []egressfirewallapi.EgressFirewallPort{
+					{
+						Protocol: "ICMP",
+						ICMPType: ptr.To[int32](8),
+					},
+					{
+						Protocol: "ICMP",
+						ICMPType: ptr.To[int32](3),
+						ICMPCode: ptr.To[int32](4),
+					},
+					{
+						Protocol: "ICMPv6",
+					},
+					{
+						Protocol: "TCP",
+						Port:     443,
+					},
+				},
+				expectedMatch: "((tcp && ( tcp.dst == 443 )) || (icmp4 && ( icmp4.type == 8 || (icmp4.type == 3 && icmp4.code == 4) )) || (icmp6))",
+			},
 		}
 		for _, test := range testcases {
 			l4Match := egressGetL4Match(test.ports)
@@ -317,6 +358,30 @@ var _ = ginkgo.Describe("OVN test basic functions", func() {
 				errOutput: "invalid CIDR address: 1.2.3./32",
 				output:    egressFirewallRule{},
 			},
+			{
+				clusterSubnets: []string{"10.128.0.0/16"},
+				egressFirewallRule: egressfirewallapi.EgressFirewallRule{
+					Type:  egressfirewallapi.EgressFirewallRuleAllow,
+					To:    egressfirewallapi.EgressFirewallDestination{CIDRSelector: "1.2.3.4/32"},
+					Ports: []egressfirewallapi.EgressFirewallPort{{Protocol: "ICMP", Port: 80}},
+				},
+				id:        1,
+				err:       true,
+				errOutput: "port and endPort are not supported for ICMP",
+				output:    egressFirewallRule{},
+			},
+			{
+				clusterSubnets: []string{"10.128.0.0/16"},
+				egressFirewallRule: egressfirewallapi.EgressFirewallRule{
+					Type:  egressfirewallapi.EgressFirewallRuleAllow,
+					To:    egressfirewallapi.EgressFirewallDestination{CIDRSelector: "1.2.3.4/32"},
+					Ports: []egressfirewallapi.EgressFirewallPort{{Protocol: "TCP", Port: 80, EndPort: ptr.To[int32](79)}},
+				},
+				id:        1,
+				err:       true,
+				errOutput: "endPort 79 requires port and must be greater than or equal to port 80",
+				output:    egressFirewallRule{},
+			},
 			{
 				clusterSubnets: []string{"2002:0:0:1234::/64"},
 				egressFirewallRule: egressfirewallapi.EgressFirewallRule{
@@ -21,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -64,6 +65,10 @@ const (
 	egressFirewallName             = "default"
 )
 
+// validLogLevels are the various pre-established ACL log levels or the empty string.
+var validLogLevels = sets.NewString(nbdb.ACLSeverityAlert, nbdb.ACLSeverityWarning, nbdb.ACLSeverityNotice,
+	nbdb.ACLSeverityInfo, nbdb.ACLSeverityDebug, "")
+
 const (
 	matchKindV4CIDR matchKind = iota
 	matchKindV6CIDR
@@ -82,6 +87,10 @@ type egressFirewallRule struct {
 	access egressfirewallapi.EgressFirewallRuleType
 	ports  []egressfirewallapi.EgressFirewallPort
 	to     destination
+	// logLevel overrides the namespace ACL logging levels when set
+	logLevel string
+	// disableSampling excludes the rule ACL from observability sampling
+	disableSampling bool
 }
 
 type destination struct {
@@ -141,6 +150,14 @@ type EFController struct {
 	// used in egress firewall rules
 	dnsNameResolver dnsnameresolver.DNSNameResolver
 	observManager   *observability.Manager
+
+	// getACLHits returns the number of packets that matched each of the given ACLs in the local zone, by ACL UUID,
+	// or false if they were not collected yet. nil when rule hits are disabled.
+	getACLHits func(acls []*nbdb.ACL) (map[string]int64, bool)
+	// ruleHitPackets holds the packet counts of the rules at the last collection, only used by updateRuleHits
+	ruleHitPackets map[efRule]int64
+	ruleHitsStop   chan struct{}
+	ruleHitsWg     sync.WaitGroup
 }
 
 func NewEFController(
@@ -154,6 +171,7 @@ func NewEFController(
 	networkManager networkmanager.Interface,
 	dnsNameResolver dnsnameresolver.DNSNameResolver,
 	observManager *observability.Manager,
+	aclStatsCollector *util.ACLStatsCollector,
 ) (*EFController, error) {
 	c := &EFController{
 		name:            name,
@@ -169,6 +187,11 @@ func NewEFController(
 		observManager:   observManager,
 		ruleCounter:     sync.Map{},
 	}
+	if aclStatsCollector != nil {
+		c.getACLHits = func(acls []*nbdb.ACL) (map[string]int64, bool) {
+			return getACLHits(aclStatsCollector, acls)
+		}
+	}
 
 	controllerConfig := &controller.ControllerConfig[egressfirewallapi.EgressFirewall]{
 		RateLimiter: workqueue.DefaultTypedControllerRateLimiter[string](),
@@ -374,11 +397,27 @@ func (oc *EFController) Start() (err error) {
 			oc.nadReconcilerID = 0
 		}
 	}()
-	return controller.StartWithInitialSync(oc.initialSync, oc.controller, oc.nodeController, oc.nadReconciler)
+	if err = controller.StartWithInitialSync(oc.initialSync, oc.controller, oc.nodeController, oc.nadReconciler); err != nil {
+		return err
+	}
+	if interval := config.OVNKubernetesFeature.EgressFirewallRuleHitsInterval; interval > 0 && oc.getACLHits != nil {
+		oc.ruleHitsStop = make(chan struct{})
+		oc.ruleHitsWg.Add(1)
+		go func() {
+			defer oc.ruleHitsWg.Done()
+			wait.Until(oc.updateRuleHits, interval, oc.ruleHitsStop)
+		}()
+	}
+	return nil
 }
 
 func (oc *EFController) Stop() {
 	klog.Infof("%s: shutting down", oc.name)
+	if oc.ruleHitsStop != nil {
+		close(oc.ruleHitsStop)
+		oc.ruleHitsWg.Wait()
+		oc.ruleHitsStop = nil
+	}
 	if oc.nadReconcilerID != 0 {
 		oc.networkManager.DeRegisterNADReconciler(oc.nadReconcilerID)
 	}
@@ -659,8 +698,18 @@ func (oc *EFController) validateAndGetEgressFirewallDestination(namespace string
 func (oc *EFController) newEgressFirewallRule(namespace string, rawEgressFirewallRule egressfirewallapi.EgressFirewallRule,
 	id int, entry *cacheEntry) (*egressFirewallRule, error) {
 	efr := &egressFirewallRule{
-		id:     id,
-		access: rawEgressFirewallRule.Type,
+		id:              id,
+		access:          rawEgressFirewallRule.Type,
+		logLevel:        rawEgressFirewallRule.LogLevel,
+		disableSampling: rawEgressFirewallRule.Sample != nil && !*rawEgressFirewallRule.Sample,
+	}
+	if efr.logLevel != "" && efr.logLevel != egressfirewallapi.EgressFirewallRuleLogLevelNone &&
+		!validLogLevels.Has(efr.logLevel) {
+		return efr, fmt.Errorf("invalid log level %q", efr.logLevel)
+	}
+
+	if err := validateEgressFirewallPorts(rawEgressFirewallRule.Ports); err != nil {
+		return efr, err
 	}
 
 	// Validate the egress firewall rule destination and update the appropriate
@@ -792,7 +841,7 @@ func (oc *EFController) addEgressFirewallRules(ef *egressFirewall, pgName string
 			// If DNSNameResolver is enabled, then use the egressFirewallExternalDNS to get the address
 			// set corresponding to the DNS name, otherwise use the egressFirewallDNS
 			// to get the address set.
-			if config.OVNKubernetesFeature.EnableDNSNameResolver {
+			if config.OVNKubernetesFeature.EnableDNSNameResolver || util.IsWildcard(dnsName) {
 				// Convert the DNS name to lower case fully qualified domain name.
 				dnsName = util.LowerCaseFQDN(rule.to.dnsName)
 			}
@@ -826,10 +875,13 @@ func (oc *EFController) addEgressFirewallRules(ef *egressFirewall, pgName string
 			priority,
 			match,
 			action,
-			aclLogging,
+			getRuleACLLogging(rule, aclLogging),
 			// since egressFirewall has direction to-lport, set type to ingress
 			libovsdbutil.LportIngress,
 		)
+		if rule.disableSampling {
+			egressFirewallACL.ExternalIDs[libovsdbops.SamplingDisabledKey] = "true"
+		}
 
 		ops, err = oc.createEgressFirewallACLOps(ops, egressFirewallACL, pgName)
 		if err != nil {
@@ -852,6 +904,21 @@ func (oc *EFController) addEgressFirewallRules(ef *egressFirewall, pgName string
 	return nil
 }
 
+// getRuleACLLogging returns the ACL logging levels for the given rule: the rule log level if set,
+// otherwise the namespace ACL logging levels.
+func getRuleACLLogging(rule *egressFirewallRule, nsACLLogging *libovsdbutil.ACLLoggingLevels) *libovsdbutil.ACLLoggingLevels {
+	switch rule.logLevel {
+	case "":
+		return nsACLLogging
+	case egressfirewallapi.EgressFirewallRuleLogLevelNone:
+		return &libovsdbutil.ACLLoggingLevels{}
+	}
+	return &libovsdbutil.ACLLoggingLevels{
+		Allow: rule.logLevel,
+		Deny:  rule.logLevel,
+	}
+}
+
 // moveACLsToNamespacedPortGroups syncs db from the previous version where all ACLs were attached to the ClusterPortGroup
 // to the new version where ACLs are attached to the namespace port groups.
 func (oc *EFController) moveACLsToNamespacedPortGroups(existingEFNamespaces map[string]bool, efACLs []*nbdb.ACL) error {
@@ -1011,16 +1078,28 @@ func (oc *EFController) setEgressFirewallStatus(egressFirewall *egressfirewallap
 		return nil
 	}
 
+	// keep the reported rule hits, they are owned by the same field manager
+	return oc.applyEgressFirewallStatus(egressFirewall, newMsg, oc.getZoneRuleHits(egressFirewall))
+}
+
+// applyEgressFirewallStatus applies the status message and rule hits of the local zone.
+func (oc *EFController) applyEgressFirewallStatus(egressFirewall *egressfirewallapi.EgressFirewall, zoneMsg string,
+	ruleHits []egressfirewallapi.EgressFirewallRuleHits) error {
 	applyOptions := metav1.ApplyOptions{
 		Force:        true,
 		FieldManager: oc.zone,
 	}
 
+	statusApply := egressfirewallapply.EgressFirewallStatus().WithMessages(zoneMsg)
+	for _, hits := range ruleHits {
+		statusApply.WithRuleHits(egressfirewallapply.EgressFirewallRuleHits().
+			WithIndex(hits.Index).
+			WithLastHitTime(hits.LastHitTime).
+			WithZone(hits.Zone))
+	}
 	applyObj := egressfirewallapply.EgressFirewall(egressFirewall.Name, egressFirewall.Namespace).
-		WithStatus(egressfirewallapply.EgressFirewallStatus().
-			WithMessages(newMsg))
+		WithStatus(statusApply)
 	_, err := oc.kube.EgressFirewallClient.K8sV1().EgressFirewalls(egressFirewall.Namespace).ApplyStatus(context.TODO(), applyObj, applyOptions)
-
 	return err
 }
 
@@ -1103,10 +1182,6 @@ func parseACLLogging(annotation string) *libovsdbutil.ACLLoggingLevels {
 		}
 	}
 
-	// Valid log levels are the various pre-established levels or the empty string.
-	validLogLevels := sets.NewString(nbdb.ACLSeverityAlert, nbdb.ACLSeverityWarning, nbdb.ACLSeverityNotice,
-		nbdb.ACLSeverityInfo, nbdb.ACLSeverityDebug, "")
-
 	// Set Deny logging.
 	if !validLogLevels.Has(aclLevels.Deny) {
 		aclLevels.Deny = ""
@@ -1171,68 +1246,105 @@ func generateMatch(pgName string, destinations []matchTarget, dstPorts []egressf
 // a single rule is to build up each protocol as you walk through the list and place the appropriate logic
 // between the elements.
 func egressGetL4Match(ports []egressfirewallapi.EgressFirewallPort) string {
-	var udpString string
-	var tcpString string
-	var sctpString string
+	// protocol name: "||"-separated port matches, or the protocol name when all the protocol traffic matches
+	protocolMatches := map[string]string{}
 	for _, port := range ports {
-		if corev1.Protocol(port.Protocol) == corev1.ProtocolUDP && udpString != "udp" {
-			if port.Port == 0 {
-				udpString = "udp"
-			} else {
-				udpString = fmt.Sprintf("%s udp.dst == %d ||", udpString, port.Port)
-			}
-		} else if corev1.Protocol(port.Protocol) == corev1.ProtocolTCP && tcpString != "tcp" {
-			if port.Port == 0 {
-				tcpString = "tcp"
-			} else {
-				tcpString = fmt.Sprintf("%s tcp.dst == %d ||", tcpString, port.Port)
-			}
-		} else if corev1.Protocol(port.Protocol) == corev1.ProtocolSCTP && sctpString != "sctp" {
-			if port.Port == 0 {
-				sctpString = "sctp"
-			} else {
-				sctpString = fmt.Sprintf("%s sctp.dst == %d ||", sctpString, port.Port)
-			}
+		protocolName := getL4ProtocolName(port.Protocol)
+		if protocolName == "" || protocolMatches[protocolName] == protocolName {
+			continue
+		}
+		portMatch := getL4PortMatch(protocolName, port)
+		if portMatch == "" {
+			protocolMatches[protocolName] = protocolName
+		} else {
+			protocolMatches[protocolName] = fmt.Sprintf("%s %s ||", protocolMatches[protocolName], portMatch)
 		}
 	}
 	// build the l4 match
 	var l4Match string
-	type tuple struct {
-		protocolName     string
-		protocolFormated string
-	}
-	list := []tuple{
-		{
-			protocolName:     "udp",
-			protocolFormated: udpString,
-		},
-		{
-			protocolName:     "tcp",
-			protocolFormated: tcpString,
-		},
-		{
-			protocolName:     "sctp",
-			protocolFormated: sctpString,
-		},
-	}
-	for _, entry := range list {
-		if entry.protocolName == entry.protocolFormated {
+	for _, protocolName := range []string{"udp", "tcp", "sctp", "icmp4", "icmp6"} {
+		protocolFormated := protocolMatches[protocolName]
+		if protocolName == protocolFormated {
 			if l4Match == "" {
-				l4Match = fmt.Sprintf("(%s)", entry.protocolName)
+				l4Match = fmt.Sprintf("(%s)", protocolName)
 			} else {
-				l4Match = fmt.Sprintf("%s || (%s)", l4Match, entry.protocolName)
+				l4Match = fmt.Sprintf("%s || (%s)", l4Match, protocolName)
 			}
 		} else {
-			if l4Match == "" && entry.protocolFormated != "" {
-				l4Match = fmt.Sprintf("(%s && (%s))", entry.protocolName, entry.protocolFormated[:len(entry.protocolFormated)-2])
-			} else if entry.protocolFormated != "" {
-				l4Match = fmt.Sprintf("%s || (%s && (%s))", l4Match, entry.protocolName, entry.protocolFormated[:len(entry.protocolFormated)-2])
+			if l4Match == "" && protocolFormated != "" {
+				l4Match = fmt.Sprintf("(%s && (%s))", protocolName, protocolFormated[:len(protocolFormated)-2])
+			} else if protocolFormated != "" {
+				l4Match = fmt.Sprintf("%s || (%s && (%s))", l4Match, protocolName, protocolFormated[:len(protocolFormated)-2])
 			}
 		}
 	}
 	return fmt.Sprintf("(%s)", l4Match)
 }
 
+// getL4ProtocolName returns the OVN match field name of the given egress firewall port protocol.
+func getL4ProtocolName(protocol string) string {
+	switch protocol {
+	case string(corev1.ProtocolUDP):
+		return "udp"
+	case string(corev1.ProtocolTCP):
+		return "tcp"
+	case string(corev1.ProtocolSCTP):
+		return "sctp"
+	case egressfirewallapi.EgressFirewallProtocolICMP:
+		return "icmp4"
+	case egressfirewallapi.EgressFirewallProtocolICMPv6:
+		return "icmp6"
+	}
+	return ""
+}
+
+// getL4PortMatch returns the match for the given port of the protocol, or "" if all the protocol traffic matches.
+func getL4PortMatch(protocolName string, port egressfirewallapi.EgressFirewallPort) string {
+	switch {
+	case protocolName == "icmp4" || protocolName == "icmp6":
+		if port.ICMPType == nil {
+			return ""
+		}
+		if port.ICMPCode == nil {
+			return fmt.Sprintf("%s.type == %d", protocolName, *port.ICMPType)
+		}
+		return fmt.Sprintf("(%s.type == %d && %s.code == %d)", protocolName, *port.ICMPType, protocolName, *port.ICMPCode)
+	case port.Port == 0:
+		return ""
+	case port.EndPort != nil && *port.EndPort > port.Port:
+		return fmt.Sprintf("%d<=%s.dst<=%d", port.Port, protocolName, *port.EndPort)
+	default:
+		return fmt.Sprintf("%s.dst == %d", protocolName, port.Port)
+	}
+}
+
+// validateEgressFirewallPorts validates the port fields that are not allowed for the port protocol.
+// The same validation is done by the CRD, this covers API servers without CEL validation rules.
+func validateEgressFirewallPorts(ports []egressfirewallapi.EgressFirewallPort) error {
+	for _, port := range ports {
+		protocolName := getL4ProtocolName(port.Protocol)
+		switch {
+		case protocolName == "":
+			return fmt.Errorf("invalid protocol %q", port.Protocol)
+		case protocolName == "icmp4" || protocolName == "icmp6":
+			if port.Port != 0 || port.EndPort != nil {
+				return fmt.Errorf("port and endPort are not supported for %s", port.Protocol)
+			}
+			if port.ICMPCode != nil && port.ICMPType == nil {
+				return errors.New("icmpCode requires icmpType")
+			}
+		default:
+			if port.ICMPType != nil || port.ICMPCode != nil {
+				return fmt.Errorf("icmpType and icmpCode are not supported for %s", port.Protocol)
+			}
+			if port.EndPort != nil && (port.Port == 0 || *port.EndPort < port.Port) {
+				return fmt.Errorf("endPort %d requires port and must be greater than or equal to port %d", *port.EndPort, port.Port)
+			}
+		}
+	}
+	return nil
+}
+
 func getV4ClusterSubnetsExclusion(subnets []*net.IPNet) string {
 	var exclusions []string
 	for _, clusterSubnet := range subnets {
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package egressfirewall
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	egressfirewallapi "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+)
+
+// getACLHits returns the number of packets that matched each of the given ACLs in the local br-int flows
+// at the last collection of the ACL counters, by ACL UUID, or false if they were not collected yet.
+func getACLHits(aclStatsCollector *util.ACLStatsCollector, acls []*nbdb.ACL) (map[string]int64, bool) {
+	aclUUIDs := make([]string, 0, len(acls))
+	for _, acl := range acls {
+		aclUUIDs = append(aclUUIDs, acl.UUID)
+	}
+	aclStats, ok := aclStatsCollector.GetACLStats(aclUUIDs)
+	if !ok {
+		return nil, false
+	}
+	hits := make(map[string]int64, len(aclStats))
+	for aclUUID, stats := range aclStats {
+		hits[aclUUID] = stats.Packets
+	}
+	return hits, true
+}
+
+// ruleLastHitTimeResolution is how old the last hit time of a rule has to be before a new hit is reported,
+// so that rules that are constantly hit don't update the status on every collection.
+const ruleLastHitTimeResolution = 5 * time.Minute
+
+// efRule identifies a rule of the egress firewall in a namespace.
+type efRule struct {
+	namespace string
+	index     int
+}
+
+// updateRuleHits collects the hit counts of all egress firewall ACLs and reports the rules that got new hits
+// since the last collection in the egress firewall status. The first collection is only used as a baseline.
+func (oc *EFController) updateRuleHits() {
+	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.ACLEgressFirewall, types.DefaultNetworkControllerName, nil)
+	efACLs, err := libovsdbops.FindACLsWithPredicate(oc.nbClient, libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil))
+	if err != nil {
+		klog.Errorf("%s: failed to find egress firewall ACLs: %v", oc.name, err)
+		return
+	}
+	aclHits, ok := oc.getACLHits(efACLs)
+	if !ok {
+		return
+	}
+	ruleHitPackets := map[efRule]int64{}
+	for _, acl := range efACLs {
+		ruleIdx, err := strconv.Atoi(acl.ExternalIDs[libovsdbops.RuleIndex.String()])
+		if err != nil {
+			continue
+		}
+		rule := efRule{namespace: acl.ExternalIDs[libovsdbops.ObjectNameKey.String()], index: ruleIdx}
+		ruleHitPackets[rule] += aclHits[acl.UUID]
+	}
+	// namespace: indexes of the rules with new hits
+	namespaceHits := map[string]sets.Set[int]{}
+	for rule, packets := range ruleHitPackets {
+		oldPackets, ok := oc.ruleHitPackets[rule]
+		// counters are reset when the rule is re-applied
+		if !ok || packets == 0 || packets == oldPackets {
+			continue
+		}
+		if namespaceHits[rule.namespace] == nil {
+			namespaceHits[rule.namespace] = sets.New[int]()
+		}
+		namespaceHits[rule.namespace].Insert(rule.index)
+	}
+	oc.ruleHitPackets = ruleHitPackets
+	for namespace, hitRules := range namespaceHits {
+		err := oc.cache.DoWithLock(namespace, func(key string) error {
+			ef, err := oc.efLister.EgressFirewalls(key).Get(egressFirewallName)
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			return oc.setEgressFirewallRuleHits(ef, hitRules)
+		})
+		if err != nil {
+			klog.Errorf("%s: failed to update rule hits for egress firewall in namespace %s: %v", oc.name, namespace, err)
+		}
+	}
+}
+
+// setEgressFirewallRuleHits reports the last hit time of the given rules in the egress firewall status, unless
+// another hit was reported within ruleLastHitTimeResolution. The status has one entry per rule, owned by the zone
+// that reported the last hit.
+// Hits are only reported after the zone status message, since both are owned by the zone field manager and
+// have to be applied together.
+func (oc *EFController) setEgressFirewallRuleHits(egressFirewall *egressfirewallapi.EgressFirewall, hitRules sets.Set[int]) error {
+	zoneMsg := oc.getZoneStatusMessage(egressFirewall)
+	if zoneMsg == "" {
+		return nil
+	}
+	lastHits := make(map[int32]egressfirewallapi.EgressFirewallRuleHits, len(egressFirewall.Status.RuleHits))
+	for _, hits := range egressFirewall.Status.RuleHits {
+		lastHits[hits.Index] = hits
+	}
+	zoneHits := map[int32]egressfirewallapi.EgressFirewallRuleHits{}
+	for _, hits := range oc.getZoneRuleHits(egressFirewall) {
+		zoneHits[hits.Index] = hits
+	}
+	now := metav1.Now()
+	updated := false
+	for ruleIdx := range hitRules {
+		if ruleIdx >= len(egressFirewall.Spec.Egress) {
+			// stale ACL, will be removed by the next sync
+			continue
+		}
+		if lastHit, ok := lastHits[int32(ruleIdx)]; ok && now.Sub(lastHit.LastHitTime.Time) < ruleLastHitTimeResolution {
+			continue
+		}
+		zoneHits[int32(ruleIdx)] = egressfirewallapi.EgressFirewallRuleHits{
+			Index:       int32(ruleIdx),
+			LastHitTime: now,
+			Zone:        oc.zone,
+		}
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+	newHits := make([]egressfirewallapi.EgressFirewallRuleHits, 0, len(zoneHits))
+	for _, hits := range zoneHits {
+		newHits = append(newHits, hits)
+	}
+	slices.SortFunc(newHits, func(a, b egressfirewallapi.EgressFirewallRuleHits) int {
+		return int(a.Index - b.Index)
+	})
+	return oc.applyEgressFirewallStatus(egressFirewall, zoneMsg, newHits)
+}
+
+// getZoneStatusMessage returns the status message of the local zone, or "" if there is none.
+func (oc *EFController) getZoneStatusMessage(egressFirewall *egressfirewallapi.EgressFirewall) string {
+	for _, message := range egressFirewall.Status.Messages {
+		if types.GetZoneFromStatus(message) == oc.zone {
+			return message
+		}
+	}
+	return ""
+}
+
+// getZoneRuleHits returns the rule hits reported by the local zone for the existing rules, sorted by rule index.
+func (oc *EFController) getZoneRuleHits(egressFirewall *egressfirewallapi.EgressFirewall) []egressfirewallapi.EgressFirewallRuleHits {
+	var zoneHits []egressfirewallapi.EgressFirewallRuleHits
+	for _, hits := range egressFirewall.Status.RuleHits {
+		if hits.Zone == oc.zone && int(hits.Index) < len(egressFirewall.Spec.Egress) {
+			zoneHits = append(zoneHits, hits)
+		}
+	}
+	slices.SortFunc(zoneHits, func(a, b egressfirewallapi.EgressFirewallRuleHits) int {
+		return int(a.Index - b.Index)
+	})
+	return zoneHits
+}
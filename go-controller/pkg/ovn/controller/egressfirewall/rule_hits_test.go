@@ -0,0 +1,251 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package egressfirewall
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	egressfirewallapi "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1"
+	egressfirewallfake "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/clientset/versioned/fake"
+	egressfirewalllisters "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/egressfirewall/v1/apis/listers/egressfirewall/v1"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/kube"
+	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
+	libovsdbutil "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/util"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	fakenetworkmanager "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/networkmanager"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/syncmap"
+	libovsdbtest "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+)
+
+func TestEFControllerSync_RuleLogLevelAndSample(t *testing.T) {
+	require.NoError(t, config.PrepareTestConfig())
+
+	const (
+		namespace = "namespace1"
+		zone      = "global"
+	)
+	pgName := libovsdbutil.GetPortGroupName(getNamespacePortGroupDbIDs(namespace, types.DefaultNetworkControllerName))
+	nbClient, _, cleanup, err := libovsdbtest.NewNBSBTestHarness(libovsdbtest.TestSetup{
+		NBData: []libovsdbtest.TestData{
+			&nbdb.PortGroup{Name: pgName},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(cleanup.Cleanup)
+
+	nsIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	require.NoError(t, nsIndexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        namespace,
+		Annotations: map[string]string{util.AclLoggingAnnotation: `{"deny": "alert", "allow": "notice"}`},
+	}}))
+	efIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	ef := &egressfirewallapi.EgressFirewall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            egressFirewallName,
+			Namespace:       namespace,
+			ResourceVersion: "1",
+		},
+		Spec: egressfirewallapi.EgressFirewallSpec{
+			Egress: []egressfirewallapi.EgressFirewallRule{
+				{
+					Type:     egressfirewallapi.EgressFirewallRuleAllow,
+					To:       egressfirewallapi.EgressFirewallDestination{CIDRSelector: "1.2.3.4/32"},
+					LogLevel: nbdb.ACLSeverityDebug,
+				},
+				{
+					Type:     egressfirewallapi.EgressFirewallRuleDeny,
+					To:       egressfirewallapi.EgressFirewallDestination{CIDRSelector: "1.2.3.5/32"},
+					LogLevel: egressfirewallapi.EgressFirewallRuleLogLevelNone,
+					Sample:   ptr.To(false),
+				},
+				{
+					Type: egressfirewallapi.EgressFirewallRuleDeny,
+					To:   egressfirewallapi.EgressFirewallDestination{CIDRSelector: "0.0.0.0/0"},
+				},
+			},
+		},
+		Status: egressfirewallapi.EgressFirewallStatus{
+			Messages: []string{types.GetZoneStatus(zone, EgressFirewallAppliedCorrectly)},
+		},
+	}
+	require.NoError(t, efIndexer.Add(ef))
+
+	oc := &EFController{
+		name:            "test",
+		zone:            zone,
+		cache:           syncmap.NewSyncMap[*cacheEntry](),
+		nbClient:        nbClient,
+		namespaceLister: corelisters.NewNamespaceLister(nsIndexer),
+		efLister:        egressfirewalllisters.NewEgressFirewallLister(efIndexer),
+		networkManager:  &fakenetworkmanager.FakeNetworkManager{},
+		ruleCounter:     sync.Map{},
+		dnsNameResolver: noopDNSNameResolver{},
+	}
+	oc.ruleCounter.Store(namespace+"/"+egressFirewallName, uint32(len(ef.Spec.Egress)))
+	require.NoError(t, oc.sync(namespace+"/"+egressFirewallName))
+
+	getACL := func(ruleIdx int) *nbdb.ACL {
+		p := libovsdbops.GetPredicate[*nbdb.ACL](oc.GetEgressFirewallACLDbIDs(namespace, ruleIdx), nil)
+		acls, err := libovsdbops.FindACLsWithPredicate(nbClient, p)
+		require.NoError(t, err)
+		require.Len(t, acls, 1)
+		return acls[0]
+	}
+	// rule log level overrides the namespace allow level
+	acl := getACL(0)
+	assert.True(t, acl.Log)
+	assert.Equal(t, nbdb.ACLSeverityDebug, *acl.Severity)
+	assert.NotContains(t, acl.ExternalIDs, libovsdbops.SamplingDisabledKey)
+	// logging and sampling disabled for the rule
+	acl = getACL(1)
+	assert.False(t, acl.Log)
+	assert.Equal(t, "true", acl.ExternalIDs[libovsdbops.SamplingDisabledKey])
+	// namespace deny level is used
+	acl = getACL(2)
+	assert.True(t, acl.Log)
+	assert.Equal(t, nbdb.ACLSeverityAlert, *acl.Severity)
+}
+
+func TestEFControllerUpdateRuleHits(t *testing.T) {
+	require.NoError(t, config.PrepareTestConfig())
+
+	const (
+		namespace = "namespace1"
+		zone      = "node1"
+	)
+	aclDbIDs := func(ruleIdx string) map[string]string {
+		return map[string]string{
+			libovsdbops.OwnerTypeKey.String():       libovsdbops.EgressFirewallOwnerType,
+			libovsdbops.OwnerControllerKey.String(): types.DefaultNetworkControllerName,
+			libovsdbops.ObjectNameKey.String():      namespace,
+			libovsdbops.RuleIndex.String():          ruleIdx,
+		}
+	}
+	nbClient, _, cleanup, err := libovsdbtest.NewNBSBTestHarness(libovsdbtest.TestSetup{
+		NBData: []libovsdbtest.TestData{
+			&nbdb.ACL{UUID: "acl0-UUID", Action: nbdb.ACLActionAllow, ExternalIDs: aclDbIDs("0")},
+			&nbdb.ACL{UUID: "acl1-UUID", Action: nbdb.ACLActionDrop, ExternalIDs: aclDbIDs("1")},
+			// stale ACL for a deleted rule
+			&nbdb.ACL{UUID: "acl2-UUID", Action: nbdb.ACLActionDrop, ExternalIDs: aclDbIDs("2")},
+			&nbdb.PortGroup{UUID: "pg-UUID", Name: "pg", ACLs: []string{"acl0-UUID", "acl1-UUID", "acl2-UUID"}},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(cleanup.Cleanup)
+
+	ef := &egressfirewallapi.EgressFirewall{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      egressFirewallName,
+			Namespace: namespace,
+		},
+		Spec: egressfirewallapi.EgressFirewallSpec{
+			Egress: []egressfirewallapi.EgressFirewallRule{
+				{
+					Type: egressfirewallapi.EgressFirewallRuleAllow,
+					To:   egressfirewallapi.EgressFirewallDestination{CIDRSelector: "1.2.3.4/32"},
+				},
+				{
+					Type: egressfirewallapi.EgressFirewallRuleDeny,
+					To:   egressfirewallapi.EgressFirewallDestination{CIDRSelector: "0.0.0.0/0"},
+				},
+			},
+		},
+		Status: egressfirewallapi.EgressFirewallStatus{
+			Messages: []string{types.GetZoneStatus(zone, EgressFirewallAppliedCorrectly)},
+			RuleHits: []egressfirewallapi.EgressFirewallRuleHits{
+				{Index: 1, LastHitTime: metav1.NewTime(time.Now().Add(-time.Minute)), Zone: "node2"},
+			},
+		},
+	}
+	efIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	require.NoError(t, efIndexer.Add(ef))
+
+	efClient := egressfirewallfake.NewSimpleClientset(ef)
+	var appliedStatuses []egressfirewallapi.EgressFirewallStatus
+	efClient.PrependReactor("patch", "egressfirewalls", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		applied := &egressfirewallapi.EgressFirewall{}
+		require.NoError(t, json.Unmarshal(action.(clienttesting.PatchAction).GetPatch(), applied))
+		appliedStatuses = append(appliedStatuses, applied.Status)
+		return true, applied, nil
+	})
+
+	// the test server assigns new UUIDs, report hits by rule index
+	hitsByRuleIdx := map[string]int64{"0": 4, "1": 12, "2": 1}
+	oc := &EFController{
+		name:     "test",
+		zone:     zone,
+		cache:    syncmap.NewSyncMap[*cacheEntry](),
+		nbClient: nbClient,
+		kube:     &kube.KubeOVN{EgressFirewallClient: efClient},
+		efLister: egressfirewalllisters.NewEgressFirewallLister(efIndexer),
+		getACLHits: func(acls []*nbdb.ACL) (map[string]int64, bool) {
+			aclHits := map[string]int64{}
+			for _, acl := range acls {
+				aclHits[acl.UUID] = hitsByRuleIdx[acl.ExternalIDs[libovsdbops.RuleIndex.String()]]
+			}
+			return aclHits, true
+		},
+	}
+
+	// the first collection is the baseline
+	oc.updateRuleHits()
+	require.Empty(t, appliedStatuses)
+
+	// rule 1 was recently hit in another zone, rule 2 is stale
+	hitsByRuleIdx = map[string]int64{"0": 6, "1": 20, "2": 3}
+	oc.updateRuleHits()
+	require.Len(t, appliedStatuses, 1)
+	require.Len(t, appliedStatuses[0].RuleHits, 1)
+	firstHitTime := appliedStatuses[0].RuleHits[0].LastHitTime
+	assert.Equal(t, egressfirewallapi.EgressFirewallStatus{
+		Messages: []string{types.GetZoneStatus(zone, EgressFirewallAppliedCorrectly)},
+		RuleHits: []egressfirewallapi.EgressFirewallRuleHits{
+			{Index: 0, LastHitTime: firstHitTime, Zone: zone},
+		},
+	}, appliedStatuses[0])
+
+	// no update when hits didn't change
+	ef = ef.DeepCopy()
+	ef.Status.RuleHits = append(ef.Status.RuleHits, appliedStatuses[0].RuleHits...)
+	require.NoError(t, efIndexer.Update(ef))
+	oc.updateRuleHits()
+	require.Len(t, appliedStatuses, 1)
+
+	// status message updates keep the reported hits
+	require.NoError(t, oc.setEgressFirewallStatus(ef, errors.New("test error")))
+	require.Len(t, appliedStatuses, 2)
+	assert.Equal(t, appliedStatuses[0].RuleHits, appliedStatuses[1].RuleHits)
+
+	// the last hit time is only moved when it is older than the resolution, the zone takes over the
+	// entry of the other zone
+	ef = ef.DeepCopy()
+	ef.Status.RuleHits[0].LastHitTime = metav1.NewTime(time.Now().Add(-time.Hour))
+	require.NoError(t, efIndexer.Update(ef))
+	hitsByRuleIdx = map[string]int64{"0": 7, "1": 21}
+	oc.updateRuleHits()
+	require.Len(t, appliedStatuses, 3)
+	require.Len(t, appliedStatuses[2].RuleHits, 2)
+	assert.Equal(t, appliedStatuses[0].RuleHits[0], appliedStatuses[2].RuleHits[0])
+	assert.EqualValues(t, 1, appliedStatuses[2].RuleHits[1].Index)
+	assert.Equal(t, zone, appliedStatuses[2].RuleHits[1].Zone)
+	assert.True(t, appliedStatuses[2].RuleHits[1].LastHitTime.After(ef.Status.RuleHits[0].LastHitTime.Time))
+}
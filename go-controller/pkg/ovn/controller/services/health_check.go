@@ -0,0 +1,371 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	libovsdbcache "github.com/ovn-kubernetes/libovsdb/cache"
+	"github.com/ovn-kubernetes/libovsdb/model"
+
+	globalconfig "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/metrics"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/sbdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+)
+
+// serviceHealthCheckEnabled returns true if the backends of the service get OVN load balancer health checks,
+// as requested by the service annotation or, if not set, by the global config.
+func serviceHealthCheckEnabled(service *corev1.Service) bool {
+	if enabled, ok := util.ParseServiceAnnotation(service, types.ServiceHealthCheckAnnotation, strconv.ParseBool); ok {
+		return enabled
+	}
+	return globalconfig.Kubernetes.OVNServiceHealthChecks
+}
+
+// ipPortMappingAddress formats an IP the way OVN expects it in load balancer ip_port_mappings.
+func ipPortMappingAddress(ip net.IP) string {
+	if ip.To4() == nil {
+		return "[" + ip.String() + "]"
+	}
+	return ip.String()
+}
+
+// buildHealthCheckIPPortMappings returns the ip_port_mappings of the pod backends of the service that run on
+// the given nodes, i.e. backend IP -> "logical_port:source_ip". The OVN service monitors send the health check
+// probes to the backend logical port, from the IP of the node's gateway router port on the node switch.
+// Backends on nodes of other zones can't be probed by the local ovn-controllers and are not part of the result.
+func buildHealthCheckIPPortMappings(endpointSlices []*discovery.EndpointSlice, nodeInfos []nodeInfo) map[string]string {
+	nodes := make(map[string]*nodeInfo, len(nodeInfos))
+	for i := range nodeInfos {
+		nodes[nodeInfos[i].name] = &nodeInfos[i]
+	}
+
+	mappings := map[string]string{}
+	for _, endpointSlice := range endpointSlices {
+		for _, endpoint := range endpointSlice.Endpoints {
+			if endpoint.TargetRef == nil || endpoint.TargetRef.Kind != "Pod" || endpoint.NodeName == nil {
+				continue
+			}
+			node, ok := nodes[*endpoint.NodeName]
+			if !ok {
+				continue
+			}
+			logicalPort := util.GetLogicalPortName(endpoint.TargetRef.Namespace, endpoint.TargetRef.Name)
+			for _, address := range endpoint.Addresses {
+				ip := net.ParseIP(address)
+				if ip == nil {
+					continue
+				}
+				// host-network pods are not in the pod subnets and don't have a logical port
+				for i := range node.podSubnets {
+					if !node.podSubnets[i].Contains(ip) {
+						continue
+					}
+					sourceIP := util.GetNodeGatewayIfAddr(&node.podSubnets[i]).IP
+					mappings[ipPortMappingAddress(ip)] = logicalPort + ":" + ipPortMappingAddress(sourceIP)
+					break
+				}
+			}
+		}
+	}
+	return mappings
+}
+
+// isHealthCheckedVIP returns true if the vip of the load balancer can get a health check:
+// OVN service monitors only support TCP and UDP, and health checks are only used for ClusterIPs.
+func isHealthCheckedVIP(lb *LB, vip Addr) bool {
+	protocol := corev1.Protocol(lb.Protocol)
+	return vip.Template == nil && util.IsClusterIP(vip.IP) &&
+		(protocol == corev1.ProtocolTCP || protocol == corev1.ProtocolUDP)
+}
+
+// setLBIPPortMappings sets the ip_port_mappings of the ClusterIP backends of the load balancers, from the
+// mappings of all the service backends.
+func setLBIPPortMappings(lbs []LB, mappings map[string]string) {
+	for i := range lbs {
+		lb := &lbs[i]
+		if lb.Opts.Template {
+			continue
+		}
+		lbMappings := map[string]string{}
+		for _, rule := range lb.Rules {
+			if !isHealthCheckedVIP(lb, rule.Source) {
+				continue
+			}
+			for _, target := range rule.Targets {
+				ip := net.ParseIP(target.IP)
+				if ip == nil {
+					continue
+				}
+				if mapping, ok := mappings[ipPortMappingAddress(ip)]; ok {
+					lbMappings[ipPortMappingAddress(ip)] = mapping
+				}
+			}
+		}
+		if len(lbMappings) > 0 {
+			lb.IPPortMappings = lbMappings
+		}
+	}
+}
+
+// buildHealthChecks returns the health checks of the ClusterIP vips of the load balancer that have backends
+// with ip_port_mappings. The health checks use the OVN default probe interval, timeout and counts.
+func buildHealthChecks(lb *LB) []*nbdb.LoadBalancerHealthCheck {
+	var hcs []*nbdb.LoadBalancerHealthCheck
+	for _, rule := range lb.Rules {
+		if !isHealthCheckedVIP(lb, rule.Source) {
+			continue
+		}
+		for _, target := range rule.Targets {
+			ip := net.ParseIP(target.IP)
+			if ip == nil {
+				continue
+			}
+			if _, ok := lb.IPPortMappings[ipPortMappingAddress(ip)]; ok {
+				hcs = append(hcs, &nbdb.LoadBalancerHealthCheck{
+					Vip:         rule.Source.String(),
+					ExternalIDs: lb.ExternalIDs,
+				})
+				break
+			}
+		}
+	}
+	return hcs
+}
+
+// healthCheckedBackend is a service backend probed by an OVN service monitor.
+type healthCheckedBackend struct {
+	ip       string
+	port     int
+	protocol string
+}
+
+// getHealthCheckedBackends returns the backends of the load balancers that have health checks.
+func getHealthCheckedBackends(lbs []LB) sets.Set[healthCheckedBackend] {
+	backends := sets.New[healthCheckedBackend]()
+	for i := range lbs {
+		lb := &lbs[i]
+		if len(lb.IPPortMappings) == 0 {
+			continue
+		}
+		for _, rule := range lb.Rules {
+			if !isHealthCheckedVIP(lb, rule.Source) {
+				continue
+			}
+			for _, target := range rule.Targets {
+				ip := net.ParseIP(target.IP)
+				if ip == nil {
+					continue
+				}
+				if _, ok := lb.IPPortMappings[ipPortMappingAddress(ip)]; ok {
+					backends.Insert(healthCheckedBackend{
+						ip:       ip.String(),
+						port:     int(target.Port),
+						protocol: strings.ToLower(lb.Protocol),
+					})
+				}
+			}
+		}
+	}
+	return backends
+}
+
+// healthCheckTracker keeps track of the services of the health checked backends,
+// to report the service monitor status changes on the services.
+type healthCheckTracker struct {
+	sync.Mutex
+	servicesByBackend map[healthCheckedBackend]sets.Set[string]
+	backendsByService map[string]sets.Set[healthCheckedBackend]
+}
+
+func newHealthCheckTracker() *healthCheckTracker {
+	return &healthCheckTracker{
+		servicesByBackend: map[healthCheckedBackend]sets.Set[string]{},
+		backendsByService: map[string]sets.Set[healthCheckedBackend]{},
+	}
+}
+
+// setServiceBackends sets the health checked backends of the service with the given key.
+func (t *healthCheckTracker) setServiceBackends(key string, backends sets.Set[healthCheckedBackend]) {
+	t.Lock()
+	defer t.Unlock()
+	for backend := range t.backendsByService[key].Difference(backends) {
+		t.servicesByBackend[backend].Delete(key)
+		if t.servicesByBackend[backend].Len() == 0 {
+			delete(t.servicesByBackend, backend)
+		}
+	}
+	for backend := range backends {
+		if t.servicesByBackend[backend] == nil {
+			t.servicesByBackend[backend] = sets.New[string]()
+		}
+		t.servicesByBackend[backend].Insert(key)
+	}
+	if backends.Len() == 0 {
+		delete(t.backendsByService, key)
+	} else {
+		t.backendsByService[key] = backends
+	}
+}
+
+// getBackendServices returns the keys of the services of the backend.
+func (t *healthCheckTracker) getBackendServices(backend healthCheckedBackend) []string {
+	t.Lock()
+	defer t.Unlock()
+	return sets.List(t.servicesByBackend[backend])
+}
+
+// serviceMonitorStatusChange is a status change of an OVN service monitor.
+type serviceMonitorStatusChange struct {
+	backend     healthCheckedBackend
+	logicalPort string
+	oldStatus   string
+	newStatus   string
+}
+
+func getServiceMonitorStatus(monitor *sbdb.ServiceMonitor) string {
+	if monitor == nil || monitor.Status == nil {
+		return ""
+	}
+	return *monitor.Status
+}
+
+func newServiceMonitorStatusChange(oldMonitor, newMonitor *sbdb.ServiceMonitor) serviceMonitorStatusChange {
+	protocol := sbdb.ServiceMonitorProtocolTCP
+	if newMonitor.Protocol != nil {
+		protocol = *newMonitor.Protocol
+	}
+	ip := newMonitor.IP
+	if parsedIP := net.ParseIP(ip); parsedIP != nil {
+		ip = parsedIP.String()
+	}
+	return serviceMonitorStatusChange{
+		backend: healthCheckedBackend{
+			ip:       ip,
+			port:     newMonitor.Port,
+			protocol: protocol,
+		},
+		logicalPort: newMonitor.LogicalPort,
+		oldStatus:   getServiceMonitorStatus(oldMonitor),
+		newStatus:   getServiceMonitorStatus(newMonitor),
+	}
+}
+
+// runServiceMonitorStatusReporter reports the OVN service monitor status changes of the health checked
+// backends as events on their services, and the number of service monitors by status as metrics.
+func (c *Controller) runServiceMonitorStatusReporter(stopCh <-chan struct{}) {
+	queue := workqueue.NewTyped[serviceMonitorStatusChange]()
+	c.sbClient.Cache().AddEventHandler(&libovsdbcache.EventHandlerFuncs{
+		AddFunc: func(table string, m model.Model) {
+			if table != sbdb.ServiceMonitorTable {
+				return
+			}
+			queue.Add(newServiceMonitorStatusChange(nil, m.(*sbdb.ServiceMonitor)))
+		},
+		UpdateFunc: func(table string, old, new model.Model) {
+			if table != sbdb.ServiceMonitorTable {
+				return
+			}
+			change := newServiceMonitorStatusChange(old.(*sbdb.ServiceMonitor), new.(*sbdb.ServiceMonitor))
+			if change.oldStatus != change.newStatus {
+				queue.Add(change)
+			}
+		},
+		DeleteFunc: func(table string, m model.Model) {
+			if table != sbdb.ServiceMonitorTable {
+				return
+			}
+			// only the metrics need to be updated
+			queue.Add(serviceMonitorStatusChange{})
+		},
+	})
+	c.updateServiceMonitorMetrics()
+
+	go func() {
+		<-stopCh
+		queue.ShutDown()
+	}()
+	go func() {
+		for {
+			change, shutdown := queue.Get()
+			if shutdown {
+				return
+			}
+			c.updateServiceMonitorMetrics()
+			c.reportServiceMonitorStatusChange(change)
+			queue.Done(change)
+		}
+	}()
+}
+
+// updateServiceMonitorMetrics sets the number of service monitors by status.
+func (c *Controller) updateServiceMonitorMetrics() {
+	var monitors []*sbdb.ServiceMonitor
+	ctx, cancel := context.WithTimeout(context.Background(), globalconfig.Default.OVSDBTxnTimeout)
+	defer cancel()
+	if err := c.sbClient.List(ctx, &monitors); err != nil {
+		klog.Errorf("Failed to list OVN service monitors: %v", err)
+		return
+	}
+	count := map[string]float64{
+		sbdb.ServiceMonitorStatusOnline:  0,
+		sbdb.ServiceMonitorStatusOffline: 0,
+		sbdb.ServiceMonitorStatusError:   0,
+	}
+	for _, monitor := range monitors {
+		if status := getServiceMonitorStatus(monitor); status != "" {
+			count[status]++
+		}
+	}
+	for status, n := range count {
+		metrics.MetricServiceMonitors.WithLabelValues(status).Set(n)
+	}
+}
+
+// reportServiceMonitorStatusChange records an event on the services of the backend when it becomes unhealthy,
+// or healthy again.
+func (c *Controller) reportServiceMonitorStatusChange(change serviceMonitorStatusChange) {
+	var eventType, reason, message string
+	switch {
+	case change.newStatus == sbdb.ServiceMonitorStatusOffline || change.newStatus == sbdb.ServiceMonitorStatusError:
+		eventType = corev1.EventTypeWarning
+		reason = "BackendUnhealthy"
+		message = "failed the OVN load balancer health check, status " + change.newStatus
+	case change.newStatus == sbdb.ServiceMonitorStatusOnline &&
+		(change.oldStatus == sbdb.ServiceMonitorStatusOffline || change.oldStatus == sbdb.ServiceMonitorStatusError):
+		eventType = corev1.EventTypeNormal
+		reason = "BackendHealthy"
+		message = "passed the OVN load balancer health check again"
+	default:
+		return
+	}
+	backend := util.JoinHostPortInt32(change.backend.ip, int32(change.backend.port))
+	for _, key := range c.healthChecks.getBackendServices(change.backend) {
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			continue
+		}
+		service, err := c.serviceLister.Services(namespace).Get(name)
+		if err != nil {
+			continue
+		}
+		klog.V(4).Infof("Backend %s/%s (%s) of service %s %s", change.backend.protocol, backend, change.logicalPort, key, message)
+		c.eventRecorder.Eventf(service, eventType, reason, "Backend %s/%s (%s) %s",
+			change.backend.protocol, backend, change.logicalPort, message)
+	}
+}
@@ -0,0 +1,226 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package services
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	globalconfig "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/sbdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
+)
+
+func Test_serviceHealthCheckEnabled(t *testing.T) {
+	oldHealthChecks := globalconfig.Kubernetes.OVNServiceHealthChecks
+	defer func() {
+		globalconfig.Kubernetes.OVNServiceHealthChecks = oldHealthChecks
+	}()
+
+	tests := []struct {
+		name       string
+		annotation *string
+		global     bool
+		expected   bool
+	}{
+		{name: "disabled by default"},
+		{name: "enabled globally", global: true, expected: true},
+		{name: "enabled by annotation", annotation: ptr.To("true"), expected: true},
+		{name: "disabled by annotation", annotation: ptr.To("false"), global: true},
+		{name: "invalid annotation", annotation: ptr.To("yes please"), global: true, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			globalconfig.Kubernetes.OVNServiceHealthChecks = tt.global
+			service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+			if tt.annotation != nil {
+				service.Annotations = map[string]string{types.ServiceHealthCheckAnnotation: *tt.annotation}
+			}
+			assert.Equal(t, tt.expected, serviceHealthCheckEnabled(service))
+		})
+	}
+}
+
+func Test_buildHealthCheckIPPortMappings(t *testing.T) {
+	_, podSubnetA4, _ := net.ParseCIDR("10.128.0.0/24")
+	_, podSubnetA6, _ := net.ParseCIDR("fe00:0:0:0:1::/80")
+	nodeInfos := []nodeInfo{
+		{
+			name:       nodeA,
+			podSubnets: []net.IPNet{*podSubnetA4, *podSubnetA6},
+		},
+	}
+	podEndpoint := func(pod, node string, addresses ...string) discovery.Endpoint {
+		return discovery.Endpoint{
+			Addresses: addresses,
+			NodeName:  ptr.To(node),
+			TargetRef: &corev1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: pod},
+		}
+	}
+	endpointSlices := []*discovery.EndpointSlice{
+		{
+			AddressType: discovery.AddressTypeIPv4,
+			Endpoints: []discovery.Endpoint{
+				podEndpoint("pod1", nodeA, "10.128.0.3"),
+				// pod in another zone
+				podEndpoint("pod2", nodeB, "10.128.1.3"),
+				// host-network pod
+				podEndpoint("pod3", nodeA, "192.168.0.10"),
+				// not a pod
+				{Addresses: []string{"10.128.0.4"}, NodeName: ptr.To(nodeA)},
+			},
+		},
+		{
+			AddressType: discovery.AddressTypeIPv6,
+			Endpoints: []discovery.Endpoint{
+				podEndpoint("pod1", nodeA, "fe00::1:0:0:3"),
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"10.128.0.3":      "testns_pod1:10.128.0.1",
+		"[fe00::1:0:0:3]": "testns_pod1:[fe00::1:0:0:1]",
+	}, buildHealthCheckIPPortMappings(endpointSlices, nodeInfos))
+}
+
+func Test_setLBIPPortMappings(t *testing.T) {
+	oldServiceCIDRs := globalconfig.Kubernetes.ServiceCIDRs
+	defer func() {
+		globalconfig.Kubernetes.ServiceCIDRs = oldServiceCIDRs
+	}()
+	_, svcCIDRv4, _ := net.ParseCIDR("192.168.0.0/16")
+	globalconfig.Kubernetes.ServiceCIDRs = []*net.IPNet{svcCIDRv4}
+
+	mappings := map[string]string{
+		"10.128.0.3": "testns_pod1:10.128.0.1",
+	}
+	lbs := []LB{
+		{
+			Name:     "clusterip",
+			Protocol: "TCP",
+			Rules: []LBRule{
+				{
+					Source:  Addr{IP: "192.168.1.1", Port: 80},
+					Targets: []Addr{{IP: "10.128.0.3", Port: 8080}, {IP: "10.128.1.3", Port: 8080}},
+				},
+			},
+		},
+		{
+			Name:     "nodeport",
+			Protocol: "TCP",
+			Rules: []LBRule{
+				{
+					Source:  Addr{IP: "10.0.0.1", Port: 30080},
+					Targets: []Addr{{IP: "10.128.0.3", Port: 8080}},
+				},
+			},
+		},
+		{
+			Name:     "sctp",
+			Protocol: "SCTP",
+			Rules: []LBRule{
+				{
+					Source:  Addr{IP: "192.168.1.1", Port: 80},
+					Targets: []Addr{{IP: "10.128.0.3", Port: 8080}},
+				},
+			},
+		},
+	}
+	setLBIPPortMappings(lbs, mappings)
+
+	assert.Equal(t, mappings, lbs[0].IPPortMappings)
+	assert.Nil(t, lbs[1].IPPortMappings)
+	assert.Nil(t, lbs[2].IPPortMappings)
+
+	hcs := buildHealthChecks(&lbs[0])
+	if assert.Len(t, hcs, 1) {
+		assert.Equal(t, "192.168.1.1:80", hcs[0].Vip)
+	}
+	assert.Equal(t, sets.New(healthCheckedBackend{ip: "10.128.0.3", port: 8080, protocol: "tcp"}),
+		getHealthCheckedBackends(lbs))
+}
+
+func Test_reportServiceMonitorStatusChange(t *testing.T) {
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(service); err != nil {
+		t.Fatalf("Error adding service: %v", err)
+	}
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{
+		serviceLister: corelisters.NewServiceLister(indexer),
+		eventRecorder: recorder,
+		healthChecks:  newHealthCheckTracker(),
+	}
+	backend := healthCheckedBackend{ip: "10.128.0.3", port: 8080, protocol: "tcp"}
+	c.healthChecks.setServiceBackends(namespace+"/"+name, sets.New(backend))
+
+	change := newServiceMonitorStatusChange
+	monitor := func(status string) *sbdb.ServiceMonitor {
+		return &sbdb.ServiceMonitor{
+			IP:          "10.128.0.3",
+			Port:        8080,
+			Protocol:    ptr.To(sbdb.ServiceMonitorProtocolTCP),
+			LogicalPort: "testns_pod1",
+			Status:      ptr.To(status),
+		}
+	}
+
+	tests := []struct {
+		name     string
+		change   serviceMonitorStatusChange
+		expected string
+	}{
+		{
+			name:   "backend online",
+			change: change(nil, monitor(sbdb.ServiceMonitorStatusOnline)),
+		},
+		{
+			name:     "backend offline",
+			change:   change(monitor(sbdb.ServiceMonitorStatusOnline), monitor(sbdb.ServiceMonitorStatusOffline)),
+			expected: "Warning BackendUnhealthy Backend tcp/10.128.0.3:8080 (testns_pod1) failed the OVN load balancer health check, status offline",
+		},
+		{
+			name:     "backend back online",
+			change:   change(monitor(sbdb.ServiceMonitorStatusOffline), monitor(sbdb.ServiceMonitorStatusOnline)),
+			expected: "Normal BackendHealthy Backend tcp/10.128.0.3:8080 (testns_pod1) passed the OVN load balancer health check again",
+		},
+		{
+			name: "backend of another service",
+			change: change(nil, &sbdb.ServiceMonitor{
+				IP:     "10.128.0.4",
+				Port:   8080,
+				Status: ptr.To(sbdb.ServiceMonitorStatusError),
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.reportServiceMonitorStatusChange(tt.change)
+			select {
+			case event := <-recorder.Events:
+				assert.Equal(t, tt.expected, event)
+			default:
+				assert.Empty(t, tt.expected, "expected an event")
+			}
+		})
+	}
+
+	// no more events once the service no longer has health checks
+	c.healthChecks.setServiceBackends(namespace+"/"+name, nil)
+	c.reportServiceMonitorStatusChange(change(nil, monitor(sbdb.ServiceMonitorStatusOffline)))
+	assert.Empty(t, recorder.Events)
+}
@@ -16,6 +16,7 @@ import (
 	utilnet "k8s.io/utils/net"
 
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/ovn/controller/unidling"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
@@ -34,6 +35,7 @@ type lbConfig struct {
 
 	clusterEndpoints util.LBEndpoints            // addresses of cluster-wide endpoints
 	nodeEndpoints    map[string]util.LBEndpoints // node -> addresses of local endpoints
+	zoneEndpoints    map[string]util.LBEndpoints // topology zone -> addresses of endpoints hinted for the zone
 
 	// if true, then vips added on the router are in "local" mode
 	// that means, skipSNAT, and remove any non-local endpoints.
@@ -46,9 +48,18 @@ type lbConfig struct {
 	hasNodePort bool
 }
 
-func makeNodeSwitchTargetIPs(node string, c *lbConfig) (targetIPsV4, targetIPsV6 []string, v4Changed, v6Changed bool) {
-	targetIPsV4 = c.clusterEndpoints.V4IPs
-	targetIPsV6 = c.clusterEndpoints.V6IPs
+// makeNodeClusterTargetIPs returns the targets of the node for traffic policy Cluster: the endpoints hinted
+// for the node's topology zone if there are any, otherwise all the cluster endpoints.
+func makeNodeClusterTargetIPs(node *nodeInfo, c *lbConfig) (targetIPsV4, targetIPsV6 []string) {
+	if zoneEndpoints, ok := c.zoneEndpoints[node.topologyZone]; ok && node.topologyZone != "" &&
+		len(zoneEndpoints.V4IPs)+len(zoneEndpoints.V6IPs) > 0 {
+		return zoneEndpoints.V4IPs, zoneEndpoints.V6IPs
+	}
+	return c.clusterEndpoints.V4IPs, c.clusterEndpoints.V6IPs
+}
+
+func makeNodeSwitchTargetIPs(node *nodeInfo, c *lbConfig) (targetIPsV4, targetIPsV6 []string, v4Changed, v6Changed bool) {
+	targetIPsV4, targetIPsV6 = makeNodeClusterTargetIPs(node, c)
 
 	if c.externalTrafficLocal || c.internalTrafficLocal {
 		// For ExternalTrafficPolicy=Local, remove non-local endpoints from the router/switch targets
@@ -56,7 +67,7 @@ func makeNodeSwitchTargetIPs(node string, c *lbConfig) (targetIPsV4, targetIPsV6
 		// for InternalTrafficPolicy=Local, remove non-local endpoints from the switch targets only
 		localIPsV4 := []string{}
 		localIPsV6 := []string{}
-		if localEndpoints, ok := c.nodeEndpoints[node]; ok {
+		if localEndpoints, ok := c.nodeEndpoints[node.name]; ok {
 			localIPsV4 = localEndpoints.V4IPs
 			localIPsV6 = localEndpoints.V6IPs
 		}
@@ -64,7 +75,7 @@ func makeNodeSwitchTargetIPs(node string, c *lbConfig) (targetIPsV4, targetIPsV6
 		targetIPsV6 = localIPsV6
 	}
 
-	// Local endpoints are a subset of cluster endpoints, so it is enough to compare their length
+	// Local and zone endpoints are a subset of cluster endpoints, so it is enough to compare their length
 	v4Changed = len(targetIPsV4) != len(c.clusterEndpoints.V4IPs)
 	v6Changed = len(targetIPsV6) != len(c.clusterEndpoints.V6IPs)
 
@@ -72,8 +83,7 @@ func makeNodeSwitchTargetIPs(node string, c *lbConfig) (targetIPsV4, targetIPsV6
 }
 
 func makeNodeRouterTargetIPs(node *nodeInfo, c *lbConfig, hostMasqueradeIPV4, hostMasqueradeIPV6 string) (targetIPsV4, targetIPsV6 []string, v4Changed, v6Changed bool) {
-	targetIPsV4 = c.clusterEndpoints.V4IPs
-	targetIPsV6 = c.clusterEndpoints.V6IPs
+	targetIPsV4, targetIPsV6 = makeNodeClusterTargetIPs(node, c)
 
 	if c.externalTrafficLocal {
 		// For ExternalTrafficPolicy=Local, remove non-local endpoints from the router/switch targets
@@ -99,7 +109,7 @@ func makeNodeRouterTargetIPs(node *nodeInfo, c *lbConfig, hostMasqueradeIPV4, ho
 	targetIPsV4, v4Updated := util.UpdateIPsSlice(targetIPsV4, lbAddresses, []string{hostMasqueradeIPV4})
 	targetIPsV6, v6Updated := util.UpdateIPsSlice(targetIPsV6, lbAddresses, []string{hostMasqueradeIPV6})
 
-	// Local endpoints are a subset of cluster endpoints, so it is enough to compare their length
+	// Local and zone endpoints are a subset of cluster endpoints, so it is enough to compare their length
 	v4Changed = len(targetIPsV4) != len(c.clusterEndpoints.V4IPs) || v4Updated
 	v6Changed = len(targetIPsV6) != len(c.clusterEndpoints.V6IPs) || v6Updated
 
@@ -129,6 +139,7 @@ var protos = []corev1.Protocol{
 // - services with host-network endpoints
 // - services with ExternalTrafficPolicy=Local
 // - services with InternalTrafficPolicy=Local
+// - services with topology aware routing, i.e. EndpointSlices with zone hints
 //
 // Template LBs will be created for
 //   - services with NodePort set but *without* ExternalTrafficPolicy=Local or
@@ -152,6 +163,8 @@ func buildServiceLBConfigs(service *corev1.Service, endpointSlices []*discovery.
 			klog.Warningf("Failed to get endpoints for service during LB config build: %v", err)
 		}
 	}
+	// get the endpoints of each topology zone if the service uses topology aware routing
+	portToZoneToEndpoints := util.GetZoneEndpointsForService(endpointSlices, service)
 	for _, svcPort := range service.Spec.Ports {
 		svcPortKey := util.GetServicePortKey(svcPort.Protocol, svcPort.Name)
 		clusterEndpoints := portToClusterEndpoints[svcPortKey]
@@ -159,6 +172,7 @@ func buildServiceLBConfigs(service *corev1.Service, endpointSlices []*discovery.
 		if nodeEndpoints == nil {
 			nodeEndpoints = make(map[string]util.LBEndpoints)
 		}
+		zoneEndpoints := portToZoneToEndpoints[svcPortKey]
 		// if ExternalTrafficPolicy or InternalTrafficPolicy is local, then we need to do things a bit differently
 		externalTrafficLocal := util.ServiceExternalTrafficPolicyLocal(service)
 		internalTrafficLocal := util.ServiceInternalTrafficPolicyLocal(service)
@@ -173,6 +187,7 @@ func buildServiceLBConfigs(service *corev1.Service, endpointSlices []*discovery.
 				vips:                 []string{placeholderNodeIPs}, // shortcut for all-physical-ips
 				clusterEndpoints:     clusterEndpoints,
 				nodeEndpoints:        nodeEndpoints,
+				zoneEndpoints:        zoneEndpoints,
 				externalTrafficLocal: externalTrafficLocal,
 				internalTrafficLocal: false, // always false for non-ClusterIPs
 				hasNodePort:          true,
@@ -217,6 +232,7 @@ func buildServiceLBConfigs(service *corev1.Service, endpointSlices []*discovery.
 			vips:                 vips,
 			clusterEndpoints:     clusterEndpoints,
 			nodeEndpoints:        nodeEndpoints,
+			zoneEndpoints:        zoneEndpoints,
 			externalTrafficLocal: false, // always false for ClusterIPs
 			internalTrafficLocal: internalTrafficLocal,
 			hasNodePort:          false,
@@ -226,9 +242,11 @@ func buildServiceLBConfigs(service *corev1.Service, endpointSlices []*discovery.
 		// unless any of the following are true:
 		// - Any of the endpoints are host-network
 		// - ETP=local service backed by non-local-host-networked endpoints
+		// - the endpoints have zone hints for topology aware routing
 		//
 		// In that case, we need to create per-node LBs.
-		if hasHostEndpoints(clusterEndpoints.V4IPs, netInfo) || hasHostEndpoints(clusterEndpoints.V6IPs, netInfo) || internalTrafficLocal {
+		if hasHostEndpoints(clusterEndpoints.V4IPs, netInfo) || hasHostEndpoints(clusterEndpoints.V6IPs, netInfo) || internalTrafficLocal ||
+			len(zoneEndpoints) > 0 {
 			perNodeConfigs = append(perNodeConfigs, clusterIPConfig)
 		} else {
 			clusterConfigs = append(clusterConfigs, clusterIPConfig)
@@ -423,7 +441,7 @@ func buildTemplateLBs(service *corev1.Service, configs []lbConfig, nodes []nodeI
 
 				for _, node := range nodes {
 
-					switchV4TargetIPs, switchV6TargetIPs, v4Changed, v6Changed := makeNodeSwitchTargetIPs(node.name, &cfg)
+					switchV4TargetIPs, switchV6TargetIPs, v4Changed, v6Changed := makeNodeSwitchTargetIPs(&node, &cfg)
 					if !switchV4TargetNeedsTemplate && v4Changed {
 						switchV4TargetNeedsTemplate = true
 					}
@@ -622,7 +640,7 @@ func buildPerNodeLBs(service *corev1.Service, configs []lbConfig, nodes []nodeIn
 
 			for _, cfg := range configs {
 
-				switchV4TargetIPs, switchV6TargetIPs, _, _ := makeNodeSwitchTargetIPs(node.name, &cfg)
+				switchV4TargetIPs, switchV6TargetIPs, _, _ := makeNodeSwitchTargetIPs(&node, &cfg)
 
 				routerV4TargetIPs, routerV6TargetIPs, _, _ := makeNodeRouterTargetIPs(
 					&node,
@@ -633,8 +651,10 @@ func buildPerNodeLBs(service *corev1.Service, configs []lbConfig, nodes []nodeIn
 				routerV4targets := joinHostsPort(routerV4TargetIPs, cfg.clusterEndpoints.Port)
 				routerV6targets := joinHostsPort(routerV6TargetIPs, cfg.clusterEndpoints.Port)
 
-				switchV4targets := joinHostsPort(cfg.clusterEndpoints.V4IPs, cfg.clusterEndpoints.Port)
-				switchV6targets := joinHostsPort(cfg.clusterEndpoints.V6IPs, cfg.clusterEndpoints.Port)
+				// with traffic policy Cluster, the switch targets prefer the endpoints of the node's topology zone
+				clusterV4TargetIPs, clusterV6TargetIPs := makeNodeClusterTargetIPs(&node, &cfg)
+				switchV4targets := joinHostsPort(clusterV4TargetIPs, cfg.clusterEndpoints.Port)
+				switchV6targets := joinHostsPort(clusterV6TargetIPs, cfg.clusterEndpoints.Port)
 
 				// Substitute the special vip "node" for the node's physical ips
 				// This is used for nodeport
@@ -790,7 +810,22 @@ func getSessionAffinityTimeOut(service *corev1.Service) int32 {
 
 func hasSessionAffinityTimeOut(service *corev1.Service) bool {
 	return service.Spec.SessionAffinity == corev1.ServiceAffinityClientIP &&
-		getSessionAffinityTimeOut(service) != core.MaxClientIPServiceAffinitySeconds
+		getSessionAffinityTimeOut(service) != core.MaxClientIPServiceAffinitySeconds &&
+		!hasClientHashSessionAffinity(service)
+}
+
+// hasClientHashSessionAffinity returns true if the ClientIP session affinity of the service hashes the client IP
+// instead of tracking the clients for the affinity timeout. The affinity of the OVN affinity_timeout is learnt by each
+// datapath, so a client that reaches the service through several nodes gets one backend per node; hashing the client
+// IP gives the client the same backend everywhere as long as the nodes have the same backends. OVN can only hash
+// whole client IPs, there is no affinity by client subnet.
+func hasClientHashSessionAffinity(service *corev1.Service) bool {
+	if service.Spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		return false
+	}
+	_, ok := util.ParseServiceAnnotation(service, types.ServiceSessionAffinityModeAnnotation,
+		util.OneOf(types.ServiceSessionAffinityModeClientHash))
+	return ok
 }
 
 // lbOpts generates the OVN load balancer options from the kubernetes Service.
@@ -821,12 +856,75 @@ func lbOpts(service *corev1.Service) LBOpts {
 		}
 	}
 
-	if affinity {
+	lbOptions.HairpinSNATVIP = hasHairpinSNATVIP(service)
+
+	if affinity && hasClientHashSessionAffinity(service) {
+		lbOptions.SelectionFields = []nbdb.LoadBalancerSelectionFields{nbdb.LoadBalancerSelectionFieldsIPSrc}
+	} else if affinity {
 		lbOptions.AffinityTimeOut = getSessionAffinityTimeOut(service)
+	} else {
+		// session affinity takes precedence over the requested backend selection
+		lbOptions.SelectionFields = getSelectionFields(service)
 	}
 	return lbOptions
 }
 
+// hasHairpinSNATVIP returns true if the hairpin traffic of the service is SNATed to the service vip instead of the
+// OVN service hairpin masquerade IP.
+func hasHairpinSNATVIP(service *corev1.Service) bool {
+	value, ok := util.ParseServiceAnnotation(service, types.ServiceHairpinSNATAnnotation,
+		util.OneOf(types.ServiceHairpinSNATVIP, types.ServiceHairpinSNATMasquerade))
+	return ok && value == types.ServiceHairpinSNATVIP
+}
+
+// lbSelectionFields are the load balancer selection fields that can be set with the
+// ServiceLBSelectionFieldsAnnotation, in the order they are set in the load balancer.
+var lbSelectionFields = []nbdb.LoadBalancerSelectionFields{
+	nbdb.LoadBalancerSelectionFieldsIPSrc,
+	nbdb.LoadBalancerSelectionFieldsIPDst,
+	nbdb.LoadBalancerSelectionFieldsTpSrc,
+	nbdb.LoadBalancerSelectionFieldsTpDst,
+}
+
+// parseSelectionFields parses the comma separated list of load balancer selection fields of the
+// ServiceLBSelectionFieldsAnnotation.
+func parseSelectionFields(value string) (sets.Set[string], error) {
+	requested := sets.New[string]()
+	for _, field := range strings.Split(value, ",") {
+		requested.Insert(strings.TrimSpace(field))
+	}
+	if invalid := requested.Difference(sets.New(lbSelectionFields...)); invalid.Len() > 0 {
+		return nil, fmt.Errorf("invalid selection fields %v", sets.List(invalid))
+	}
+	return requested, nil
+}
+
+// getSelectionFields returns the selection fields of the service load balancers requested by the service
+// annotations, or nil to use the OVN default backend selection. When selection fields are set, OVS selects the
+// backend with rendezvous hashing of these fields, which only moves the connections of the added or removed
+// backends; consistent hashing without explicit selection fields hashes the 5-tuple.
+func getSelectionFields(service *corev1.Service) []nbdb.LoadBalancerSelectionFields {
+	_, consistent := util.ParseServiceAnnotation(service, types.ServiceLBHashingAnnotation,
+		util.OneOf(types.ServiceLBHashingConsistent))
+
+	requested, _ := util.ParseServiceAnnotation(service, types.ServiceLBSelectionFieldsAnnotation, parseSelectionFields)
+	if requested.Len() == 0 && consistent {
+		requested = sets.New[string]()
+		requested.Insert(lbSelectionFields...)
+	}
+	if requested.Len() == 0 {
+		return nil
+	}
+
+	fields := make([]nbdb.LoadBalancerSelectionFields, 0, requested.Len())
+	for _, field := range lbSelectionFields {
+		if requested.Has(field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
 func lbTemplateOpts(service *corev1.Service, addressFamily corev1.IPFamily) LBOpts {
 	lbOptions := lbOpts(service)
 
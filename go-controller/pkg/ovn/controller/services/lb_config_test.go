@@ -21,6 +21,7 @@ import (
 	"k8s.io/utils/ptr"
 
 	globalconfig "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
 	kubetest "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/testing"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
@@ -209,6 +210,11 @@ func Test_buildServiceLBConfigs(t *testing.T) {
 		return []*discovery.EndpointSlice{e}
 	}
 
+	withZoneHint := func(endpoint discovery.Endpoint, zone string) discovery.Endpoint {
+		endpoint.Hints = &discovery.EndpointHints{ForZones: []discovery.ForZone{{Name: zone}}}
+		return endpoint
+	}
+
 	type args struct {
 		service *corev1.Service
 		slices  []*discovery.EndpointSlice
@@ -539,6 +545,47 @@ func Test_buildServiceLBConfigs(t *testing.T) {
 				nodeEndpoints: util.PortToLBEndpoints{}, // ETP=cluster (default), so nodeEndpoints is not filled out
 			}},
 		},
+		{
+			name: "dual-stack clusterip, one port, endpoints, external ips + lb status in Proxy mode",
+			args: args{
+				slices: makeSlices([]string{"10.128.0.2"}, []string{"fe00::1:1"}, corev1.ProtocolTCP),
+				service: &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns},
+					Spec: corev1.ServiceSpec{
+						Type:       corev1.ServiceTypeLoadBalancer,
+						ClusterIP:  "192.168.1.1",
+						ClusterIPs: []string{"192.168.1.1", "2002::1"},
+						Ports: []corev1.ServicePort{{
+							Name:       portName,
+							Port:       inport,
+							Protocol:   corev1.ProtocolTCP,
+							TargetPort: outportstr,
+						}},
+						ExternalIPs: []string{"4.2.2.2", "42::42"},
+					},
+					Status: corev1.ServiceStatus{
+						LoadBalancer: corev1.LoadBalancerStatus{
+							Ingress: []corev1.LoadBalancerIngress{{
+								IP:     "5.5.5.5",
+								IPMode: ptr.To(corev1.LoadBalancerIPModeProxy),
+							}},
+						},
+					},
+				},
+			},
+			resultsSame: true,
+			resultSharedGatewayCluster: []lbConfig{{
+				vips:     []string{"192.168.1.1", "2002::1", "4.2.2.2", "42::42"}, // no VIP for the Proxy mode ingress IP
+				protocol: corev1.ProtocolTCP,
+				inport:   inport,
+				clusterEndpoints: util.LBEndpoints{
+					V4IPs: []string{"10.128.0.2"},
+					V6IPs: []string{"fe00::1:1"},
+					Port:  outport,
+				},
+				nodeEndpoints: util.PortToLBEndpoints{},
+			}},
+		},
 		{
 			name: "dual-stack clusterip, one port, endpoints, external ips + lb status, ExternalTrafficPolicy=local",
 			args: args{
@@ -1167,6 +1214,101 @@ func Test_buildServiceLBConfigs(t *testing.T) {
 				},
 			},
 		},
+		{
+			// ClusterIP gets per-node LBs so that each node can prefer the endpoints hinted for its zone
+			name: "v4 NodePort service, one port, two endpoints with zone hints",
+			args: args{
+				slices: makeV4SliceWithEndpoints(corev1.ProtocolTCP,
+					withZoneHint(kubetest.MakeReadyEndpoint(nodeA, "10.128.0.2"), "zone-a"),
+					withZoneHint(kubetest.MakeReadyEndpoint(nodeB, "10.128.1.2"), "zone-b")),
+				service: &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns},
+					Spec: corev1.ServiceSpec{
+						Type:                corev1.ServiceTypeNodePort,
+						ClusterIP:           "192.168.1.1",
+						ClusterIPs:          []string{"192.168.1.1"},
+						TrafficDistribution: ptr.To(corev1.ServiceTrafficDistributionPreferClose),
+						Ports: []corev1.ServicePort{{
+							Name:       portName,
+							Port:       inport,
+							Protocol:   corev1.ProtocolTCP,
+							TargetPort: outportstr,
+							NodePort:   5,
+						}},
+					},
+				},
+			},
+			resultsSame: true,
+			resultSharedGatewayTemplate: []lbConfig{
+				{
+					vips:        []string{"node"},
+					protocol:    corev1.ProtocolTCP,
+					inport:      5,
+					hasNodePort: true,
+					clusterEndpoints: util.LBEndpoints{
+						V4IPs: []string{"10.128.0.2", "10.128.1.2"},
+						Port:  outport,
+					},
+					nodeEndpoints: util.PortToLBEndpoints{},
+					zoneEndpoints: map[string]util.LBEndpoints{
+						"zone-a": {V4IPs: []string{"10.128.0.2"}, Port: outport},
+						"zone-b": {V4IPs: []string{"10.128.1.2"}, Port: outport},
+					},
+				},
+			},
+			resultSharedGatewayNode: []lbConfig{
+				{
+					vips:     []string{"192.168.1.1"},
+					protocol: corev1.ProtocolTCP,
+					inport:   inport,
+					clusterEndpoints: util.LBEndpoints{
+						V4IPs: []string{"10.128.0.2", "10.128.1.2"},
+						Port:  outport,
+					},
+					nodeEndpoints: util.PortToLBEndpoints{},
+					zoneEndpoints: map[string]util.LBEndpoints{
+						"zone-a": {V4IPs: []string{"10.128.0.2"}, Port: outport},
+						"zone-b": {V4IPs: []string{"10.128.1.2"}, Port: outport},
+					},
+				},
+			},
+		},
+		{
+			// zone hints are ignored unless all endpoints have them
+			name: "v4 clusterip, one port, two endpoints, only one with a zone hint",
+			args: args{
+				slices: makeV4SliceWithEndpoints(corev1.ProtocolTCP,
+					withZoneHint(kubetest.MakeReadyEndpoint(nodeA, "10.128.0.2"), "zone-a"),
+					kubetest.MakeReadyEndpoint(nodeB, "10.128.1.2")),
+				service: &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns},
+					Spec: corev1.ServiceSpec{
+						Type:       corev1.ServiceTypeClusterIP,
+						ClusterIP:  "192.168.1.1",
+						ClusterIPs: []string{"192.168.1.1"},
+						Ports: []corev1.ServicePort{{
+							Name:       portName,
+							Port:       inport,
+							Protocol:   corev1.ProtocolTCP,
+							TargetPort: outportstr,
+						}},
+					},
+				},
+			},
+			resultsSame: true,
+			resultSharedGatewayCluster: []lbConfig{
+				{
+					vips:     []string{"192.168.1.1"},
+					protocol: corev1.ProtocolTCP,
+					inport:   inport,
+					clusterEndpoints: util.LBEndpoints{
+						V4IPs: []string{"10.128.0.2", "10.128.1.2"},
+						Port:  outport,
+					},
+					nodeEndpoints: util.PortToLBEndpoints{},
+				},
+			},
+		},
 	}
 
 	for i, tt := range tests {
@@ -1506,6 +1648,7 @@ func Test_buildPerNodeLBs(t *testing.T) {
 			hostAddresses:      []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.111")},
 			gatewayRouterName:  "gr-node-a",
 			switchName:         "switch-node-a",
+			topologyZone:       "zone-a",
 			podSubnets:         []net.IPNet{{IP: net.ParseIP("10.128.0.0"), Mask: net.CIDRMask(24, 32)}},
 		},
 		{
@@ -1514,6 +1657,7 @@ func Test_buildPerNodeLBs(t *testing.T) {
 			hostAddresses:      []net.IP{net.ParseIP("10.0.0.2")},
 			gatewayRouterName:  "gr-node-b",
 			switchName:         "switch-node-b",
+			topologyZone:       "zone-b",
 			podSubnets:         []net.IPNet{{IP: net.ParseIP("10.128.1.0"), Mask: net.CIDRMask(24, 32)}},
 		},
 	}
@@ -1599,6 +1743,86 @@ func Test_buildPerNodeLBs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:    "clusterip service with zone hints",
+			service: defaultService,
+			configs: []lbConfig{
+				{
+					vips:     []string{"1.2.3.4"},
+					protocol: corev1.ProtocolTCP,
+					inport:   80,
+					clusterEndpoints: util.LBEndpoints{
+						V4IPs: []string{"10.128.0.2", "10.128.1.2"},
+						Port:  8080,
+					},
+					nodeEndpoints: util.PortToLBEndpoints{},
+					zoneEndpoints: map[string]util.LBEndpoints{
+						"zone-a": {V4IPs: []string{"10.128.0.2"}, Port: 8080},
+						"zone-b": {V4IPs: []string{"10.128.1.2"}, Port: 8080},
+					},
+				},
+			},
+			expectedShared: []LB{
+				{
+					Name:        "Service_testns/foo_TCP_node_router+switch_node-a",
+					ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+					Routers:     []string{"gr-node-a"},
+					Switches:    []string{"switch-node-a"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "1.2.3.4", Port: 80},
+							Targets: []Addr{{IP: "10.128.0.2", Port: 8080}},
+						},
+					},
+					Opts: defaultOpts,
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_router+switch_node-b",
+					ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+					Routers:     []string{"gr-node-b"},
+					Switches:    []string{"switch-node-b"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "1.2.3.4", Port: 80},
+							Targets: []Addr{{IP: "10.128.1.2", Port: 8080}},
+						},
+					},
+					Opts: defaultOpts,
+				},
+			},
+			expectedLocal: []LB{
+				{
+					Name:        "Service_testns/foo_TCP_node_router+switch_node-a",
+					ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+					Routers:     []string{"gr-node-a"},
+					Switches:    []string{"switch-node-a"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "1.2.3.4", Port: 80},
+							Targets: []Addr{{IP: "10.128.0.2", Port: 8080}},
+						},
+					},
+					Opts: defaultOpts,
+				},
+				{
+					Name:        "Service_testns/foo_TCP_node_router+switch_node-b",
+					ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+					Routers:     []string{"gr-node-b"},
+					Switches:    []string{"switch-node-b"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "1.2.3.4", Port: 80},
+							Targets: []Addr{{IP: "10.128.1.2", Port: 8080}},
+						},
+					},
+					Opts: defaultOpts,
+				},
+			},
+		},
 		{
 			name:    "nodeport service, standard pod",
 			service: defaultService,
@@ -3277,6 +3501,152 @@ func Test_idledServices(t *testing.T) {
 	}
 }
 
+func Test_lbSelectionFields(t *testing.T) {
+	serviceName := "foo"
+	ns := "testns"
+
+	tc := []struct {
+		name        string
+		annotations map[string]string
+		affinity    bool
+		expected    []nbdb.LoadBalancerSelectionFields
+	}{
+		{
+			name: "default selection",
+		},
+		{
+			name:        "source IP selection",
+			annotations: map[string]string{types.ServiceLBSelectionFieldsAnnotation: "ip_src"},
+			expected:    []string{"ip_src"},
+		},
+		{
+			name:        "selection fields are deduplicated and ordered",
+			annotations: map[string]string{types.ServiceLBSelectionFieldsAnnotation: "tp_dst, ip_src,tp_dst"},
+			expected:    []string{"ip_src", "tp_dst"},
+		},
+		{
+			name:        "invalid selection fields",
+			annotations: map[string]string{types.ServiceLBSelectionFieldsAnnotation: "ip_src,eth_src"},
+		},
+		{
+			name:        "consistent hashing of the 5-tuple",
+			annotations: map[string]string{types.ServiceLBHashingAnnotation: types.ServiceLBHashingConsistent},
+			expected:    []string{"ip_src", "ip_dst", "tp_src", "tp_dst"},
+		},
+		{
+			name: "consistent hashing of the selection fields",
+			annotations: map[string]string{
+				types.ServiceLBHashingAnnotation:         types.ServiceLBHashingConsistent,
+				types.ServiceLBSelectionFieldsAnnotation: "ip_src,ip_dst",
+			},
+			expected: []string{"ip_src", "ip_dst"},
+		},
+		{
+			name:        "invalid hashing",
+			annotations: map[string]string{types.ServiceLBHashingAnnotation: "maglev"},
+		},
+		{
+			name:        "session affinity takes precedence",
+			annotations: map[string]string{types.ServiceLBSelectionFieldsAnnotation: "ip_src"},
+			affinity:    true,
+		},
+	}
+
+	for i, tt := range tc {
+		t.Run(fmt.Sprintf("%d_%s", i, tt.name), func(t *testing.T) {
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns, Annotations: tt.annotations},
+			}
+			if tt.affinity {
+				service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+			}
+			assert.Equal(t, tt.expected, lbOpts(service).SelectionFields)
+		})
+	}
+}
+
+func Test_clientHashSessionAffinity(t *testing.T) {
+	serviceName := "foo"
+	ns := "testns"
+
+	tc := []struct {
+		name                    string
+		affinity                bool
+		mode                    *string
+		expectedAffinityTimeOut int32
+		expectedSelectionFields []nbdb.LoadBalancerSelectionFields
+	}{
+		{
+			name: "no session affinity",
+			mode: ptr.To(types.ServiceSessionAffinityModeClientHash),
+		},
+		{
+			name:                    "session affinity timeout",
+			affinity:                true,
+			expectedAffinityTimeOut: 300,
+		},
+		{
+			name:                    "client hash session affinity",
+			affinity:                true,
+			mode:                    ptr.To(types.ServiceSessionAffinityModeClientHash),
+			expectedSelectionFields: []string{"ip_src"},
+		},
+		{
+			name:                    "invalid session affinity mode",
+			affinity:                true,
+			mode:                    ptr.To("sticky"),
+			expectedAffinityTimeOut: 300,
+		},
+	}
+
+	for i, tt := range tc {
+		t.Run(fmt.Sprintf("%d_%s", i, tt.name), func(t *testing.T) {
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: serviceName, Namespace: ns},
+			}
+			if tt.mode != nil {
+				service.Annotations = map[string]string{types.ServiceSessionAffinityModeAnnotation: *tt.mode}
+			}
+			if tt.affinity {
+				service.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+				service.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+					ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: ptr.To[int32](300)},
+				}
+			}
+			opts := lbOpts(service)
+			assert.Equal(t, tt.expectedAffinityTimeOut, opts.AffinityTimeOut)
+			assert.Equal(t, tt.expectedSelectionFields, opts.SelectionFields)
+			// the node port of a client hash service can use the template load balancers
+			assert.Equal(t, tt.expectedAffinityTimeOut > 0, hasSessionAffinityTimeOut(service))
+		})
+	}
+}
+
+func Test_hasHairpinSNATVIP(t *testing.T) {
+	tc := []struct {
+		name     string
+		value    *string
+		expected bool
+	}{
+		{name: "default"},
+		{name: "masquerade IP", value: ptr.To(types.ServiceHairpinSNATMasquerade)},
+		{name: "vip", value: ptr.To(types.ServiceHairpinSNATVIP), expected: true},
+		{name: "invalid value", value: ptr.To("source")},
+	}
+
+	for i, tt := range tc {
+		t.Run(fmt.Sprintf("%d_%s", i, tt.name), func(t *testing.T) {
+			service := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "testns"},
+			}
+			if tt.value != nil {
+				service.Annotations = map[string]string{types.ServiceHairpinSNATAnnotation: *tt.value}
+			}
+			assert.Equal(t, tt.expected, lbOpts(service).HairpinSNATVIP)
+		})
+	}
+}
+
 func Test_GetEndpointsForService(t *testing.T) {
 	type args struct {
 		slices []*discovery.EndpointSlice
@@ -4462,6 +4832,7 @@ func Test_makeNodeSwitchTargetIPs(t *testing.T) {
 		name                string
 		config              *lbConfig
 		node                string
+		topologyZone        string
 		expectedTargetIPsV4 []string
 		expectedTargetIPsV6 []string
 		expectedV4Changed   bool
@@ -4565,10 +4936,101 @@ func Test_makeNodeSwitchTargetIPs(t *testing.T) {
 			expectedV4Changed:   true,
 			expectedV6Changed:   true,
 		},
+		{
+			name: "cluster ip service with zone hints",
+			config: &lbConfig{
+				vips:     []string{"1.2.3.4", "fe10::1"},
+				protocol: corev1.ProtocolTCP,
+				inport:   80,
+				clusterEndpoints: util.LBEndpoints{
+					V4IPs: []string{"192.168.0.1", "192.168.1.1"},
+					V6IPs: []string{"fe00:0:0:0:1::2", "fe00:0:0:0:2::2"},
+					Port:  8080,
+				},
+				zoneEndpoints: map[string]util.LBEndpoints{
+					"zone-a": {
+						V4IPs: []string{"192.168.0.1"},
+						V6IPs: []string{"fe00:0:0:0:1::2"},
+						Port:  8080,
+					},
+					"zone-b": {
+						V4IPs: []string{"192.168.1.1"},
+						V6IPs: []string{"fe00:0:0:0:2::2"},
+						Port:  8080,
+					},
+				},
+			},
+			node:                nodeA,
+			topologyZone:        "zone-b",
+			expectedTargetIPsV4: []string{"192.168.1.1"}, // only the endpoint hinted for zone-b is kept
+			expectedTargetIPsV6: []string{"fe00:0:0:0:2::2"},
+			expectedV4Changed:   true,
+			expectedV6Changed:   true,
+		},
+		{
+			name: "cluster ip service with zone hints, no endpoints hinted for the node's zone",
+			config: &lbConfig{
+				vips:     []string{"1.2.3.4", "fe10::1"},
+				protocol: corev1.ProtocolTCP,
+				inport:   80,
+				clusterEndpoints: util.LBEndpoints{
+					V4IPs: []string{"192.168.0.1", "192.168.1.1"},
+					V6IPs: []string{"fe00:0:0:0:1::2", "fe00:0:0:0:2::2"},
+					Port:  8080,
+				},
+				zoneEndpoints: map[string]util.LBEndpoints{
+					"zone-a": {
+						V4IPs: []string{"192.168.0.1"},
+						V6IPs: []string{"fe00:0:0:0:1::2"},
+						Port:  8080,
+					},
+				},
+			},
+			node:                nodeA,
+			topologyZone:        "zone-c",
+			expectedTargetIPsV4: []string{"192.168.0.1", "192.168.1.1"}, // falls back to all endpoints
+			expectedTargetIPsV6: []string{"fe00:0:0:0:1::2", "fe00:0:0:0:2::2"},
+			expectedV4Changed:   false,
+			expectedV6Changed:   false,
+		},
+		{
+			name: "service with ITP=local and zone hints",
+			config: &lbConfig{
+				vips:     []string{"1.2.3.4", "fe10::1"},
+				protocol: corev1.ProtocolTCP,
+				inport:   80,
+				clusterEndpoints: util.LBEndpoints{
+					V4IPs: []string{"192.168.0.1", "192.168.1.1"},
+					V6IPs: []string{"fe00:0:0:0:1::2", "fe00:0:0:0:2::2"},
+					Port:  8080,
+				},
+				nodeEndpoints: util.PortToLBEndpoints{
+					nodeA: {
+						V4IPs: []string{"192.168.0.1"},
+						V6IPs: []string{"fe00:0:0:0:1::2"},
+						Port:  8080,
+					},
+				},
+				zoneEndpoints: map[string]util.LBEndpoints{
+					"zone-b": {
+						V4IPs: []string{"192.168.1.1"},
+						V6IPs: []string{"fe00:0:0:0:2::2"},
+						Port:  8080,
+					},
+				},
+				internalTrafficLocal: true,
+			},
+			node:                nodeA,
+			topologyZone:        "zone-b",
+			expectedTargetIPsV4: []string{"192.168.0.1"}, // the traffic policy takes precedence over zone hints
+			expectedTargetIPsV6: []string{"fe00:0:0:0:1::2"},
+			expectedV4Changed:   true,
+			expectedV6Changed:   true,
+		},
 	}
 	for i, tt := range tc {
 		t.Run(fmt.Sprintf("%d_%s", i, tt.name), func(t *testing.T) {
-			actualTargetIPsV4, actualTargetIPsV6, actualV4Changed, actualV6Changed := makeNodeSwitchTargetIPs(tt.node, tt.config)
+			actualTargetIPsV4, actualTargetIPsV6, actualV4Changed, actualV6Changed := makeNodeSwitchTargetIPs(&nodeInfo{name: tt.node, topologyZone: tt.topologyZone}, tt.config)
 			assert.Equal(t, tt.expectedTargetIPsV4, actualTargetIPsV4)
 			assert.Equal(t, tt.expectedTargetIPsV6, actualTargetIPsV6)
 			assert.Equal(t, tt.expectedV4Changed, actualV4Changed)
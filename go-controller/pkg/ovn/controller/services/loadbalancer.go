@@ -14,6 +14,7 @@ import (
 	"k8s.io/kubernetes/pkg/apis/core"
 
 	libovsdbclient "github.com/ovn-kubernetes/libovsdb/client"
+	"github.com/ovn-kubernetes/libovsdb/ovsdb"
 
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
 	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
@@ -35,6 +36,10 @@ type LB struct {
 
 	Templates TemplateMap // Templates that this LB uses as backends.
 
+	// IPPortMappings maps the backend IPs to the "logical_port:source_ip" the OVN service
+	// monitors use to check their health. If set, the ClusterIP vips of the LB get health checks.
+	IPPortMappings map[string]string
+
 	// the names of logical switches, routers and LB groups that this LB should be attached to
 	Switches []string
 	Routers  []string
@@ -54,11 +59,17 @@ type LBOpts struct {
 	// If true, then disable SNAT entirely
 	SkipSNAT bool
 
+	// If true, hairpin traffic is SNATed to the vip instead of the OVN service hairpin masquerade IP
+	HairpinSNATVIP bool
+
 	// If true, this is a LB template.
 	Template bool
 
 	// Only useful for template LBs.
 	AddressFamily corev1.IPFamily
+
+	// If set, the packet fields hashed to select a backend, instead of the OVN default.
+	SelectionFields []nbdb.LoadBalancerSelectionFields
 }
 
 type Addr struct {
@@ -95,8 +106,9 @@ func addrsToString(addrs []Addr) string {
 // templateLoadBalancer enriches a NB load balancer record with the
 // associated template maps it requires provisioned in the NB database.
 type templateLoadBalancer struct {
-	nbLB      *nbdb.LoadBalancer
-	templates TemplateMap
+	nbLB         *nbdb.LoadBalancer
+	templates    TemplateMap
+	healthChecks []*nbdb.LoadBalancerHealthCheck
 }
 
 func toNBLoadBalancerList(tlbs []*templateLoadBalancer) []*nbdb.LoadBalancer {
@@ -168,6 +180,11 @@ func EnsureLBs(nbClient libovsdbclient.Client, service *corev1.Service, existing
 			existingRouters = sets.New[string](existingLB.Routers...)
 			existingSwitches = sets.New[string](existingLB.Switches...)
 			existingGroups = sets.New[string](existingLB.Groups...)
+			if len(existingLB.IPPortMappings) > 0 && len(lb.IPPortMappings) == 0 {
+				// clear the health checks of the existing LB
+				blb.nbLB.HealthCheck = []string{}
+				blb.nbLB.IPPortMappings = map[string]string{}
+			}
 		}
 		wantRouters := sets.New(lb.Routers...)
 		wantSwitches := sets.New(lb.Switches...)
@@ -180,7 +197,20 @@ func EnsureLBs(nbClient libovsdbclient.Client, service *corev1.Service, existing
 		mapLBDifferenceByKey(removeLBsFromGroups, existingGroups, wantGroups, blb)
 	}
 
-	ops, err := libovsdbops.CreateOrUpdateLoadBalancersOps(nbClient, nil, toNBLoadBalancerList(tlbs)...)
+	var ops []ovsdb.Operation
+	var err error
+	for _, tlb := range tlbs {
+		if len(tlb.healthChecks) == 0 {
+			continue
+		}
+		ops, err = libovsdbops.CreateOrUpdateLoadBalancerHealthChecksOps(nbClient, ops, tlb.nbLB, tlb.healthChecks...)
+		if err != nil {
+			return fmt.Errorf("failed to create ops for ensuring health checks of load balancer %s for service %s/%s: %w",
+				tlb.nbLB.Name, service.Namespace, service.Name, err)
+		}
+	}
+
+	ops, err = libovsdbops.CreateOrUpdateLoadBalancersOps(nbClient, ops, toNBLoadBalancerList(tlbs)...)
 	if err != nil {
 		return err
 	}
@@ -361,7 +391,11 @@ func buildLB(lb *LB) *templateLoadBalancer {
 		"event":              emptyLb,
 		"skip_snat":          skipSNAT,
 		"neighbor_responder": "none",
-		"hairpin_snat_ip":    fmt.Sprintf("%s %s", config.Gateway.MasqueradeIPs.V4OVNServiceHairpinMasqueradeIP.String(), config.Gateway.MasqueradeIPs.V6OVNServiceHairpinMasqueradeIP.String()),
+	}
+
+	// OVN SNATs hairpin traffic to the vip unless hairpin_snat_ip is set
+	if !lb.Opts.HairpinSNATVIP {
+		options["hairpin_snat_ip"] = fmt.Sprintf("%s %s", config.Gateway.MasqueradeIPs.V4OVNServiceHairpinMasqueradeIP.String(), config.Gateway.MasqueradeIPs.V6OVNServiceHairpinMasqueradeIP.String())
 	}
 
 	// Session affinity
@@ -377,6 +411,8 @@ func buildLB(lb *LB) *templateLoadBalancer {
 				nbdb.LoadBalancerSelectionFieldsIPDst,
 			}
 		}
+	} else if len(lb.Opts.SelectionFields) > 0 {
+		selectionFields = lb.Opts.SelectionFields
 	}
 
 	if lb.Opts.Template {
@@ -389,10 +425,15 @@ func buildLB(lb *LB) *templateLoadBalancer {
 		}
 	}
 
-	return &templateLoadBalancer{
+	tlb := &templateLoadBalancer{
 		nbLB:      libovsdbops.BuildLoadBalancer(lb.Name, strings.ToLower(lb.Protocol), selectionFields, buildVipMap(lb.Rules), options, lb.ExternalIDs),
 		templates: lb.Templates,
 	}
+	if len(lb.IPPortMappings) > 0 {
+		tlb.nbLB.IPPortMappings = lb.IPPortMappings
+		tlb.healthChecks = buildHealthChecks(lb)
+	}
+	return tlb
 }
 
 // buildVipMap returns a viups map from a set of rules
@@ -499,9 +540,11 @@ func _getLBsCommon(nbClient libovsdbclient.Client, allTemplates TemplateMap, wit
 			Opts:        LBOpts{},
 			Rules:       []LBRule{},
 			Templates:   getLoadBalancerTemplates(lb, allTemplates),
-			Switches:    []string{},
-			Routers:     []string{},
-			Groups:      []string{},
+			// needed to clear the health checks of the LB once disabled
+			IPPortMappings: lb.IPPortMappings,
+			Switches:       []string{},
+			Routers:        []string{},
+			Groups:         []string{},
 		}
 		if lb.Protocol != nil {
 			res.Protocol = *lb.Protocol
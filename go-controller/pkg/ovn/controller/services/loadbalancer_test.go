@@ -5,12 +5,14 @@ package services
 
 import (
 	"fmt"
+	"net"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
+	globalconfig "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
 	libovsdbtest "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
@@ -146,6 +148,83 @@ func TestEnsureLBs(t *testing.T) {
 				SelectionFields: []string{"ip_src", "ip_dst"}, // permanent session affinity, no learn flows
 			},
 		},
+		{
+			desc: "create service with consistent hashing",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeClusterIP,
+				},
+			},
+			LBs: []LB{
+				{
+					Name:        "Service_foo/testns_TCP_cluster",
+					ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+					Routers:     []string{"gr-node-a"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "192.168.1.1", Port: 80},
+							Targets: []Addr{{IP: "10.0.244.3", Port: 8080}},
+						},
+					},
+					UUID: "test-UUID",
+					Opts: LBOpts{
+						Reject:          true,
+						SelectionFields: []string{"ip_src", "ip_dst", "tp_src", "tp_dst"},
+					},
+				},
+			},
+			finalLB: &nbdb.LoadBalancer{
+				UUID:     clusterWideTCPServiceLoadBalancerName(name, namespace),
+				Name:     clusterWideTCPServiceLoadBalancerName(name, namespace),
+				Options:  servicesOptions(),
+				Protocol: &nbdb.LoadBalancerProtocolTCP,
+				Vips: map[string]string{
+					"192.168.1.1:80": "10.0.244.3:8080",
+				},
+				ExternalIDs:     loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+				SelectionFields: []string{"ip_src", "ip_dst", "tp_src", "tp_dst"},
+			},
+		},
+		{
+			desc: "create service with hairpin traffic SNATed to the vip",
+			service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeClusterIP,
+				},
+			},
+			LBs: []LB{
+				{
+					Name:        "Service_foo/testns_TCP_cluster",
+					ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+					Routers:     []string{"gr-node-a"},
+					Protocol:    "TCP",
+					Rules: []LBRule{
+						{
+							Source:  Addr{IP: "192.168.1.1", Port: 80},
+							Targets: []Addr{{IP: "10.0.244.3", Port: 8080}},
+						},
+					},
+					UUID: "test-UUID",
+					Opts: LBOpts{
+						Reject:         true,
+						HairpinSNATVIP: true,
+					},
+				},
+			},
+			finalLB: &nbdb.LoadBalancer{
+				UUID:     clusterWideTCPServiceLoadBalancerName(name, namespace),
+				Name:     clusterWideTCPServiceLoadBalancerName(name, namespace),
+				Options:  servicesOptionsWithHairpinSNATVIP(),
+				Protocol: &nbdb.LoadBalancerProtocolTCP,
+				Vips: map[string]string{
+					"192.168.1.1:80": "10.0.244.3:8080",
+				},
+				ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+			},
+		},
 		{
 			desc: "create service with default session affinity timeout",
 			service: &corev1.Service{
@@ -226,3 +305,117 @@ func TestEnsureLBs(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureLBsHealthChecks(t *testing.T) {
+	oldServiceCIDRs := globalconfig.Kubernetes.ServiceCIDRs
+	defer func() {
+		globalconfig.Kubernetes.ServiceCIDRs = oldServiceCIDRs
+	}()
+	_, svcCIDRv4, _ := net.ParseCIDR("192.168.0.0/16")
+	globalconfig.Kubernetes.ServiceCIDRs = []*net.IPNet{svcCIDRv4}
+
+	nbClient, cleanup, err := libovsdbtest.NewNBTestHarness(libovsdbtest.TestSetup{
+		NBData: []libovsdbtest.TestData{
+			&nbdb.LogicalRouter{
+				Name: "gr-node-a",
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Error creating NB: %v", err)
+	}
+	t.Cleanup(cleanup.Cleanup)
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+		},
+	}
+	lbName := clusterWideTCPServiceLoadBalancerName(name, namespace)
+	newLB := func(ipPortMappings map[string]string) []LB {
+		return []LB{
+			{
+				Name:        lbName,
+				ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+				Routers:     []string{"gr-node-a"},
+				Protocol:    "TCP",
+				Rules: []LBRule{
+					{
+						Source:  Addr{IP: "192.168.1.1", Port: 80},
+						Targets: []Addr{{IP: "10.128.0.3", Port: 8080}, {IP: "10.128.0.4", Port: 8080}},
+					},
+				},
+				Opts:           LBOpts{Reject: true},
+				IPPortMappings: ipPortMappings,
+			},
+		}
+	}
+	vips := map[string]string{
+		"192.168.1.1:80": "10.128.0.3:8080,10.128.0.4:8080",
+	}
+	ipPortMappings := map[string]string{
+		"10.128.0.3": "testns_pod1:10.128.0.1",
+		"10.128.0.4": "testns_pod2:10.128.0.1",
+	}
+
+	// create the load balancer with a health check
+	withHealthCheck := newLB(ipPortMappings)
+	if err := EnsureLBs(nbClient, service, nil, withHealthCheck, &util.DefaultNetInfo{}); err != nil {
+		t.Fatalf("Error EnsureLBs: %v", err)
+	}
+	matcher := libovsdbtest.HaveDataIgnoringUUIDs([]libovsdbtest.TestData{
+		&nbdb.LoadBalancerHealthCheck{
+			UUID:        "hc-UUID",
+			Vip:         "192.168.1.1:80",
+			ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+		},
+		&nbdb.LoadBalancer{
+			UUID:           lbName,
+			Name:           lbName,
+			Options:        servicesOptions(),
+			Protocol:       &nbdb.LoadBalancerProtocolTCP,
+			Vips:           vips,
+			ExternalIDs:    loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+			HealthCheck:    []string{"hc-UUID"},
+			IPPortMappings: ipPortMappings,
+		},
+		&nbdb.LogicalRouter{
+			Name:         "gr-node-a",
+			LoadBalancer: []string{lbName},
+		},
+	})
+	if success, err := matcher.Match(nbClient); !success || err != nil {
+		t.Fatalf("Health check not created, err: %v, %s", err, matcher.FailureMessage(nbClient))
+	}
+
+	// updating the load balancer keeps the existing health check
+	if err := EnsureLBs(nbClient, service, withHealthCheck, newLB(ipPortMappings), &util.DefaultNetInfo{}); err != nil {
+		t.Fatalf("Error EnsureLBs: %v", err)
+	}
+	if success, err := matcher.Match(nbClient); !success || err != nil {
+		t.Fatalf("Health check not kept, err: %v, %s", err, matcher.FailureMessage(nbClient))
+	}
+
+	// disabling the health check clears it from the load balancer
+	if err := EnsureLBs(nbClient, service, withHealthCheck, newLB(nil), &util.DefaultNetInfo{}); err != nil {
+		t.Fatalf("Error EnsureLBs: %v", err)
+	}
+	matcher = libovsdbtest.HaveDataIgnoringUUIDs([]libovsdbtest.TestData{
+		&nbdb.LoadBalancer{
+			UUID:        lbName,
+			Name:        lbName,
+			Options:     servicesOptions(),
+			Protocol:    &nbdb.LoadBalancerProtocolTCP,
+			Vips:        vips,
+			ExternalIDs: loadBalancerExternalIDs(namespacedServiceName(namespace, name)),
+		},
+		&nbdb.LogicalRouter{
+			Name:         "gr-node-a",
+			LoadBalancer: []string{lbName},
+		},
+	})
+	if success, err := matcher.Match(nbClient); !success || err != nil {
+		t.Fatalf("Health check not cleared, err: %v, %s", err, matcher.FailureMessage(nbClient))
+	}
+}
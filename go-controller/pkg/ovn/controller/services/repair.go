@@ -104,6 +104,7 @@ func (r *repair) runBeforeSync(useTemplates bool, netInfo util.NetInfo, nodes ma
 		staleTemplateNames.Insert(templateName)
 	}
 	staleLBs := []string{}
+	staleHealthCheckLBs := []*nbdb.LoadBalancer{}
 	for _, lb := range existingLBs {
 		// Extract namespace + name, look to see if it exists
 		owner := lb.ExternalIDs[types.LoadBalancerOwnerExternalID]
@@ -114,13 +115,24 @@ func (r *repair) runBeforeSync(useTemplates bool, netInfo util.NetInfo, nodes ma
 			continue
 		}
 
-		_, err = r.serviceLister.Services(namespace).Get(name)
+		service, err := r.serviceLister.Services(namespace).Get(name)
 		if apierrors.IsNotFound(err) {
 			klog.V(5).Infof("Found stale service LB %#v", lb)
 			staleLBs = append(staleLBs, lb.UUID)
 			continue
 		}
 
+		// Health checks are no longer requested for the service: clear them
+		// (the Load_Balancer_Health_Check rows are garbage collected).
+		if err == nil && len(lb.IPPortMappings) > 0 && !serviceHealthCheckEnabled(service) {
+			klog.V(5).Infof("Found stale health checks of service LB %#v", lb)
+			staleHealthCheckLBs = append(staleHealthCheckLBs, &nbdb.LoadBalancer{
+				UUID:           lb.UUID,
+				HealthCheck:    []string{},
+				IPPortMappings: map[string]string{},
+			})
+		}
+
 		// All of the LB's template vars are still useful.
 		for _, t := range lb.Templates {
 			staleTemplateNames.Delete(t.Name)
@@ -133,6 +145,19 @@ func (r *repair) runBeforeSync(useTemplates bool, netInfo util.NetInfo, nodes ma
 	}
 	klog.V(2).Infof("Deleted %d stale service LBs", len(staleLBs))
 
+	// Clear the stale health checks
+	if len(staleHealthCheckLBs) > 0 {
+		ops, err := libovsdbops.CreateOrUpdateLoadBalancersOps(r.nbClient, nil, staleHealthCheckLBs...)
+		if err == nil {
+			_, err = libovsdbops.TransactAndCheck(r.nbClient, ops)
+		}
+		if err != nil {
+			klog.Errorf("Failed to clear stale health checks of service LBs: %v", err)
+		} else {
+			klog.V(2).Infof("Cleared health checks of %d service LBs", len(staleHealthCheckLBs))
+		}
+	}
+
 	// Delete those stale template vars
 	if err := libovsdbops.DeleteAllChassisTemplateVarVariables(r.nbClient, staleTemplateNames.UnsortedList()); err != nil {
 		klog.Errorf("Failed to delete stale Chassis Template Vars: %v", err)
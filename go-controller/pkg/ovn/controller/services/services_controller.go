@@ -41,6 +41,7 @@ import (
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/metrics/recorders"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/networkmanager"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/ovn/controller/unidling"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
 )
@@ -62,6 +63,7 @@ var ErrMissingServiceLabel = fmt.Errorf("endpointSlice missing the service name
 // NewController returns a new *Controller.
 func NewController(client clientset.Interface,
 	nbClient libovsdbclient.Client,
+	sbClient libovsdbclient.Client,
 	serviceInformer coreinformers.ServiceInformer,
 	endpointSliceInformer discoveryinformers.EndpointSliceInformer,
 	nodeInformer coreinformers.NodeInformer,
@@ -73,12 +75,14 @@ func NewController(client clientset.Interface,
 	c := &Controller{
 		client:   client,
 		nbClient: nbClient,
+		sbClient: sbClient,
 		queue: workqueue.NewTypedRateLimitingQueueWithConfig(
 			newRatelimiter(100),
 			workqueue.TypedRateLimitingQueueConfig[string]{Name: controllerName},
 		),
 		workerLoopPeriod:      time.Second,
 		alreadyApplied:        map[string][]LB{},
+		healthChecks:          newHealthCheckTracker(),
 		nodeIPv4Templates:     NewNodeIPsTemplates(corev1.IPv4Protocol),
 		nodeIPv6Templates:     NewNodeIPsTemplates(corev1.IPv6Protocol),
 		serviceInformer:       serviceInformer,
@@ -111,6 +115,9 @@ type Controller struct {
 	// libovsdb northbound client interface
 	nbClient      libovsdbclient.Client
 	eventRecorder record.EventRecorder
+	// libovsdb southbound client interface, used to report the status of the
+	// service backend health checks. Only set for the default network.
+	sbClient libovsdbclient.Client
 
 	serviceInformer coreinformers.ServiceInformer
 	// serviceLister is able to list/get services and is populated by the shared informer passed to
@@ -159,6 +166,9 @@ type Controller struct {
 	alreadyApplied       map[string][]LB
 	alreadyAppliedRWLock sync.RWMutex
 
+	// healthChecks tracks the services of the backends with OVN load balancer health checks
+	healthChecks *healthCheckTracker
+
 	// Lock order considerations: if both nodeInfoRWLock and alreadyAppliedRWLock
 	// need to be taken for some reason then the order in which they're taken is
 	// always: first nodeInfoRWLock and then alreadyAppliedRWLock.
@@ -253,6 +263,10 @@ func (c *Controller) Run(workers int, stopCh <-chan struct{}, wg *sync.WaitGroup
 	c.startupDone = true
 	c.startupDoneLock.Unlock()
 
+	if c.netInfo.IsDefault() && c.sbClient != nil {
+		c.runServiceMonitorStatusReporter(stopCh)
+	}
+
 	// Start the workers after the repair loop to avoid races
 	klog.Infof("Starting workers for network=%s", c.netInfo.GetNetworkName())
 	for i := 0; i < workers; i++ {
@@ -453,6 +467,7 @@ func (c *Controller) syncService(key string) error {
 			delete(c.alreadyApplied, key)
 			c.alreadyAppliedRWLock.Unlock()
 		}
+		c.healthChecks.setServiceBackends(key, nil)
 
 		c.repair.serviceSynced(key)
 		return nil
@@ -485,6 +500,12 @@ func (c *Controller) syncService(key string) error {
 	lbs := append(clusterLBs, templateLBs...)
 	lbs = append(lbs, perNodeLBs...)
 
+	// Health check the ClusterIP backends running in this zone, if requested
+	if c.netInfo.IsDefault() && serviceHealthCheckEnabled(service) {
+		setLBIPPortMappings(lbs, buildHealthCheckIPPortMappings(endpointSlices, c.nodeInfos))
+	}
+	c.healthChecks.setServiceBackends(key, getHealthCheckedBackends(lbs))
+
 	// Short-circuit if nothing has changed
 	c.alreadyAppliedRWLock.RLock()
 	alreadyAppliedLbs, alreadyAppliedKeyExists := c.alreadyApplied[key]
@@ -512,6 +533,13 @@ func (c *Controller) syncService(key string) error {
 		c.alreadyAppliedRWLock.Unlock()
 	}
 
+	// Sync the service again at the end of its unidling grace period so that it rejects connections again
+	if globalconfig.Kubernetes.OVNEmptyLbEvents {
+		if remaining := unidling.GracePeriodRemaining(service); remaining > 0 {
+			c.queue.AddAfter(key, remaining)
+		}
+	}
+
 	c.repair.serviceSynced(key)
 	return nil
 }
@@ -89,6 +89,7 @@ func newControllerWithDBSetupForNetwork(dbSetup libovsdbtest.TestSetup, netInfo
 
 	controller, err := NewController(client.KubeClient,
 		nbClient,
+		nil,
 		factoryMock.ServiceCoreInformer(),
 		factoryMock.EndpointSliceCoreInformer(),
 		factoryMock.NodeCoreInformer(),
@@ -1717,6 +1718,12 @@ func servicesOptionsWithAffinityTimeout() map[string]string {
 	return options
 }
 
+func servicesOptionsWithHairpinSNATVIP() map[string]string {
+	options := servicesOptions()
+	delete(options, "hairpin_snat_ip")
+	return options
+}
+
 func templateServicesOptions() map[string]string {
 	// Template LBs need "options:template=true" and "options:address-family" set.
 	opts := servicesOptions()
@@ -21,13 +21,18 @@ import (
 	"github.com/ovn-kubernetes/libovsdb/ovsdb"
 
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/sbdb"
+	ovntypes "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
 )
 
 // unidlingController checks periodically the OVN events db
 // and generates a Kubernetes NeedPods events with the Service
-// associated to the VIP
+// associated to the load balancer or to the VIP. The packets that
+// triggered the events are dropped by OVN and are not part of the
+// events, so they can't be replayed once the service has pods.
 type unidlingController struct {
 	eventQueue    chan sbdb.ControllerEvent
 	eventRecorder record.EventRecorder
@@ -35,15 +40,20 @@ type unidlingController struct {
 	serviceVIPToName     map[ServiceVIPKey]types.NamespacedName
 	serviceVIPToNameLock sync.Mutex
 	sbClient             libovsdbclient.Client
+	// nbClient is used to find the service and network of the load
+	// balancer of an event, services of all the networks share the
+	// same southbound events
+	nbClient libovsdbclient.Client
 }
 
 // NewController creates a new unidling controller
-func NewController(recorder record.EventRecorder, serviceInformer cache.SharedIndexInformer, sbClient libovsdbclient.Client) (*unidlingController, error) {
+func NewController(recorder record.EventRecorder, serviceInformer cache.SharedIndexInformer, sbClient, nbClient libovsdbclient.Client) (*unidlingController, error) {
 	uc := &unidlingController{
 		eventQueue:       make(chan sbdb.ControllerEvent),
 		eventRecorder:    recorder,
 		serviceVIPToName: map[ServiceVIPKey]types.NamespacedName{},
 		sbClient:         sbClient,
+		nbClient:         nbClient,
 	}
 
 	klog.Info("Registering OVN SB ControllerEvent handler")
@@ -207,7 +217,13 @@ func (uc *unidlingController) handleLbEmptyBackendsEvent(event sbdb.ControllerEv
 		protocol = corev1.ProtocolTCP
 	}
 
-	serviceName, ok := uc.GetServiceVIPToName(vip, protocol)
+	serviceName, network, ok := uc.getLoadBalancerService(event.EventInfo["load_balancer"])
+	if !ok {
+		// the load balancer might be gone already, or the event might
+		// come from an OVN version that does not report it
+		serviceName, ok = uc.GetServiceVIPToName(vip, protocol)
+		network = ovntypes.DefaultNetworkName
+	}
 
 	if !ok {
 		return fmt.Errorf("can't find service for vip %s:%s", protocol, vip)
@@ -218,8 +234,33 @@ func (uc *unidlingController) handleLbEmptyBackendsEvent(event sbdb.ControllerEv
 		Namespace: serviceName.Namespace,
 		Name:      serviceName.Name,
 	}
-	klog.V(5).Infof("Sending a NeedPods event for service %s in namespace %s.", serviceName.Name, serviceName.Namespace)
+	klog.V(5).Infof("Sending a NeedPods event for service %s in namespace %s for network %s.", serviceName.Name, serviceName.Namespace, network)
 	uc.eventRecorder.Eventf(&serviceRef, corev1.EventTypeNormal, "NeedPods", "The service %s needs pods", serviceName.Name)
 
 	return nil
 }
+
+// getLoadBalancerService returns the service and the network that own the load
+// balancer with the given UUID
+func (uc *unidlingController) getLoadBalancerService(lbUUID string) (types.NamespacedName, string, bool) {
+	if lbUUID == "" || uc.nbClient == nil {
+		return types.NamespacedName{}, "", false
+	}
+	lb, err := libovsdbops.GetLoadBalancer(uc.nbClient, &nbdb.LoadBalancer{UUID: lbUUID})
+	if err != nil {
+		klog.V(5).Infof("Can't find load balancer %s of the empty backends event: %v", lbUUID, err)
+		return types.NamespacedName{}, "", false
+	}
+	if lb.ExternalIDs[ovntypes.LoadBalancerKindExternalID] != "Service" {
+		return types.NamespacedName{}, "", false
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(lb.ExternalIDs[ovntypes.LoadBalancerOwnerExternalID])
+	if err != nil || name == "" {
+		return types.NamespacedName{}, "", false
+	}
+	network := lb.ExternalIDs[ovntypes.NetworkExternalID]
+	if network == "" {
+		network = ovntypes.DefaultNetworkName
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, network, true
+}
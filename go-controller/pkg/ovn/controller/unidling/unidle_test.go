@@ -19,8 +19,10 @@ import (
 
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/kube"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/sbdb"
 	libovsdbtest "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -79,6 +81,7 @@ var _ = Describe("Unidling Controller", func() {
 			recorder,
 			serviceInformer,
 			sbClient,
+			nil,
 		)
 		Expect(err).NotTo(HaveOccurred())
 
@@ -124,6 +127,92 @@ var _ = Describe("Unidling Controller", func() {
 		}
 	})
 
+	It("should respond to a controller event for the load balancer of a user defined network service", func() {
+		client := fake.NewSimpleClientset()
+		recorder := record.NewFakeRecorder(10)
+		informerFactory := informers.NewSharedInformerFactory(client, 0)
+		serviceInformer := informerFactory.Core().V1().Services().Informer()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		const lbUUID = "8d6b5c1e-3f0a-4e2b-9c7d-1a2b3c4d5e6f"
+		testSetup := libovsdbtest.TestSetup{
+			NBData: []libovsdbtest.TestData{
+				&nbdb.LoadBalancer{
+					UUID:     lbUUID,
+					Name:     "Service_bar_ns/bar_service_UDP_node_router_node1_network1",
+					Protocol: &nbdb.LoadBalancerProtocolUDP,
+					ExternalIDs: map[string]string{
+						types.LoadBalancerKindExternalID:  "Service",
+						types.LoadBalancerOwnerExternalID: "bar_ns/bar_service",
+						types.NetworkExternalID:           "network1",
+					},
+				},
+			},
+			SBData: []libovsdbtest.TestData{
+				&sbdb.ControllerEvent{
+					EventType: sbdb.ControllerEventEventTypeEmptyLbBackends,
+					SeqNum:    9,
+					EventInfo: map[string]string{
+						// node port vip, unknown to the service vip map
+						"vip":           "172.18.0.2:30053",
+						"protocol":      "udp",
+						"load_balancer": lbUUID,
+					},
+				},
+			},
+		}
+
+		nbClient, sbClient, libovsdbCleanup, err := libovsdbtest.NewNBSBTestHarness(testSetup)
+		Expect(err).NotTo(HaveOccurred())
+		cleanup = libovsdbCleanup
+
+		c, err := NewController(
+			recorder,
+			serviceInformer,
+			sbClient,
+			nbClient,
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		informerFactory.Start(ctx.Done())
+		cache.WaitForCacheSync(ctx.Done(), serviceInformer.HasSynced)
+
+		go c.Run(ctx.Done())
+
+		timeout := time.Tick(5 * time.Second)
+		select {
+		case event := <-recorder.Events:
+			Expect(event).To(Equal("Normal NeedPods The service bar_service needs pods"))
+		case <-timeout:
+			Fail("did not receive controller_event event")
+		}
+	})
+
+	It("should use the grace period of the service", func() {
+		unidledAt := time.Now().Add(-time.Minute).Format(time.RFC3339)
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default", Name: "svc1",
+				Annotations: map[string]string{UnidledAtAnnotation: unidledAt},
+			},
+		}
+		Expect(GetGracePeriod(svc)).To(Equal(GracePeriodDuration))
+		Expect(IsOnGracePeriod(svc)).To(BeFalse())
+
+		svc.Annotations[GracePeriodAnnotation] = "2m"
+		Expect(GetGracePeriod(svc)).To(Equal(2 * time.Minute))
+		Expect(IsOnGracePeriod(svc)).To(BeTrue())
+		Expect(GracePeriodRemaining(svc)).To(BeNumerically("~", time.Minute, 5*time.Second))
+
+		svc.Annotations[GracePeriodAnnotation] = "0s"
+		Expect(IsOnGracePeriod(svc)).To(BeFalse())
+
+		svc.Annotations[GracePeriodAnnotation] = "forever"
+		Expect(GetGracePeriod(svc)).To(Equal(GracePeriodDuration))
+	})
+
 	It("should update unidled-at annotation when unidling", func() {
 		client := fake.NewSimpleClientset()
 		informerFactory := informers.NewSharedInformerFactory(client, 0)
@@ -14,6 +14,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/kube"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
 )
 
 const (
@@ -21,6 +22,8 @@ const (
 	IdledAtSuffix       = "/idled-at"
 	UnidledAtSuffix     = "/unidled-at"
 	UnidledAtAnnotation = "k8s.ovn.org" + UnidledAtSuffix
+	// GracePeriodAnnotation overrides the GracePeriodDuration of a service, as a duration like "1m30s"
+	GracePeriodAnnotation = "k8s.ovn.org/unidling-grace-period"
 )
 
 type unidledAtController struct {
@@ -65,22 +68,50 @@ func HasIdleAt(svc *corev1.Service) bool {
 	return false
 }
 
-// IsOnGracePeriod return true if the service has been unidled less than 30s (grace period) ago.
-func IsOnGracePeriod(svc *corev1.Service) bool {
+// GetGracePeriod returns the duration after the service has been unidled during which it does not reject
+// connections while it has no endpoints: the GracePeriodAnnotation of the service or GracePeriodDuration.
+func GetGracePeriod(svc *corev1.Service) time.Duration {
+	gracePeriod, ok := util.ParseServiceAnnotation(svc, GracePeriodAnnotation, parseGracePeriod)
+	if !ok {
+		return GracePeriodDuration
+	}
+	return gracePeriod
+}
+
+func parseGracePeriod(value string) (time.Duration, error) {
+	gracePeriod, err := time.ParseDuration(value)
+	if err == nil && gracePeriod < 0 {
+		err = fmt.Errorf("negative grace period")
+	}
+	return gracePeriod, err
+}
+
+// GracePeriodRemaining returns the remaining time of the grace period of the service, or 0 if the service is not
+// on grace period.
+func GracePeriodRemaining(svc *corev1.Service) time.Duration {
 	ok, unidledAtStr := getUnidleAt(svc)
 	if !ok {
-		return false
+		return 0
 	}
 
 	unidledAtTime, err := time.Parse(time.RFC3339, unidledAtStr)
 	if err != nil {
 		klog.Warningf("Bad value [%s] for [%s] annotation on service [%s/%s]", unidledAtStr, UnidledAtAnnotation, svc.Namespace, svc.Name)
-		return false
+		return 0
 	}
 
-	endOfGracePeriod := unidledAtTime.Add(GracePeriodDuration)
+	endOfGracePeriod := unidledAtTime.Add(GetGracePeriod(svc))
 
-	return time.Now().Before(endOfGracePeriod)
+	remaining := time.Until(endOfGracePeriod)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// IsOnGracePeriod return true if the service has been unidled less than its grace period ago.
+func IsOnGracePeriod(svc *corev1.Service) bool {
+	return GracePeriodRemaining(svc) > 0
 }
 
 func (uac *unidledAtController) onServiceUpdate(old, new interface{}) {
@@ -113,6 +113,13 @@ type DefaultNetworkController struct {
 	dnsNameResolver dnsnameresolver.DNSNameResolver
 	efController    *efcontroller.EFController
 
+	// aclStatsCollector collects the ACL counters shared by the rule hits of egress firewalls and (admin) network
+	// policies, nil when rule hits are disabled
+	aclStatsCollector *util.ACLStatsCollector
+	// networkPolicyRuleHits are the hit counts of the network policy rules at the last rule hits update,
+	// only accessed by updateNetworkPolicyRuleHits
+	networkPolicyRuleHits map[networkPolicyRule]util.ACLStats
+
 	// retry framework for egress IP
 	retryEgressIPs *retry.RetryFramework
 	// retry framework for egress IP Namespaces
@@ -184,7 +191,7 @@ func newDefaultNetworkControllerCommon(
 	}
 
 	svcController, err := svccontroller.NewController(
-		cnci.client, cnci.nbClient,
+		cnci.client, cnci.nbClient, cnci.sbClient,
 		cnci.watchFactory.ServiceCoreInformer(),
 		cnci.watchFactory.EndpointSliceCoreInformer(),
 		cnci.watchFactory.NodeCoreInformer(),
@@ -255,6 +262,10 @@ func newDefaultNetworkControllerCommon(
 
 	oc.ovnClusterLRPToJoinIfAddrs = gwLRPIfAddrs
 
+	if getACLStatsCollectionInterval() > 0 {
+		oc.aclStatsCollector = util.NewACLStatsCollector()
+	}
+
 	oc.initRetryFramework()
 	if oc.eIPC != nil {
 		oc.eIPC.retryEgressIPPods = oc.retryEgressIPPods
@@ -649,6 +660,13 @@ func (oc *DefaultNetworkController) init() error {
 // run starts the actual watching.
 func (oc *DefaultNetworkController) run(_ context.Context) error {
 	oc.syncPeriodic()
+	if oc.aclStatsCollector != nil {
+		oc.wg.Add(1)
+		go func() {
+			defer oc.wg.Done()
+			oc.aclStatsCollector.Run(getACLStatsCollectionInterval(), oc.stopChan)
+		}()
+	}
 	klog.Info("Starting all the Watchers...")
 	start := time.Now()
 
@@ -737,7 +755,8 @@ func (oc *DefaultNetworkController) run(_ context.Context) error {
 			oc.dnsNameResolver, err = dnsnameresolver.NewExternalEgressDNS(oc.addressSetFactory, oc.controllerName, true,
 				oc.watchFactory.DNSNameResolverInformer().Informer(), oc.watchFactory.EgressFirewallInformer().Lister())
 		} else {
-			oc.dnsNameResolver, err = dnsnameresolver.NewEgressDNS(oc.addressSetFactory, oc.controllerName, oc.stopChan, egressFirewallDNSDefaultDuration)
+			oc.dnsNameResolver, err = dnsnameresolver.NewEgressDNS(oc.addressSetFactory, oc.controllerName, oc.stopChan, egressFirewallDNSDefaultDuration,
+				oc.watchFactory.PodCoreInformer().Lister())
 		}
 		if err != nil {
 			return err
@@ -749,7 +768,7 @@ func (oc *DefaultNetworkController) run(_ context.Context) error {
 
 		oc.efController, err = efcontroller.NewEFController("egress-firewall-controller", oc.zone, oc.kube, oc.nbClient,
 			oc.watchFactory.NamespaceInformer().Lister(), oc.watchFactory.NodeCoreInformer(), oc.watchFactory.EgressFirewallInformer(),
-			oc.networkManager, oc.dnsNameResolver, oc.observManager)
+			oc.networkManager, oc.dnsNameResolver, oc.observManager, oc.aclStatsCollector)
 		if err != nil {
 			return err
 		}
@@ -832,6 +851,7 @@ func (oc *DefaultNetworkController) run(_ context.Context) error {
 			oc.recorder,
 			oc.watchFactory.ServiceInformer(),
 			oc.sbClient,
+			oc.nbClient,
 		)
 		if err != nil {
 			return err
@@ -845,6 +865,14 @@ func (oc *DefaultNetworkController) run(_ context.Context) error {
 
 	metrics.RunOVNKubeFeatureDBObjectsMetricsUpdater(oc.nbClient, oc.controllerName, 30*time.Second, oc.stopChan)
 
+	if oc.aclStatsCollector != nil && config.OVNKubernetesFeature.PolicyRuleHitsInterval > 0 {
+		oc.wg.Add(1)
+		go func() {
+			defer oc.wg.Done()
+			wait.Until(oc.updateNetworkPolicyRuleHits, config.OVNKubernetesFeature.PolicyRuleHitsInterval, oc.stopChan)
+		}()
+	}
+
 	return nil
 }
 
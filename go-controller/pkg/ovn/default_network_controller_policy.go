@@ -3,9 +3,103 @@
 
 package ovn
 
+import (
+	"strconv"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	libovsdbops "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/libovsdb/ops"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/metrics"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+)
+
 // WatchNetworkPolicy starts the watching of network policy resource and calls
 // back the appropriate handler logic
 func (oc *DefaultNetworkController) WatchNetworkPolicy() error {
 	_, err := oc.retryNetworkPolicies.WatchResource()
 	return err
 }
+
+// getACLStatsCollectionInterval returns how often the ACL counters shared by the rule hits of egress firewalls and
+// (admin) network policies are collected, the shortest of the rule hits intervals, or 0 when rule hits are disabled.
+func getACLStatsCollectionInterval() time.Duration {
+	ruleHitsIntervals := []time.Duration{config.OVNKubernetesFeature.PolicyRuleHitsInterval}
+	if config.OVNKubernetesFeature.EnableEgressFirewall {
+		ruleHitsIntervals = append(ruleHitsIntervals, config.OVNKubernetesFeature.EgressFirewallRuleHitsInterval)
+	}
+	var interval time.Duration
+	for _, ruleHitsInterval := range ruleHitsIntervals {
+		if ruleHitsInterval > 0 && (interval == 0 || ruleHitsInterval < interval) {
+			interval = ruleHitsInterval
+		}
+	}
+	return interval
+}
+
+// networkPolicyRule identifies a NetworkPolicy ingress or egress rule
+type networkPolicyRule struct {
+	namespace string
+	name      string
+	direction string
+	idx       int
+}
+
+// updateNetworkPolicyRuleHits reads the hit counts of all network policy ACLs from the last ACL counters collection
+// and adds the new hits to the metrics, by policy rule.
+func (oc *DefaultNetworkController) updateNetworkPolicyRuleHits() {
+	predicateIDs := libovsdbops.NewDbObjectIDs(libovsdbops.ACLNetworkPolicy, oc.controllerName, nil)
+	acls, err := libovsdbops.FindACLsWithPredicate(oc.nbClient, libovsdbops.GetPredicate[*nbdb.ACL](predicateIDs, nil))
+	if err != nil {
+		klog.Errorf("Failed to find network policy ACLs: %v", err)
+		return
+	}
+	aclUUIDs := make([]string, 0, len(acls))
+	for _, acl := range acls {
+		aclUUIDs = append(aclUUIDs, acl.UUID)
+	}
+	aclStats, ok := oc.aclStatsCollector.GetACLStats(aclUUIDs)
+	if !ok {
+		return
+	}
+	ruleHits := getNetworkPolicyRuleHits(acls, aclStats)
+	for rule, stats := range ruleHits {
+		newHits := stats.Since(oc.networkPolicyRuleHits[rule])
+		metrics.AddNetworkPolicyRuleHits(rule.namespace, rule.name, rule.direction, rule.idx, newHits.Packets, newHits.Bytes)
+	}
+	for rule := range oc.networkPolicyRuleHits {
+		if _, ok := ruleHits[rule]; !ok {
+			metrics.DeleteNetworkPolicyRuleHits(rule.namespace, rule.name, rule.direction, rule.idx)
+		}
+	}
+	oc.networkPolicyRuleHits = ruleHits
+}
+
+// getNetworkPolicyRuleHits sums the hit counts of the given network policy ACLs per policy rule.
+// A rule may have multiple ACLs, one per protocol and ipBlock.
+func getNetworkPolicyRuleHits(acls []*nbdb.ACL, aclStats map[string]util.ACLStats) map[networkPolicyRule]util.ACLStats {
+	ruleHits := map[networkPolicyRule]util.ACLStats{}
+	for _, acl := range acls {
+		namespace, name, err := libovsdbops.ParseNamespaceNameKey(acl.ExternalIDs[libovsdbops.ObjectNameKey.String()])
+		if err != nil {
+			continue
+		}
+		idx, err := strconv.Atoi(acl.ExternalIDs[libovsdbops.GressIdxKey.String()])
+		if err != nil {
+			continue
+		}
+		rule := networkPolicyRule{
+			namespace: namespace,
+			name:      name,
+			direction: acl.ExternalIDs[libovsdbops.PolicyDirectionKey.String()],
+			idx:       idx,
+		}
+		hits := ruleHits[rule]
+		hits.Packets += aclStats[acl.UUID].Packets
+		hits.Bytes += aclStats[acl.UUID].Bytes
+		ruleHits[rule] = hits
+	}
+	return ruleHits
+}
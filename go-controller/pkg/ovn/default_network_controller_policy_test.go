@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package ovn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	knet "k8s.io/api/networking/v1"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/nbdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/types"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+)
+
+func TestGetNetworkPolicyRuleHits(t *testing.T) {
+	ingress0 := newGressPolicy(knet.PolicyTypeIngress, 0, "testing", "policy1", types.DefaultNetworkControllerName,
+		false, &util.DefaultNetInfo{})
+	egress1 := newGressPolicy(knet.PolicyTypeEgress, 1, "testing", "policy1", types.DefaultNetworkControllerName,
+		false, &util.DefaultNetInfo{})
+	acls := []*nbdb.ACL{
+		{UUID: "acl1", ExternalIDs: ingress0.getNetpolACLDbIDs(emptyIdx, "TCP").GetExternalIDs()},
+		{UUID: "acl2", ExternalIDs: ingress0.getNetpolACLDbIDs(emptyIdx, "UDP").GetExternalIDs()},
+		{UUID: "acl3", ExternalIDs: egress1.getNetpolACLDbIDs(ipBlockCombinedIdx, "TCP").GetExternalIDs()},
+		// no flows in the local zone
+		{UUID: "acl4", ExternalIDs: egress1.getNetpolACLDbIDs(emptyIdx, "TCP").GetExternalIDs()},
+		// not a network policy ACL
+		{UUID: "acl5", ExternalIDs: map[string]string{}},
+	}
+	aclStats := map[string]util.ACLStats{
+		"acl1": {Packets: 3, Bytes: 300},
+		"acl2": {Packets: 2, Bytes: 100},
+		"acl3": {Packets: 7, Bytes: 700},
+		"acl5": {Packets: 1, Bytes: 10},
+	}
+	assert.Equal(t, map[networkPolicyRule]util.ACLStats{
+		{namespace: "testing", name: "policy1", direction: "Ingress", idx: 0}: {Packets: 5, Bytes: 400},
+		{namespace: "testing", name: "policy1", direction: "Egress", idx: 1}:  {Packets: 7, Bytes: 700},
+	}, getNetworkPolicyRuleHits(acls, aclStats))
+}
@@ -6,10 +6,13 @@ package dnsnameresolver
 import (
 	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/klog/v2"
 
 	libovsdbclient "github.com/ovn-kubernetes/libovsdb/client"
@@ -21,6 +24,15 @@ import (
 	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
 )
 
+const (
+	// maxLearnedDNSNames is the maximum number of DNS names learned for a wildcard DNS name, the least
+	// recently seen learned name is evicted to learn a new one.
+	maxLearnedDNSNames = 256
+	// learnedDNSNameIdleTimeout is how long a learned DNS name is kept after it was last seen in a DNS
+	// response sent to a pod.
+	learnedDNSNameIdleTimeout = time.Hour
+)
+
 type EgressDNS struct {
 	// Protects pdMap/namespaces operations
 	lock sync.Mutex
@@ -34,6 +46,8 @@ type EgressDNS struct {
 	// default interval of time to send DNS lookup
 	// requests.
 	defaultInterval time.Duration
+	// podLister is used to only accept the dnstap messages of the cluster DNS pods
+	podLister corelisters.PodLister
 
 	// Report change when Add operation is done
 	added          chan struct{}
@@ -52,6 +66,10 @@ type dnsEntry struct {
 	dnsResolves []net.IP
 	// the addressSet that contains the current IPs
 	dnsAddressSet addressset.AddressSet
+	// learnedNames holds the DNS names matching a wildcard dnsName that were learned from
+	// the DNS responses sent to pods, with the time they were last seen in a response.
+	// Only set for wildcard DNS names.
+	learnedNames map[string]time.Time
 }
 
 func GetEgressFirewallDNSAddrSetDbIDs(dnsName, controller string) *libovsdbops.DbObjectIDs {
@@ -63,7 +81,7 @@ func GetEgressFirewallDNSAddrSetDbIDs(dnsName, controller string) *libovsdbops.D
 }
 
 func NewEgressDNS(addressSetFactory addressset.AddressSetFactory, controllerName string,
-	controllerStop <-chan struct{}, defaultInterval time.Duration) (*EgressDNS, error) {
+	controllerStop <-chan struct{}, defaultInterval time.Duration, podLister corelisters.PodLister) (*EgressDNS, error) {
 	dnsInfo, err := util.NewDNS("/etc/resolv.conf")
 	if err != nil {
 		return nil, err
@@ -75,6 +93,7 @@ func NewEgressDNS(addressSetFactory addressset.AddressSetFactory, controllerName
 		addressSetFactory: addressSetFactory,
 		controllerName:    controllerName,
 		defaultInterval:   defaultInterval,
+		podLister:         podLister,
 
 		added:          make(chan struct{}, 1),
 		deleted:        make(chan string, 1),
@@ -103,7 +122,12 @@ func (e *EgressDNS) Add(namespace, dnsName string) (addressset.AddressSet, error
 			return nil, fmt.Errorf("cannot create addressSet for %s: %v", dnsName, err)
 		}
 		e.dnsEntries[dnsName] = &dnsEntry
-		go e.addToDNS(dnsName)
+		if util.IsWildcard(dnsName) {
+			// wildcard DNS names can't be resolved, the IPs of the learned names are added instead
+			dnsEntry.learnedNames = make(map[string]time.Time)
+		} else {
+			go e.addToDNS(dnsName)
+		}
 	}
 	e.dnsEntries[dnsName].namespaces[namespace] = struct{}{}
 	return e.dnsEntries[dnsName].dnsAddressSet, nil
@@ -127,10 +151,28 @@ func (e *EgressDNS) delete(namespace string) ([]string, error) {
 			}
 			// the dnsEntry is no longer needed because nothing references it, so delete it
 			delete(e.dnsEntries, dnsName)
-			dnsNamesToDelete = append(dnsNamesToDelete, dnsName)
+			if dnsEntry.learnedNames != nil {
+				for learnedName := range dnsEntry.learnedNames {
+					dnsNamesToDelete = append(dnsNamesToDelete, learnedName)
+				}
+			} else {
+				dnsNamesToDelete = append(dnsNamesToDelete, dnsName)
+			}
+		}
+	}
+	return e.getUnusedDNSNames(dnsNamesToDelete), nil
+}
+
+// getUnusedDNSNames returns the given DNS names that are neither used in a rule nor learned for a
+// wildcard DNS name, since a DNS name can be both. Must be called with e.lock held.
+func (e *EgressDNS) getUnusedDNSNames(dnsNames []string) []string {
+	unusedDNSNames := make([]string, 0, len(dnsNames))
+	for _, dnsName := range dnsNames {
+		if _, used := e.dnsEntries[dnsName]; !used && e.getLearningEntry(dnsName) == nil {
+			unusedDNSNames = append(unusedDNSNames, dnsName)
 		}
 	}
-	return dnsNamesToDelete, nil
+	return unusedDNSNames
 }
 
 func (e *EgressDNS) Delete(namespace string) error {
@@ -152,13 +194,44 @@ func (e *EgressDNS) updateEntryForName(dnsName string) error {
 	e.lock.Lock()
 	defer e.lock.Unlock()
 	ips := e.dns.GetIPs(dnsName)
-	if _, ok := e.dnsEntries[dnsName]; !ok {
+	entry, ok := e.dnsEntries[dnsName]
+	learningEntry := e.getLearningEntry(dnsName)
+	if !ok && learningEntry == nil {
 		return fmt.Errorf("cannot update DNS record for %s: no entry found. "+
 			"Was the EgressFirewall deleted?", dnsName)
 	}
-	e.dnsEntries[dnsName].dnsResolves = ips
+	if ok {
+		entry.dnsResolves = ips
+		if err := entry.dnsAddressSet.SetAddresses(util.StringSlice(filterClusterSubnetIPs(ips))); err != nil {
+			return fmt.Errorf("cannot add IPs from EgressFirewall AddressSet %s: %v", dnsName, err)
+		}
+	}
+	if learningEntry != nil {
+		return e.updateLearningEntry(wildcardDNSName(dnsName), learningEntry)
+	}
+	return nil
+}
 
-	// ignore ips from clusterSubnet, since this subnet shouldn't be affected by egress firewall
+// updateLearningEntry sets the wildcard DNS name address set to the IPs of all the learned names.
+// Must be called with e.lock held.
+func (e *EgressDNS) updateLearningEntry(dnsName string, entry *dnsEntry) error {
+	wildcardIPs := sets.New[string]()
+	for learnedName := range entry.learnedNames {
+		wildcardIPs.Insert(util.StringSlice(e.dns.GetIPs(learnedName))...)
+	}
+	entry.dnsResolves = make([]net.IP, 0, wildcardIPs.Len())
+	for _, ip := range sets.List(wildcardIPs) {
+		entry.dnsResolves = append(entry.dnsResolves, net.ParseIP(ip))
+	}
+	if err := entry.dnsAddressSet.SetAddresses(util.StringSlice(filterClusterSubnetIPs(entry.dnsResolves))); err != nil {
+		return fmt.Errorf("cannot add IPs from EgressFirewall AddressSet %s: %v", dnsName, err)
+	}
+	return nil
+}
+
+// filterClusterSubnetIPs returns the ips that are not in the cluster subnets, since the cluster subnets
+// shouldn't be affected by egress firewall.
+func filterClusterSubnetIPs(ips []net.IP) []net.IP {
 	ipsNoClusterSubnet := []net.IP{}
 	for _, ip := range ips {
 		fromClusterSubnet := false
@@ -173,10 +246,101 @@ func (e *EgressDNS) updateEntryForName(dnsName string) error {
 			ipsNoClusterSubnet = append(ipsNoClusterSubnet, ip)
 		}
 	}
-	if err := e.dnsEntries[dnsName].dnsAddressSet.SetAddresses(util.StringSlice(ipsNoClusterSubnet)); err != nil {
-		return fmt.Errorf("cannot add IPs from EgressFirewall AddressSet %s: %v", dnsName, err)
+	return ipsNoClusterSubnet
+}
+
+// wildcardDNSName returns the wildcard DNS name that matches the given lower case fully qualified DNS name.
+// As with DNSNameResolver, a wildcard DNS name only matches the names with one more label, e.g.
+// *.example.com. matches www.example.com. but not www.test.example.com.
+func wildcardDNSName(dnsName string) string {
+	_, parent, found := strings.Cut(dnsName, ".")
+	if !found || parent == "" {
+		return ""
+	}
+	return "*." + parent
+}
+
+// getLearningEntry returns the entry of the wildcard DNS name that learned the given DNS name, if any.
+// Must be called with e.lock held.
+func (e *EgressDNS) getLearningEntry(dnsName string) *dnsEntry {
+	entry, ok := e.dnsEntries[wildcardDNSName(dnsName)]
+	if !ok {
+		return nil
+	}
+	if _, learned := entry.learnedNames[dnsName]; !learned {
+		return nil
+	}
+	return entry
+}
+
+// learnDNSName adds a DNS name from a DNS response sent to a pod, if it matches a wildcard DNS name used in
+// EgressFirewall rules. The learned name is resolved and refreshed like any other DNS name, and its IPs are
+// added to the wildcard DNS name address set. Before learning a new name, the learned names that were not
+// seen for learnedDNSNameIdleTimeout are evicted, as is the least recently seen one if there are already
+// maxLearnedDNSNames.
+func (e *EgressDNS) learnDNSName(dnsName string) {
+	dnsName = util.LowerCaseFQDN(dnsName)
+	wildcardName := wildcardDNSName(dnsName)
+	now := time.Now()
+	e.lock.Lock()
+	entry, ok := e.dnsEntries[wildcardName]
+	if !ok || entry.learnedNames == nil {
+		e.lock.Unlock()
+		return
+	}
+	if _, learned := entry.learnedNames[dnsName]; learned {
+		entry.learnedNames[dnsName] = now
+		e.lock.Unlock()
+		return
+	}
+	evictedNames := evictLearnedDNSNames(entry.learnedNames, now)
+	entry.learnedNames[dnsName] = now
+	var err error
+	if len(evictedNames) > 0 {
+		klog.V(5).Infof("Evicted learned DNS names %v of %s", evictedNames, wildcardName)
+		err = e.updateLearningEntry(wildcardName, entry)
+	}
+	unusedDNSNames := e.getUnusedDNSNames(evictedNames)
+	e.lock.Unlock()
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+	for _, name := range unusedDNSNames {
+		go e.deleteFromDNS(name)
+	}
+	go e.addToDNS(dnsName)
+}
+
+// evictLearnedDNSNames removes the learned names that were not seen for learnedDNSNameIdleTimeout and, if
+// there is still no room for a new one, the least recently seen name. It returns the removed names.
+func evictLearnedDNSNames(learnedNames map[string]time.Time, now time.Time) []string {
+	var evictedNames []string
+	var leastRecentlySeenName string
+	for learnedName, lastSeen := range learnedNames {
+		if now.Sub(lastSeen) > learnedDNSNameIdleTimeout {
+			delete(learnedNames, learnedName)
+			evictedNames = append(evictedNames, learnedName)
+			continue
+		}
+		if leastRecentlySeenName == "" || lastSeen.Before(learnedNames[leastRecentlySeenName]) {
+			leastRecentlySeenName = learnedName
+		}
+	}
+	if len(learnedNames) >= maxLearnedDNSNames {
+		delete(learnedNames, leastRecentlySeenName)
+		evictedNames = append(evictedNames, leastRecentlySeenName)
+	}
+	return evictedNames
+}
+
+// forgetLearnedDNSName removes a learned DNS name that couldn't be resolved, so that it is learned
+// again from the next DNS response.
+func (e *EgressDNS) forgetLearnedDNSName(dnsName string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if entry := e.getLearningEntry(dnsName); entry != nil {
+		delete(entry.learnedNames, dnsName)
 	}
-	return nil
 }
 
 // addToDNS takes the dnsName adds it to the underlying dns resolver and
@@ -186,6 +350,7 @@ func (e *EgressDNS) updateEntryForName(dnsName string) error {
 func (e *EgressDNS) addToDNS(dnsName string) {
 	if err := e.dns.Add(dnsName); err != nil {
 		utilruntime.HandleError(err)
+		e.forgetLearnedDNSName(dnsName)
 	}
 	if err := e.updateEntryForName(dnsName); err != nil {
 		utilruntime.HandleError(err)
@@ -213,7 +378,15 @@ func (e *EgressDNS) deleteFromDNS(dnsName string) {
 //     and the durationTillNextQuery is updated
 //  2. e.added is received and durationTillNextQuery is recomputed
 //  3. e.deleted is received and coincides with dnsName
+//
+// If a DNS learning address is configured, it also starts learning the DNS names matching wildcard DNS names
+// from the DNS responses sent to pods.
 func (e *EgressDNS) Run() error {
+	if address := config.OVNKubernetesFeature.EgressFirewallDNSLearningAddress; address != "" {
+		if err := e.runDNSLearner(address); err != nil {
+			return err
+		}
+	}
 	var domainNameExpiringNext, domainNameDeleted string
 	var ttl time.Time
 	var timeSet bool
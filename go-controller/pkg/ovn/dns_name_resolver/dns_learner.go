@@ -0,0 +1,345 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnsnameresolver
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/miekg/dns"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+)
+
+// The DNS responses are received as dnstap messages over a Frame Streams connection, as sent by the CoreDNS
+// dnstap plugin. See https://dnstap.info and https://github.com/farsightsec/fstrm/blob/master/fstrm/control.h.
+const (
+	fstrmControlAccept = 0x01
+	fstrmControlStart  = 0x02
+	fstrmControlStop   = 0x03
+	fstrmControlReady  = 0x04
+	fstrmControlFinish = 0x05
+
+	fstrmControlFieldContentType = 0x01
+
+	fstrmMaxControlFrameSize = 512
+	// dnstap messages hold at most one query and one response
+	fstrmMaxDataFrameSize = 4 * dns.MaxMsgSize
+
+	dnstapContentType = "protobuf:dnstap.Dnstap"
+
+	// protobuf field numbers of Dnstap.message and Message.response_message,
+	// see https://github.com/dnstap/dnstap.pb/blob/master/dnstap.proto
+	dnstapMessageField         protowire.Number = 14
+	dnstapResponseMessageField protowire.Number = 14
+)
+
+// parseDNSLearningAddress returns the network and address to listen on for the given tcp://host:port or
+// unix:///path address.
+func parseDNSLearningAddress(address string) (string, string, error) {
+	network, addr, found := strings.Cut(address, "://")
+	if !found || addr == "" || (network != "tcp" && network != "unix") {
+		return "", "", fmt.Errorf("invalid DNS learning address %q, expected tcp://host:port or unix:///path", address)
+	}
+	return network, addr, nil
+}
+
+// parseDNSLearningPeers returns the namespace and label selector of the given namespace/selector cluster DNS pods.
+func parseDNSLearningPeers(peers string) (string, labels.Selector, error) {
+	namespace, selector, found := strings.Cut(peers, "/")
+	if !found || namespace == "" || selector == "" {
+		return "", nil, fmt.Errorf("invalid DNS learning peers %q, expected namespace/selector", peers)
+	}
+	labelSelector, err := labels.Parse(selector)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid DNS learning peers %q: %w", peers, err)
+	}
+	return namespace, labelSelector, nil
+}
+
+// isDNSLearningPeer returns whether the given remote address is an IP of a cluster DNS pod in the given
+// namespace matching the given selector.
+func (e *EgressDNS) isDNSLearningPeer(namespace string, selector labels.Selector, addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	pods, err := e.podLister.Pods(namespace).List(selector)
+	if err != nil {
+		klog.Warningf("Failed to list the DNS learning peer pods in namespace %s: %v", namespace, err)
+		return false
+	}
+	for _, pod := range pods {
+		for _, podIP := range pod.Status.PodIPs {
+			if tcpAddr.IP.Equal(net.ParseIP(podIP.IP)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runDNSLearner listens on the given tcp://host:port or unix:///path address for dnstap messages with the DNS
+// responses sent to pods, and learns the names matching the wildcard DNS names used in EgressFirewall rules.
+// The messages are sent by the dnstap plugin of the cluster DNS, e.g. CoreDNS. The tcp connections are only
+// accepted from the IPs of the cluster DNS pods selected by EgressFirewallDNSLearningPeers, the access to a
+// unix socket is left to its file permissions.
+func (e *EgressDNS) runDNSLearner(address string) error {
+	network, addr, err := parseDNSLearningAddress(address)
+	if err != nil {
+		return err
+	}
+	var peersNamespace string
+	var peersSelector labels.Selector
+	if network == "tcp" {
+		if e.podLister == nil {
+			return fmt.Errorf("cannot restrict the peers of DNS learning address %s without a pod lister", address)
+		}
+		peersNamespace, peersSelector, err = parseDNSLearningPeers(config.OVNKubernetesFeature.EgressFirewallDNSLearningPeers)
+		if err != nil {
+			return err
+		}
+	}
+	if network == "unix" {
+		if err := os.Remove(addr); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale DNS learning socket %s: %w", addr, err)
+		}
+	}
+	listener, err := net.Listen(network, addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on DNS learning address %s: %w", address, err)
+	}
+	go func() {
+		e.waitForStop()
+		listener.Close()
+	}()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+				klog.Warningf("Failed to accept connection on DNS learning address %s: %v", address, err)
+				continue
+			}
+			if network == "tcp" && !e.isDNSLearningPeer(peersNamespace, peersSelector, conn.RemoteAddr()) {
+				klog.Warningf("Rejecting connection on DNS learning address %s from %s, not a cluster DNS pod %s",
+					address, conn.RemoteAddr(), config.OVNKubernetesFeature.EgressFirewallDNSLearningPeers)
+				conn.Close()
+				continue
+			}
+			go e.handleDNSTapConn(conn)
+		}
+	}()
+	klog.Infof("Learning wildcard EgressFirewall DNS names from dnstap messages received on %s", address)
+	return nil
+}
+
+func (e *EgressDNS) waitForStop() {
+	select {
+	case <-e.stopChan:
+	case <-e.controllerStop:
+	}
+}
+
+// handleDNSTapConn reads the Frame Streams of a dnstap sender until it stops or the connection is closed.
+// Both bidirectional senders, that start with a READY frame, and unidirectional senders are supported.
+func (e *EgressDNS) handleDNSTapConn(conn net.Conn) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-done:
+		case <-e.stopChan:
+		case <-e.controllerStop:
+		}
+		conn.Close()
+	}()
+	reader := bufio.NewReader(conn)
+	for {
+		frame, err := readFstrmFrame(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				klog.V(5).Infof("Closing dnstap connection from %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		if !frame.control {
+			for _, dnsName := range getDNSTapResolvedDNSNames(frame.data) {
+				e.learnDNSName(dnsName)
+			}
+			continue
+		}
+		switch frame.controlType {
+		case fstrmControlReady:
+			if !frame.hasContentType(dnstapContentType) {
+				klog.V(5).Infof("Closing dnstap connection from %s: unsupported content types %q",
+					conn.RemoteAddr(), frame.contentTypes)
+				return
+			}
+			err = writeFstrmControlFrame(conn, fstrmControlAccept, dnstapContentType)
+		case fstrmControlStop:
+			// unidirectional senders don't wait for FINISH, but don't mind it either
+			_ = writeFstrmControlFrame(conn, fstrmControlFinish, "")
+			return
+		}
+		if err != nil {
+			klog.V(5).Infof("Closing dnstap connection from %s: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+// fstrmFrame is a Frame Streams data or control frame.
+type fstrmFrame struct {
+	control      bool
+	data         []byte
+	controlType  uint32
+	contentTypes []string
+}
+
+func (f *fstrmFrame) hasContentType(contentType string) bool {
+	// a READY frame without content types accepts any content type
+	if len(f.contentTypes) == 0 {
+		return true
+	}
+	for _, ct := range f.contentTypes {
+		if ct == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// readFstrmFrame reads the next Frame Streams frame. Data frames are prefixed by their length, control frames by
+// a zero length and the length of the control frame.
+func readFstrmFrame(r io.Reader) (*fstrmFrame, error) {
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	if length != 0 {
+		if length > fstrmMaxDataFrameSize {
+			return nil, fmt.Errorf("data frame of %d bytes exceeds the maximum size", length)
+		}
+		frame := &fstrmFrame{data: make([]byte, length)}
+		if _, err := io.ReadFull(r, frame.data); err != nil {
+			return nil, err
+		}
+		return frame, nil
+	}
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	if length < 4 || length > fstrmMaxControlFrameSize {
+		return nil, fmt.Errorf("invalid control frame size %d", length)
+	}
+	buf := make([]byte, length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	frame := &fstrmFrame{control: true, controlType: binary.BigEndian.Uint32(buf)}
+	for buf = buf[4:]; len(buf) > 0; {
+		if len(buf) < 8 {
+			return nil, fmt.Errorf("truncated control frame field")
+		}
+		fieldType, fieldLength := binary.BigEndian.Uint32(buf), binary.BigEndian.Uint32(buf[4:])
+		buf = buf[8:]
+		if uint32(len(buf)) < fieldLength {
+			return nil, fmt.Errorf("truncated control frame field")
+		}
+		if fieldType == fstrmControlFieldContentType {
+			frame.contentTypes = append(frame.contentTypes, string(buf[:fieldLength]))
+		}
+		buf = buf[fieldLength:]
+	}
+	return frame, nil
+}
+
+// writeFstrmControlFrame writes a control frame of the given type, with the content type if not empty.
+func writeFstrmControlFrame(w io.Writer, controlType uint32, contentType string) error {
+	control := binary.BigEndian.AppendUint32(nil, controlType)
+	if contentType != "" {
+		control = binary.BigEndian.AppendUint32(control, fstrmControlFieldContentType)
+		control = binary.BigEndian.AppendUint32(control, uint32(len(contentType)))
+		control = append(control, contentType...)
+	}
+	frame := binary.BigEndian.AppendUint32(nil, 0)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(control)))
+	_, err := w.Write(append(frame, control...))
+	return err
+}
+
+// getDNSTapResolvedDNSNames returns the resolved names of the DNS response in the given dnstap message.
+// Messages without a response, e.g. if the dnstap plugin doesn't send full messages, are ignored.
+func getDNSTapResolvedDNSNames(dnstap []byte) []string {
+	message := getProtobufBytesField(dnstap, dnstapMessageField)
+	response := getProtobufBytesField(message, dnstapResponseMessageField)
+	if response == nil {
+		return nil
+	}
+	msg := &dns.Msg{}
+	if err := msg.Unpack(response); err != nil {
+		klog.V(5).Infof("Ignoring invalid DNS response in dnstap message: %v", err)
+		return nil
+	}
+	return getResolvedDNSNames(msg)
+}
+
+// getProtobufBytesField returns the value of the given bytes field of a protobuf message, or nil if there is none.
+func getProtobufBytesField(message []byte, field protowire.Number) []byte {
+	for len(message) > 0 {
+		number, fieldType, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			return nil
+		}
+		message = message[n:]
+		if number == field && fieldType == protowire.BytesType {
+			value, n := protowire.ConsumeBytes(message)
+			if n < 0 {
+				return nil
+			}
+			return value
+		}
+		n = protowire.ConsumeFieldValue(number, fieldType, message)
+		if n < 0 {
+			return nil
+		}
+		message = message[n:]
+	}
+	return nil
+}
+
+// getResolvedDNSNames returns the queried names of a successful DNS response with A or AAAA records.
+func getResolvedDNSNames(msg *dns.Msg) []string {
+	if !msg.Response || msg.Rcode != dns.RcodeSuccess {
+		return nil
+	}
+	resolved := false
+	for _, rr := range msg.Answer {
+		if rr.Header().Rrtype == dns.TypeA || rr.Header().Rrtype == dns.TypeAAAA {
+			resolved = true
+			break
+		}
+	}
+	if !resolved {
+		return nil
+	}
+	dnsNames := make([]string, 0, len(msg.Question))
+	for _, question := range msg.Question {
+		dnsNames = append(dnsNames, question.Name)
+	}
+	return dnsNames
+}
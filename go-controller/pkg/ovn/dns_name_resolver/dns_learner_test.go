@@ -0,0 +1,351 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package dnsnameresolver
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"net"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/config"
+	addressset "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/ovn/address_set"
+	libovsdbtest "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/testing/libovsdb"
+	"github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util"
+	util_mocks "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/util/mocks"
+)
+
+func newDNSResponse(dnsName string, rcode int, answers ...dns.RR) *dns.Msg {
+	msg := &dns.Msg{}
+	msg.SetQuestion(dnsName, dns.TypeA)
+	msg.Response = true
+	msg.Rcode = rcode
+	msg.Answer = answers
+	return msg
+}
+
+func TestGetResolvedDNSNames(t *testing.T) {
+	tests := []struct {
+		desc     string
+		msg      *dns.Msg
+		expected []string
+	}{
+		{
+			desc:     "successful response",
+			msg:      newDNSResponse("www.example.com.", dns.RcodeSuccess, generateRR("www.example.com", "1.1.1.1", "30")),
+			expected: []string{"www.example.com."},
+		},
+		{
+			desc: "query",
+			msg: func() *dns.Msg {
+				msg := &dns.Msg{}
+				return msg.SetQuestion("www.example.com.", dns.TypeA)
+			}(),
+		},
+		{
+			desc: "NXDOMAIN response",
+			msg:  newDNSResponse("www.example.com.", dns.RcodeNameError),
+		},
+		{
+			desc: "response without addresses",
+			msg:  newDNSResponse("www.example.com.", dns.RcodeSuccess),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getResolvedDNSNames(tc.msg))
+		})
+	}
+}
+
+// newDNSTapMessage returns a dnstap message with the given DNS response, with the fields that the CoreDNS dnstap
+// plugin sets before and after it.
+func newDNSTapMessage(t *testing.T, response *dns.Msg) []byte {
+	packed, err := response.Pack()
+	require.NoError(t, err)
+	var message []byte
+	// type CLIENT_RESPONSE
+	message = protowire.AppendTag(message, 1, protowire.VarintType)
+	message = protowire.AppendVarint(message, 6)
+	message = protowire.AppendTag(message, 4, protowire.BytesType)
+	message = protowire.AppendBytes(message, net.ParseIP("10.244.0.3").To4())
+	message = protowire.AppendTag(message, dnstapResponseMessageField, protowire.BytesType)
+	message = protowire.AppendBytes(message, packed)
+	var dnstap []byte
+	dnstap = protowire.AppendTag(dnstap, 1, protowire.BytesType)
+	dnstap = protowire.AppendBytes(dnstap, []byte("coredns-1"))
+	dnstap = protowire.AppendTag(dnstap, dnstapMessageField, protowire.BytesType)
+	dnstap = protowire.AppendBytes(dnstap, message)
+	// type MESSAGE
+	dnstap = protowire.AppendTag(dnstap, 15, protowire.VarintType)
+	return protowire.AppendVarint(dnstap, 1)
+}
+
+func TestGetDNSTapResolvedDNSNames(t *testing.T) {
+	response := newDNSResponse("www.example.com.", dns.RcodeSuccess, generateRR("www.example.com", "1.1.1.1", "30"))
+	assert.Equal(t, []string{"www.example.com."}, getDNSTapResolvedDNSNames(newDNSTapMessage(t, response)))
+
+	// message without response, as sent by the dnstap plugin without the full option
+	var message, dnstap []byte
+	message = protowire.AppendTag(message, 1, protowire.VarintType)
+	message = protowire.AppendVarint(message, 6)
+	dnstap = protowire.AppendTag(dnstap, dnstapMessageField, protowire.BytesType)
+	dnstap = protowire.AppendBytes(dnstap, message)
+	assert.Empty(t, getDNSTapResolvedDNSNames(dnstap))
+
+	// malformed messages
+	full := newDNSTapMessage(t, response)
+	assert.Empty(t, getDNSTapResolvedDNSNames(full[:len(full)/2]))
+	assert.Empty(t, getDNSTapResolvedDNSNames([]byte{0xff, 0xff, 0xff}))
+	dnstap = protowire.AppendTag(nil, dnstapMessageField, protowire.BytesType)
+	dnstap = protowire.AppendBytes(dnstap, protowire.AppendBytes(protowire.AppendTag(nil, dnstapResponseMessageField, protowire.BytesType), []byte("invalid")))
+	assert.Empty(t, getDNSTapResolvedDNSNames(dnstap))
+}
+
+func TestParseDNSLearningAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		network string
+		addr    string
+		err     bool
+	}{
+		{address: "tcp://0.0.0.0:6000", network: "tcp", addr: "0.0.0.0:6000"},
+		{address: "tcp://[fd00::1]:6000", network: "tcp", addr: "[fd00::1]:6000"},
+		{address: "unix:///var/run/ovn-kubernetes/dnstap.sock", network: "unix", addr: "/var/run/ovn-kubernetes/dnstap.sock"},
+		{address: "/var/run/ovn-kubernetes/dnstap.sock", err: true},
+		{address: "udp://0.0.0.0:6000", err: true},
+		{address: "tcp://", err: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.address, func(t *testing.T) {
+			network, addr, err := parseDNSLearningAddress(tc.address)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.network, network)
+			assert.Equal(t, tc.addr, addr)
+		})
+	}
+}
+
+func TestWildcardDNSNameLearning(t *testing.T) {
+	require.NoError(t, config.PrepareTestConfig())
+	config.IPv4Mode = true
+	config.IPv6Mode = false
+	socketPath := filepath.Join(t.TempDir(), "dnstap.sock")
+	config.OVNKubernetesFeature.EgressFirewallDNSLearningAddress = "unix://" + socketPath
+	t.Cleanup(func() { config.OVNKubernetesFeature.EgressFirewallDNSLearningAddress = "" })
+
+	nbClient, _, cleanup, err := libovsdbtest.NewNBSBTestHarness(libovsdbtest.TestSetup{})
+	require.NoError(t, err)
+	t.Cleanup(cleanup.Cleanup)
+	addressSetFactory := addressset.NewOvnAddressSetFactory(nbClient, config.IPv4Mode, config.IPv6Mode)
+
+	resolvedIPs := map[string]string{
+		"www.example.com.": "1.1.1.1",
+		"api.example.com.": "2.2.2.2",
+	}
+	mockDnsOps := new(util_mocks.DNSOps)
+	util.SetDNSLibOpsMockInst(mockDnsOps)
+	mockDnsOps.On("ClientConfigFromFile", mock.Anything).Return(&dns.ClientConfig{Servers: []string{"1.1.1.1"}, Port: "53"}, nil)
+	mockDnsOps.On("Fqdn", mock.Anything).Return(func(s string) string { return dns.Fqdn(s) })
+	mockDnsOps.On("SetQuestion", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(msg *dns.Msg, z string, t uint16) *dns.Msg { return msg.SetQuestion(z, t) })
+	mockDnsOps.On("Exchange", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(_ *dns.Client, msg *dns.Msg, _ string) (*dns.Msg, time.Duration, error) {
+			dnsName := msg.Question[0].Name
+			return &dns.Msg{Answer: []dns.RR{generateRR(dnsName[:len(dnsName)-1], resolvedIPs[dnsName], "300")}}, 0, nil
+		})
+
+	stopChan := make(chan struct{})
+	t.Cleanup(func() { close(stopChan) })
+	egressDNS, err := NewEgressDNS(addressSetFactory, DefaultNetworkControllerName, stopChan, 5*time.Minute, nil)
+	require.NoError(t, err)
+	require.NoError(t, egressDNS.Run())
+
+	wildcardAddrSet, err := egressDNS.Add("namespace1", "*.example.com.")
+	require.NoError(t, err)
+
+	// bidirectional Frame Streams handshake, as done by the CoreDNS dnstap plugin
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	reader := bufio.NewReader(conn)
+	require.NoError(t, writeFstrmControlFrame(conn, fstrmControlReady, dnstapContentType))
+	frame, err := readFstrmFrame(reader)
+	require.NoError(t, err)
+	assert.Equal(t, &fstrmFrame{control: true, controlType: fstrmControlAccept, contentTypes: []string{dnstapContentType}}, frame)
+	require.NoError(t, writeFstrmControlFrame(conn, fstrmControlStart, dnstapContentType))
+	sendResponse := func(dnsName string) {
+		dnstap := newDNSTapMessage(t, newDNSResponse(dnsName, dns.RcodeSuccess, generateRR(dnsName[:len(dnsName)-1], "9.9.9.9", "30")))
+		_, err = conn.Write(append(binary.BigEndian.AppendUint32(nil, uint32(len(dnstap))), dnstap...))
+		require.NoError(t, err)
+	}
+	getAddresses := func() []string {
+		v4, _ := wildcardAddrSet.GetAddresses()
+		slices.Sort(v4)
+		return v4
+	}
+
+	// names matching the wildcard DNS name are learned and resolved by the EgressDNS
+	sendResponse("WWW.example.com.")
+	sendResponse("api.example.com.")
+	// names not matching the wildcard DNS name are ignored
+	sendResponse("www.test.example.com.")
+	sendResponse("www.example.org.")
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"1.1.1.1", "2.2.2.2"}, getAddresses())
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, egressDNS.dns.Size())
+
+	// the sender is done
+	require.NoError(t, writeFstrmControlFrame(conn, fstrmControlStop, ""))
+	frame, err = readFstrmFrame(reader)
+	require.NoError(t, err)
+	assert.Equal(t, &fstrmFrame{control: true, controlType: fstrmControlFinish}, frame)
+
+	// learned names are no longer resolved when the wildcard DNS name is deleted
+	require.NoError(t, egressDNS.Delete("namespace1"))
+	assert.Eventually(t, func() bool {
+		return egressDNS.dns.Size() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestDNSLearningPeers(t *testing.T) {
+	require.NoError(t, config.PrepareTestConfig())
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	config.OVNKubernetesFeature.EgressFirewallDNSLearningAddress = "tcp://" + address
+	t.Cleanup(func() { config.OVNKubernetesFeature.EgressFirewallDNSLearningAddress = "" })
+
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	stopChan := make(chan struct{})
+	t.Cleanup(func() { close(stopChan) })
+	egressDNS := &EgressDNS{
+		podLister:      corelisters.NewPodLister(podIndexer),
+		stopChan:       make(chan struct{}),
+		controllerStop: stopChan,
+	}
+	require.NoError(t, egressDNS.runDNSLearner(config.OVNKubernetesFeature.EgressFirewallDNSLearningAddress))
+
+	// the sender gets an ACCEPT frame if its connection is accepted, or nothing if it is closed
+	handshake := func() error {
+		conn, err := net.Dial("tcp", address)
+		require.NoError(t, err)
+		defer conn.Close()
+		require.NoError(t, writeFstrmControlFrame(conn, fstrmControlReady, dnstapContentType))
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		_, err = readFstrmFrame(bufio.NewReader(conn))
+		return err
+	}
+	newPod := func(namespace, ip string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns", Namespace: namespace, Labels: map[string]string{"k8s-app": "kube-dns"}},
+			Status:     corev1.PodStatus{PodIPs: []corev1.PodIP{{IP: ip}}},
+		}
+	}
+
+	// connections from other IPs than the cluster DNS pods are rejected
+	assert.Error(t, handshake())
+	require.NoError(t, podIndexer.Add(newPod("default", "127.0.0.1")))
+	assert.Error(t, handshake())
+	// connections from the cluster DNS pods are accepted
+	require.NoError(t, podIndexer.Add(newPod("kube-system", "127.0.0.1")))
+	assert.NoError(t, handshake())
+}
+
+func TestLearnedDNSNameEviction(t *testing.T) {
+	require.NoError(t, config.PrepareTestConfig())
+	config.IPv4Mode = true
+	config.IPv6Mode = false
+
+	nbClient, _, cleanup, err := libovsdbtest.NewNBSBTestHarness(libovsdbtest.TestSetup{})
+	require.NoError(t, err)
+	t.Cleanup(cleanup.Cleanup)
+	addressSetFactory := addressset.NewOvnAddressSetFactory(nbClient, config.IPv4Mode, config.IPv6Mode)
+
+	mockDnsOps := new(util_mocks.DNSOps)
+	util.SetDNSLibOpsMockInst(mockDnsOps)
+	mockDnsOps.On("ClientConfigFromFile", mock.Anything).Return(&dns.ClientConfig{Servers: []string{"1.1.1.1"}, Port: "53"}, nil)
+	mockDnsOps.On("Fqdn", mock.Anything).Return(func(s string) string { return dns.Fqdn(s) })
+	mockDnsOps.On("SetQuestion", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(msg *dns.Msg, z string, t uint16) *dns.Msg { return msg.SetQuestion(z, t) })
+	mockDnsOps.On("Exchange", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(_ *dns.Client, msg *dns.Msg, _ string) (*dns.Msg, time.Duration, error) {
+			dnsName := msg.Question[0].Name
+			return &dns.Msg{Answer: []dns.RR{generateRR(dnsName[:len(dnsName)-1], "1.1.1.1", "300")}}, 0, nil
+		})
+
+	stopChan := make(chan struct{})
+	t.Cleanup(func() { close(stopChan) })
+	egressDNS, err := NewEgressDNS(addressSetFactory, DefaultNetworkControllerName, stopChan, 5*time.Minute, nil)
+	require.NoError(t, err)
+	require.NoError(t, egressDNS.Run())
+	_, err = egressDNS.Add("namespace1", "*.example.com.")
+	require.NoError(t, err)
+	// the api.example.com. name is also used in a rule, it is still resolved when evicted
+	_, err = egressDNS.Add("namespace1", "api.example.com.")
+	require.NoError(t, err)
+
+	// one idle name and as many recently seen names as fit with it, api.example.com. being the least recently seen
+	now := time.Now()
+	egressDNS.lock.Lock()
+	learnedNames := egressDNS.dnsEntries["*.example.com."].learnedNames
+	learnedNames["idle.example.com."] = now.Add(-2 * learnedDNSNameIdleTimeout)
+	learnedNames["api.example.com."] = now.Add(-time.Duration(maxLearnedDNSNames) * time.Second)
+	for i := 0; i < maxLearnedDNSNames-2; i++ {
+		learnedNames[fmt.Sprintf("name%d.example.com.", i)] = now.Add(-time.Duration(i) * time.Second)
+	}
+	egressDNS.lock.Unlock()
+	getLearnedNames := func() map[string]time.Time {
+		egressDNS.lock.Lock()
+		defer egressDNS.lock.Unlock()
+		return maps.Clone(egressDNS.dnsEntries["*.example.com."].learnedNames)
+	}
+
+	// the idle name is evicted to learn a new name
+	egressDNS.learnDNSName("new1.example.com.")
+	assert.Len(t, getLearnedNames(), maxLearnedDNSNames)
+	assert.NotContains(t, getLearnedNames(), "idle.example.com.")
+	assert.Contains(t, getLearnedNames(), "new1.example.com.")
+
+	// a name seen again is refreshed, then the least recently seen name is evicted to learn a new name
+	egressDNS.learnDNSName("name253.example.com.")
+	egressDNS.learnDNSName("new2.example.com.")
+	assert.Len(t, getLearnedNames(), maxLearnedDNSNames)
+	assert.NotContains(t, getLearnedNames(), "api.example.com.")
+	assert.Contains(t, getLearnedNames(), "name253.example.com.")
+	assert.Contains(t, getLearnedNames(), "new2.example.com.")
+	egressDNS.learnDNSName("new3.example.com.")
+	assert.NotContains(t, getLearnedNames(), "name252.example.com.")
+	assert.Contains(t, getLearnedNames(), "name253.example.com.")
+
+	// the learned names are resolved, the evicted names that are not used in a rule are not
+	assert.Eventually(t, func() bool {
+		return egressDNS.dns.Size() == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	for _, dnsName := range []string{"api.example.com.", "new1.example.com.", "new2.example.com.", "new3.example.com."} {
+		assert.Equal(t, []net.IP{net.ParseIP("1.1.1.1")}, egressDNS.dns.GetIPs(dnsName), dnsName)
+	}
+}
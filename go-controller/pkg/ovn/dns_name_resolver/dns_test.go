@@ -69,7 +69,7 @@ func TestNewEgressDNS(t *testing.T) {
 				}
 				call.Once()
 			}
-			_, err := NewEgressDNS(testOvnAddFtry, DefaultNetworkControllerName, testCh, 0)
+			_, err := NewEgressDNS(testOvnAddFtry, DefaultNetworkControllerName, testCh, 0, nil)
 			//t.Log(res, err)
 			if tc.errExp {
 				require.Error(t, err)
@@ -372,7 +372,7 @@ func TestAdd(t *testing.T) {
 				}
 				call.Once()
 			}
-			res, err := NewEgressDNS(mockAddressSetFactoryOps, DefaultNetworkControllerName, testCh, tc.syncTime)
+			res, err := NewEgressDNS(mockAddressSetFactoryOps, DefaultNetworkControllerName, testCh, tc.syncTime, nil)
 			require.NoError(t, err)
 
 			err = res.Run()
@@ -527,7 +527,7 @@ func TestDelete(t *testing.T) {
 				}
 				call.Once()
 			}
-			res, err := NewEgressDNS(mockAddressSetFactoryOps, DefaultNetworkControllerName, testCh, tc.syncTime)
+			res, err := NewEgressDNS(mockAddressSetFactoryOps, DefaultNetworkControllerName, testCh, tc.syncTime, nil)
 			require.NoError(t, err)
 
 			err = res.Run()
@@ -238,7 +238,8 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations", func() {
 			setMockDnsOps()
 			setDNSMockServer()
 			fakeOVN.controller.dnsNameResolver, err = dnsnameresolver.NewEgressDNS(fakeOVN.controller.addressSetFactory,
-				fakeOVN.controller.controllerName, fakeOVN.controller.stopChan, egressFirewallDNSDefaultDuration)
+				fakeOVN.controller.controllerName, fakeOVN.controller.stopChan, egressFirewallDNSDefaultDuration,
+				fakeOVN.controller.watchFactory.PodCoreInformer().Lister())
 			gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		} else {
 			// Initialize the dnsNameResolver.
@@ -282,6 +283,7 @@ var _ = ginkgo.Describe("OVN EgressFirewall Operations", func() {
 			fakeOVN.controller.networkManager,
 			fakeOVN.controller.dnsNameResolver,
 			fakeOVN.controller.observManager,
+			fakeOVN.controller.aclStatsCollector,
 		)
 		gomega.Expect(err).NotTo(gomega.HaveOccurred())
 		err = fakeOVN.controller.efController.Start()
@@ -57,6 +57,10 @@ type gressPolicy struct {
 	// set to true for stateless network policies (stateless acls), otherwise set to false
 	isNetPolStateless bool
 
+	// set to true for dry-run network policies, their ACLs are created with a priority lower than the
+	// default deny ACLs and tagged as dry-run
+	isDryRun bool
+
 	// supported IP mode
 	ipv4Mode bool
 	ipv6Mode bool
@@ -232,13 +236,19 @@ func (gp *gressPolicy) buildLocalPodACLs(portGroupName string, aclLogging *libov
 	if gp.isNetPolStateless {
 		action = nbdb.ACLActionAllowStateless
 	}
+	buildACL := func(aclIDs *libovsdbops.DbObjectIDs, match string) *nbdb.ACL {
+		if gp.isDryRun {
+			return libovsdbutil.BuildDryRunACL(aclIDs, types.DryRunAllowPriority, match, action, action,
+				aclLogging, gp.aclPipeline, types.DefaultACLTier)
+		}
+		return libovsdbutil.BuildACLWithDefaultTier(aclIDs, types.DefaultAllowPriority, match, action,
+			aclLogging, gp.aclPipeline)
+	}
 	for protocol, l4Match := range libovsdbutil.GetL4MatchesFromNetworkPolicyPorts(gp.portPolicies) {
 		if len(gp.ipBlocks) > 0 {
 			// Add ACL allow rule for IPBlock CIDR
 			ipBlockMatch := gp.getMatchFromIPBlock(lportMatch, l4Match)
-			aclIDs := gp.getNetpolACLDbIDs(ipBlockCombinedIdx, protocol)
-			acl := libovsdbutil.BuildACLWithDefaultTier(aclIDs, types.DefaultAllowPriority, ipBlockMatch, action,
-				aclLogging, gp.aclPipeline)
+			acl := buildACL(gp.getNetpolACLDbIDs(ipBlockCombinedIdx, protocol), ipBlockMatch)
 			createdACLs = append(createdACLs, acl)
 		}
 		// if there are pod/namespace selector, then allow packets from/to that address_set or
@@ -256,9 +266,7 @@ func (gp *gressPolicy) buildLocalPodACLs(portGroupName string, aclLogging *libov
 			} else {
 				addrSetMatch = fmt.Sprintf("%s && %s && %s", l3Match, l4Match, lportMatch)
 			}
-			aclIDs := gp.getNetpolACLDbIDs(emptyIdx, protocol)
-			acl := libovsdbutil.BuildACLWithDefaultTier(aclIDs, types.DefaultAllowPriority, addrSetMatch, action,
-				aclLogging, gp.aclPipeline)
+			acl := buildACL(gp.getNetpolACLDbIDs(emptyIdx, protocol), addrSetMatch)
 			if l3Match == "" {
 				// if l3Match is empty, then no address sets are selected for a given gressPolicy.
 				// fortunately l3 match is not a part of externalIDs, that means that we can find
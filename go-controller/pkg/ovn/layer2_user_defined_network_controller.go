@@ -312,7 +312,7 @@ func NewLayer2UserDefinedNetworkController(
 	if util.IsNetworkSegmentationSupportEnabled() && netInfo.IsPrimaryNetwork() {
 		var err error
 		oc.svcController, err = svccontroller.NewController(
-			cnci.client, cnci.nbClient,
+			cnci.client, cnci.nbClient, nil,
 			cnci.watchFactory.ServiceCoreInformer(),
 			cnci.watchFactory.EndpointSliceCoreInformer(),
 			cnci.watchFactory.NodeCoreInformer(),
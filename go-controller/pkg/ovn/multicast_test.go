@@ -123,16 +123,21 @@ func getMulticastPolicyExpectedData(netInfo util.NetInfo, ns string, ports []str
 }
 
 func getMulticastPolicyExpectedDataWithPodIPs(netInfo util.NetInfo, ns string, ports, podIPs []string) []libovsdb.TestData {
+	return getMulticastPolicyExpectedDataWithGroups(netInfo, ns, ports, podIPs, &namespaceMulticastPolicy{})
+}
+
+func getMulticastPolicyExpectedDataWithGroups(netInfo util.NetInfo, ns string, ports, podIPs []string, policy *namespaceMulticastPolicy) []libovsdb.TestData {
 	netControllerName := getNetworkControllerName(netInfo.GetNetworkName())
 	fakeController := getFakeController(netControllerName)
 	pg_hash := fakeController.getNamespacePortGroupName(ns)
-	egressMatch := libovsdbutil.GetACLMatch(pg_hash, fakeController.getMulticastACLEgrMatch(), libovsdbutil.ACLEgress)
+	egressMatch := libovsdbutil.GetACLMatch(pg_hash, fakeController.getMulticastACLEgrMatch(policy), libovsdbutil.ACLEgress)
 
 	peerIndex := addresssetmanager.GetPodSelectorAddrSetDbIDs(&metav1.LabelSelector{}, nil, nil,
 		ns, netControllerName, true)
 	nsASv4, nsASv6 := addressset.GetTestDbAddrSets(peerIndex, podIPs)
 	ip4AddressSet, ip6AddressSet := addressset.GetHashNamesForAS(peerIndex)
-	mcastMatch := getACLMatchAF(getMulticastACLIgrMatchV4(ip4AddressSet), getMulticastACLIgrMatchV6(ip6AddressSet), config.IPv4Mode, config.IPv6Mode)
+	mcastMatch := getACLMatchAF(getMulticastACLIgrMatchV4(getMulticastSourceMatch("ip4.src", ip4AddressSet), policy),
+		getMulticastACLIgrMatchV6(getMulticastSourceMatch("ip6.src", ip6AddressSet), policy), config.IPv4Mode, config.IPv6Mode)
 	ingressMatch := libovsdbutil.GetACLMatch(pg_hash, mcastMatch, libovsdbutil.ACLIngress)
 
 	aclIDs := getNamespaceMcastACLDbIDs(ns, libovsdbutil.ACLEgress, netControllerName)
@@ -590,6 +595,68 @@ var _ = Describe("OVN Multicast with IP Address Family", func() {
 			Entry("[Network Segmentation] IPv4", true, false, nadFromIPMode(namespaceName1, true, false)),
 			Entry("[Network Segmentation] IPv6", false, true, nadFromIPMode(namespaceName1, false, true)),
 		)
+
+		DescribeTable("updates the snooping options of existing switches", func(useIPv4, useIPv6 bool, nad *nadapi.NetworkAttachmentDefinition) {
+			app.Action = func(*cli.Context) error {
+				config.IPv4Mode = useIPv4
+				config.IPv6Mode = useIPv6
+				config.OVNKubernetesFeature.MulticastIdleTimeout = 600
+				config.OVNKubernetesFeature.MulticastQueryInterval = 120
+
+				netInfo := getNetInfoFromNAD(nad)
+				externalIDs := util.GenerateExternalIDsForSwitchOrRouter(netInfo)
+				if len(externalIDs) == 0 {
+					// empty maps are read back as nil
+					externalIDs = nil
+				}
+				staleSwitch := &nbdb.LogicalSwitch{
+					UUID:        "node1_UUID",
+					Name:        netInfo.GetNetworkScopedSwitchName("node1"),
+					ExternalIDs: externalIDs,
+					OtherConfig: map[string]string{
+						"subnet":             "10.128.1.0/24",
+						"mcast_snoop":        "true",
+						"mcast_idle_timeout": "300",
+						"mcast_table_size":   "2048",
+					},
+				}
+				// snooping is not enabled on this switch
+				otherSwitch := &nbdb.LogicalSwitch{
+					UUID:        "node2_UUID",
+					Name:        netInfo.GetNetworkScopedSwitchName("node2"),
+					ExternalIDs: externalIDs,
+					OtherConfig: map[string]string{"subnet": "10.128.2.0/24"},
+				}
+				// the switch of another network
+				otherNetworkSwitch := &nbdb.LogicalSwitch{
+					UUID:        "othernet_node1_UUID",
+					Name:        "othernet_node1",
+					ExternalIDs: map[string]string{types.NetworkExternalID: "othernet"},
+					OtherConfig: map[string]string{"mcast_snoop": "true", "mcast_idle_timeout": "300"},
+				}
+				fakeOvn.startWithDBSetup(libovsdb.TestSetup{
+					NBData: []libovsdb.TestData{staleSwitch.DeepCopy(), otherSwitch, otherNetworkSwitch},
+				})
+				bnc := startBaseNetworkController(fakeOvn, nad)
+
+				Expect(bnc.syncMulticastSnoopOptions()).To(Succeed())
+				staleSwitch.OtherConfig = map[string]string{
+					"subnet":               "10.128.1.0/24",
+					"mcast_snoop":          "true",
+					"mcast_idle_timeout":   "600",
+					"mcast_query_interval": "120",
+				}
+				Eventually(fakeOvn.nbClient).Should(libovsdb.HaveData(
+					[]libovsdb.TestData{staleSwitch, otherSwitch, otherNetworkSwitch}))
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			Expect(err).NotTo(HaveOccurred())
+		},
+			Entry("IPv4", true, false, nil),
+			Entry("[Network Segmentation] IPv4", true, false, nadFromIPMode(namespaceName1, true, false)),
+		)
 	})
 
 	Context("during execution", func() {
@@ -653,6 +720,74 @@ var _ = Describe("OVN Multicast with IP Address Family", func() {
 			Entry("[Network Segmentation] IPv6", false, true, nadFromIPMode(namespaceName1, false, true)),
 		)
 
+		DescribeTable("tests restricting the multicast groups of a namespace", func(useIPv4, useIPv6 bool, nad *nadapi.NetworkAttachmentDefinition) {
+			app.Action = func(*cli.Context) error {
+				config.IPv4Mode = useIPv4
+				config.IPv6Mode = useIPv6
+
+				netInfo := getNetInfoFromNAD(nad)
+				namespace1 := *ovntest.NewNamespace(namespaceName1)
+
+				objs := []runtime.Object{&corev1.NamespaceList{
+					Items: []corev1.Namespace{
+						namespace1,
+					},
+				}}
+				if nad != nil {
+					objs = append(objs, &nadapi.NetworkAttachmentDefinitionList{
+						Items: []nadapi.NetworkAttachmentDefinition{*nad},
+					})
+				}
+
+				fakeOvn.startWithDBSetup(libovsdb.TestSetup{}, objs...)
+
+				if nad != nil {
+					Expect(fakeOvn.networkManager.Start()).To(Succeed())
+					defer fakeOvn.networkManager.Stop()
+				}
+
+				bnc := startBaseNetworkController(fakeOvn, nad)
+				Expect(bnc.WatchNamespaces()).To(Succeed())
+
+				ns, err := fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Get(context.TODO(), namespace1.Name, metav1.GetOptions{})
+				Expect(err).To(Succeed())
+				Expect(ns).NotTo(BeNil())
+
+				updateMulticast(fakeOvn, ns, true)
+				expectedData := getMulticastPolicyExpectedData(netInfo, namespace1.Name, nil)
+				Eventually(fakeOvn.nbClient).Should(libovsdb.HaveData(expectedData...))
+
+				// Restrict the multicast groups, the ACLs are updated.
+				ns.Annotations[util.NsMulticastAllowedGroupsAnnotation] = "239.1.1.0/24, ff3e::8000:1"
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Update(context.TODO(), ns, metav1.UpdateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				policy := &namespaceMulticastPolicy{
+					restrictGroups: true,
+					groupsV4:       []string{"239.1.1.0/24"},
+					groupsV6:       []string{"ff3e::8000:1"},
+				}
+				expectedData = getMulticastPolicyExpectedDataWithGroups(netInfo, namespace1.Name, nil, nil, policy)
+				Eventually(fakeOvn.nbClient).Should(libovsdb.HaveData(expectedData...))
+
+				// Invalid groups deny multicast in the namespace.
+				ns.Annotations[util.NsMulticastAllowedGroupsAnnotation] = "10.0.0.1"
+				_, err = fakeOvn.fakeClient.KubeClient.CoreV1().Namespaces().Update(context.TODO(), ns, metav1.UpdateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				namespacePortGroup := getNamespacePG(namespaceName1, bnc.controllerName)
+				Eventually(fakeOvn.nbClient).Should(libovsdb.HaveData(namespacePortGroup))
+
+				return nil
+			}
+
+			err := app.Run([]string{app.Name})
+			Expect(err).NotTo(HaveOccurred())
+		},
+			Entry("IPv4", true, false, nil),
+			Entry("IPv6", false, true, nil),
+			Entry("[Network Segmentation] IPv4", true, false, nadFromIPMode(namespaceName1, true, false)),
+			Entry("[Network Segmentation] IPv6", false, true, nadFromIPMode(namespaceName1, false, true)),
+		)
+
 		DescribeTable("tests enabling multicast in a namespace with a pod", func(useIPv4, useIPv6 bool, nad *nadapi.NetworkAttachmentDefinition) {
 			app.Action = func(*cli.Context) error {
 				config.IPv4Mode = useIPv4
@@ -913,3 +1048,79 @@ var _ = Describe("OVN Multicast with IP Address Family", func() {
 		)
 	})
 })
+
+var _ = Describe("OVN Multicast namespace policy", func() {
+	BeforeEach(func() {
+		Expect(config.PrepareTestConfig()).To(Succeed())
+	})
+
+	It("sets the configured IGMP/MLD snooping options", func() {
+		otherConfig := map[string]string{}
+		setMulticastSnoopOptions(otherConfig)
+		Expect(otherConfig).To(Equal(map[string]string{"mcast_snoop": "true"}))
+
+		config.OVNKubernetesFeature.MulticastIdleTimeout = 600
+		config.OVNKubernetesFeature.MulticastQueryInterval = 120
+		config.OVNKubernetesFeature.MulticastTableSize = 4096
+		config.OVNKubernetesFeature.MulticastFloodUnregistered = true
+		otherConfig = map[string]string{}
+		setMulticastSnoopOptions(otherConfig)
+		Expect(otherConfig).To(Equal(map[string]string{
+			"mcast_snoop":              "true",
+			"mcast_idle_timeout":       "600",
+			"mcast_query_interval":     "120",
+			"mcast_table_size":         "4096",
+			"mcast_flood_unregistered": "true",
+		}))
+	})
+
+	DescribeTable("parses the namespace multicast annotations", func(annotations map[string]string, expected *namespaceMulticastPolicy, expectErr bool) {
+		policy, err := parseNamespaceMulticastPolicy(annotations)
+		if expectErr {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(expected))
+	},
+		Entry("no annotations", map[string]string{}, &namespaceMulticastPolicy{}, false),
+		Entry("allowed groups", map[string]string{
+			util.NsMulticastAllowedGroupsAnnotation: "239.1.1.1, 239.2.0.0/16,ff3e::/16",
+		}, &namespaceMulticastPolicy{
+			restrictGroups: true,
+			groupsV4:       []string{"239.1.1.1", "239.2.0.0/16"},
+			groupsV6:       []string{"ff3e::/16"},
+		}, false),
+		Entry("empty allowed groups only allow IGMP/MLD", map[string]string{
+			util.NsMulticastAllowedGroupsAnnotation: "",
+		}, &namespaceMulticastPolicy{restrictGroups: true}, false),
+		Entry("unicast group", map[string]string{util.NsMulticastAllowedGroupsAnnotation: "10.0.0.1"}, nil, true),
+		Entry("CIDR larger than the multicast range", map[string]string{util.NsMulticastAllowedGroupsAnnotation: "224.0.0.0/3"}, nil, true),
+		Entry("invalid group", map[string]string{util.NsMulticastAllowedGroupsAnnotation: "239.1.1"}, nil, true),
+		Entry("source namespace selector", map[string]string{
+			util.NsMulticastSourceNamespaceSelectorAnnotation: "team=video,env in (prod)",
+		}, &namespaceMulticastPolicy{
+			sourceNamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "video"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"prod"}},
+				},
+			},
+		}, false),
+		Entry("invalid source namespace selector", map[string]string{
+			util.NsMulticastSourceNamespaceSelectorAnnotation: "team in (video",
+		}, nil, true),
+	)
+
+	It("builds the ingress match with the multicast source namespaces", func() {
+		srcMatch := getMulticastSourceMatch("ip4.src", "a1", "a2")
+		Expect(srcMatch).To(Equal("(ip4.src == $a1 || ip4.src == $a2)"))
+		Expect(getMulticastACLIgrMatchV4(srcMatch, &namespaceMulticastPolicy{})).To(
+			Equal("(igmp || ((ip4.src == $a1 || ip4.src == $a2) && ip4.mcast))"))
+		Expect(getMulticastACLIgrMatchV4(srcMatch, &namespaceMulticastPolicy{restrictGroups: true, groupsV4: []string{"239.1.1.1"}})).To(
+			Equal("(igmp || ((ip4.src == $a1 || ip4.src == $a2) && ip4.mcast && ip4.dst == 239.1.1.1))"))
+		Expect(getMulticastACLIgrMatchV6(getMulticastSourceMatch("ip6.src", "a1", ""),
+			&namespaceMulticastPolicy{restrictGroups: true, groupsV6: []string{"ff3e::1", "ff3e::2"}})).To(
+			Equal("(mldv1 || mldv2 || (ip6.src == $a1 && " + ipv6DynamicMulticastMatch + " && ip6.dst == {ff3e::1, ff3e::2}))"))
+	})
+})
@@ -475,6 +475,7 @@ func (oc *DefaultNetworkController) newANPController() error {
 		oc.zone,
 		oc.recorder,
 		oc.observManager,
+		oc.aclStatsCollector,
 	)
 	return err
 }
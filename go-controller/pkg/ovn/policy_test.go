@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilnet "k8s.io/utils/net"
+	"k8s.io/utils/ptr"
 
 	libovsdbclient "github.com/ovn-kubernetes/libovsdb/client"
 
@@ -219,6 +220,58 @@ func getDefaultDenyDataHelper(policyTypeIngress, policyTypeEgress bool, params *
 	return append(testData, egressDenyPG, ingressDenyPG)
 }
 
+func getDryRunData(params *netpolDataParams) []libovsdbtest.TestData {
+	namespace := params.networkPolicy.Namespace
+	fakeController := getFakeBaseController(params.netInfo)
+	policyTypeIngress, policyTypeEgress := getPolicyType(params.networkPolicy)
+	lsps := []*nbdb.LogicalSwitchPort{}
+	for _, uuid := range params.localPortUUIDs {
+		lsps = append(lsps, &nbdb.LogicalSwitchPort{UUID: uuid})
+	}
+	testData := []libovsdbtest.TestData{}
+	for _, aclDir := range []libovsdbutil.ACLDirection{libovsdbutil.ACLEgress, libovsdbutil.ACLIngress} {
+		direction, portDir, options := nbdb.ACLDirectionToLport, "outport", map[string]string(nil)
+		ports := lsps
+		if !policyTypeIngress {
+			ports = nil
+		}
+		if aclDir == libovsdbutil.ACLEgress {
+			direction, portDir, options = nbdb.ACLDirectionFromLport, "inport", map[string]string{"apply-after-lb": "true"}
+			ports = lsps
+			if !policyTypeEgress {
+				ports = nil
+			}
+		}
+		pgName := fakeController.dryRunPortGroupName(namespace, aclDir)
+		match := portDir + " == @" + pgName + " && !" + arpAllowPolicyMatch
+		if config.OVNKubernetesFeature.AllowICMPNetworkPolicy {
+			match += " && !" + icmpAllowPolicyMatch
+		}
+		aclIDs := fakeController.getDefaultDenyPolicyACLIDs(namespace, aclDir, dryRunDenyACL)
+		externalIDs := aclIDs.GetExternalIDs()
+		externalIDs[types.ACLDryRunActionExternalID] = nbdb.ACLActionDrop
+		wouldDenyACL := libovsdbops.BuildACL(
+			libovsdbutil.GetDryRunACLName(nbdb.ACLActionDrop, libovsdbutil.GetACLName(aclIDs)),
+			direction,
+			types.DryRunDenyPriority,
+			match,
+			nbdb.ACLActionAllowRelated,
+			types.OvnACLLoggingMeter,
+			params.denyLogSeverity,
+			params.denyLogSeverity != "",
+			externalIDs,
+			options,
+			types.DefaultACLTier,
+		)
+		wouldDenyACL.UUID = aclIDs.String() + "-UUID"
+		pg := libovsdbutil.BuildPortGroup(fakeController.getDryRunPolicyPortGroupIDs(namespace, aclDir), ports,
+			[]*nbdb.ACL{wouldDenyACL})
+		pg.UUID = pg.Name + "-UUID"
+		testData = append(testData, wouldDenyACL, pg)
+	}
+	return testData
+}
+
 func getDefaultDenyData(params *netpolDataParams) []libovsdbtest.TestData {
 	policyTypeIngress, policyTypeEgress := getPolicyType(params.networkPolicy)
 	return getDefaultDenyDataHelper(policyTypeIngress, policyTypeEgress, params)
@@ -383,6 +436,7 @@ type netpolDataParams struct {
 	allowLogSeverity nbdb.ACLSeverity
 	denyLogSeverity  nbdb.ACLSeverity
 	statelessNetPol  bool
+	dryRun           bool
 	netInfo          util.NetInfo
 }
 
@@ -423,6 +477,13 @@ func getPolicyData(params *netpolDataParams) []libovsdbtest.TestData {
 			}
 		}
 	}
+	if params.dryRun {
+		for _, acl := range acls {
+			acl.Priority = types.DryRunAllowPriority
+			acl.ExternalIDs[types.ACLDryRunActionExternalID] = acl.Action
+			acl.Name = ptr.To(libovsdbutil.GetDryRunACLName(acl.Action, *acl.Name))
+		}
+	}
 
 	pg := getPolicyPortGroup(params, acls)
 
@@ -498,6 +559,11 @@ func (p *netpolDataParams) withStateless(statelessNetPol bool) *netpolDataParams
 	return p
 }
 
+func (p *netpolDataParams) withDryRun(dryRun bool) *netpolDataParams {
+	p.dryRun = dryRun
+	return p
+}
+
 func (p *netpolDataParams) withNetInfo(netInfo util.NetInfo) *netpolDataParams {
 	p.netInfo = netInfo
 	return p
@@ -791,6 +857,28 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Operations", func() {
 			gomega.Expect(app.Run([]string{app.Name})).To(gomega.Succeed())
 		})
 
+		ginkgo.It("deletes stale dry-run port groups of namespaces without dry-run policies", func() {
+			app.Action = func(*cli.Context) error {
+				namespace1 := *ovntest.NewNamespace(namespaceName1)
+				namespace2 := *ovntest.NewNamespace(namespaceName2)
+				networkPolicy := ovntest.NewMatchLabelsNetworkPolicy(netPolicyName1, namespace1.Name,
+					namespace2.Name, "", true, true)
+				dataParams := newNetpolDataParams(networkPolicy)
+
+				// the policy was a dry-run policy before the restart, it is enforced now
+				initialData := append([]libovsdbtest.TestData{}, initialDB.NBData...)
+				initialData = append(initialData, getDryRunData(dataParams)...)
+				startOvn(libovsdbtest.TestSetup{NBData: initialData}, []corev1.Namespace{namespace1, namespace2},
+					[]knet.NetworkPolicy{*networkPolicy}, nil, nil)
+
+				expectedData := getNamespaceWithSinglePolicyExpectedData(dataParams, initialDB.NBData)
+				gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(expectedData))
+				return nil
+			}
+
+			gomega.Expect(app.Run([]string{app.Name})).To(gomega.Succeed())
+		})
+
 		ginkgo.DescribeTable("reconciles an existing networkPolicy with empty db",
 			func(allowICMPNetworkPolicy bool) {
 				app.Action = func(*cli.Context) error {
@@ -1233,6 +1321,46 @@ var _ = ginkgo.Describe("OVN NetworkPolicy Operations", func() {
 			gomega.Expect(app.Run([]string{app.Name})).To(gomega.Succeed())
 		})
 
+		ginkgo.It("correctly creates, enforces and deletes a dry-run networkpolicy", func() {
+			app.Action = func(*cli.Context) error {
+				namespace1 := *ovntest.NewNamespace(namespaceName1)
+				nPodTest := getTestPod(namespace1.Name, nodeName)
+				networkPolicy := getPortNetworkPolicy(netPolicyName1, namespace1.Name, labelName, labelVal, portNum)
+				networkPolicy.Annotations = map[string]string{types.PolicyDryRunAnnotation: "true"}
+				startOvn(initialDB, []corev1.Namespace{namespace1}, []knet.NetworkPolicy{*networkPolicy},
+					[]testPod{nPodTest}, map[string]string{labelName: labelVal})
+
+				ginkgo.By("Check dry-run networkPolicy doesn't isolate the local pod")
+				dataParams := newNetpolDataParams(networkPolicy).
+					withLocalPortUUIDs(nPodTest.portUUID).
+					withTCPPeerPorts(portNum).
+					withDryRun(true)
+				expectedData := getUpdatedInitialDB([]testPod{nPodTest})
+				expectedData = append(expectedData, getPolicyData(dataParams)...)
+				expectedData = append(expectedData, getDryRunData(dataParams)...)
+				gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(expectedData...))
+
+				ginkgo.By("Enforcing the network policy")
+				networkPolicy.Annotations = nil
+				_, err := fakeOvn.fakeClient.KubeClient.NetworkingV1().NetworkPolicies(networkPolicy.Namespace).
+					Update(context.TODO(), networkPolicy, metav1.UpdateOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				dataParams.withDryRun(false)
+				expectedData = getNamespaceWithSinglePolicyExpectedData(dataParams, getUpdatedInitialDB([]testPod{nPodTest}))
+				gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(expectedData...))
+
+				ginkgo.By("Deleting the network policy")
+				err = fakeOvn.fakeClient.KubeClient.NetworkingV1().NetworkPolicies(networkPolicy.Namespace).
+					Delete(context.TODO(), networkPolicy.Name, metav1.DeleteOptions{})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Eventually(fakeOvn.nbClient).Should(libovsdbtest.HaveData(getUpdatedInitialDB([]testPod{nPodTest})))
+
+				return nil
+			}
+
+			gomega.Expect(app.Run([]string{app.Name})).To(gomega.Succeed())
+		})
+
 		ginkgo.It("correctly retries creating a network policy allowing a port to a local pod", func() {
 			app.Action = func(*cli.Context) error {
 				namespace1 := *ovntest.NewNamespace(namespaceName1)
@@ -92,6 +92,11 @@ const (
 	DefaultAllowPriority = 1001
 	// Default deny acl rule priority
 	DefaultDenyPriority = 1000
+	// Allow acl rule priority of dry-run network policies, lower than DefaultDenyPriority so that
+	// dry-run policies never allow traffic denied by enforced network policies
+	DryRunAllowPriority = 999
+	// Would-deny acl rule priority of dry-run network policies
+	DryRunDenyPriority = 998
 	// Pass priority for isolated advertised networks
 	AdvertisedNetworkPassPriority = 1100
 	// Deny priority for isolated advertised networks
@@ -154,6 +159,33 @@ const (
 	UserDefinedNetworkEndpointSliceAnnotation = "k8s.ovn.org/endpointslice-network"
 	// LabelUserDefinedServiceName label key used in mirrored EndpointSlices that contains the service name matching the EndpointSlice
 	LabelUserDefinedServiceName = "k8s.ovn.org/service-name"
+	// ServiceHealthCheckAnnotation is the Service annotation that enables ("true") or disables ("false") the OVN
+	// load balancer health checks of the service backends, overriding the ovn-service-health-checks config
+	ServiceHealthCheckAnnotation = "k8s.ovn.org/service-health-check"
+	// ServiceLBSelectionFieldsAnnotation is the Service annotation that sets the packet fields (a comma separated
+	// list of ip_src, ip_dst, tp_src and tp_dst) hashed by the OVN load balancers to select the service backend
+	ServiceLBSelectionFieldsAnnotation = "k8s.ovn.org/service-lb-selection-fields"
+	// ServiceLBHashingAnnotation is the Service annotation that sets the OVN load balancer backend selection:
+	// "consistent" hashes the selection fields, the 5-tuple by default, with rendezvous hashing so that backend
+	// changes only move the connections of the added or removed backends
+	ServiceLBHashingAnnotation = "k8s.ovn.org/service-lb-hashing"
+	// ServiceLBHashingConsistent is the ServiceLBHashingAnnotation value for consistent hashing
+	ServiceLBHashingConsistent = "consistent"
+	// ServiceSessionAffinityModeAnnotation is the Service annotation that sets how the OVN load balancers implement
+	// the ClientIP session affinity of the service
+	ServiceSessionAffinityModeAnnotation = "k8s.ovn.org/session-affinity-mode"
+	// ServiceSessionAffinityModeClientHash is the ServiceSessionAffinityModeAnnotation value that selects the backend
+	// by hashing the client IP, on the first load balancer the traffic hits, so that a client gets the same backend
+	// through all the nodes and gateway routers without the affinity timeout
+	ServiceSessionAffinityModeClientHash = "client-hash"
+	// ServiceHairpinSNATAnnotation is the Service annotation that sets the source IP of the hairpin traffic, from a
+	// backend to itself through the service: "masquerade", the default, for the OVN service hairpin masquerade IP, or
+	// "vip" for the service vip the backend connected to
+	ServiceHairpinSNATAnnotation = "k8s.ovn.org/service-hairpin-snat"
+	// ServiceHairpinSNATMasquerade is the ServiceHairpinSNATAnnotation value for the OVN service hairpin masquerade IP
+	ServiceHairpinSNATMasquerade = "masquerade"
+	// ServiceHairpinSNATVIP is the ServiceHairpinSNATAnnotation value for the service vip
+	ServiceHairpinSNATVIP = "vip"
 
 	// Packet marking
 	EgressIPNodeConnectionMark         = "1008"
@@ -245,6 +277,13 @@ const (
 	LoadBalancerKindExternalID = OvnK8sPrefix + "/" + "kind"
 	// key for load_balancer service external-id
 	LoadBalancerOwnerExternalID = OvnK8sPrefix + "/" + "owner"
+	// key for ACL dry-run action external-id, set on ACLs of policies in dry-run mode to the action the
+	// ACL would have if the policy was enforced
+	ACLDryRunActionExternalID = OvnK8sPrefix + "/" + "dry-run-action"
+	// PolicyDryRunAnnotation is the NetworkPolicy, AdminNetworkPolicy and BaselineAdminNetworkPolicy annotation
+	// that, when set to "true", renders the policy ACLs without enforcing them, tagged with the action they would
+	// have so that their impact can be observed with ACL logging or sampling
+	PolicyDryRunAnnotation = "k8s.ovn.org/policy-dry-run"
 	// key for UDN enabled services routes
 	UDNEnabledServiceExternalID = OvnK8sPrefix + "/" + "udn-enabled-default-service"
 	// key for management port name, indicating the netdev link name associated with the given management port representor OVS interface
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// aclEvalStageNames are the logical switch pipeline stages where ACLs are evaluated:
+// to-lport ACLs, from-lport ACLs and from-lport ACLs applied after load balancing.
+var aclEvalStageNames = []string{"ls_out_acl_eval", "ls_in_acl_eval", "ls_in_acl_after_lb_eval"}
+
+var aclFlowStatsRegex = regexp.MustCompile(`cookie=(0x[0-9a-f]+),.* n_packets=([0-9]+), n_bytes=([0-9]+),`)
+
+// ACLStats are the counters of the local OpenFlow flows generated for an ACL.
+type ACLStats struct {
+	Packets int64
+	Bytes   int64
+}
+
+// Since returns the counters of the hits since the old counters. The counters are reset when the flows
+// are reinstalled, in which case all the hits are new.
+func (s ACLStats) Since(old ACLStats) ACLStats {
+	if s.Packets < old.Packets || s.Bytes < old.Bytes {
+		return s
+	}
+	return ACLStats{Packets: s.Packets - old.Packets, Bytes: s.Bytes - old.Bytes}
+}
+
+// ACLStatsCollector periodically reads the counters of the local OpenFlow flows generated for all the ACLs,
+// so that the EgressFirewall, NetworkPolicy and AdminNetworkPolicy rule hits share a single collection.
+type ACLStatsCollector struct {
+	sync.RWMutex
+	// stats are the counters of the last collection by stage-hint, nil until the first collection
+	stats map[string]ACLStats
+}
+
+// NewACLStatsCollector returns a collector of the ACL counters, started with Run.
+func NewACLStatsCollector() *ACLStatsCollector {
+	return &ACLStatsCollector{}
+}
+
+// Run collects the ACL counters every interval until stopCh is closed.
+func (c *ACLStatsCollector) Run(interval time.Duration, stopCh <-chan struct{}) {
+	wait.Until(func() {
+		stats, err := getACLStatsByStageHint()
+		if err != nil {
+			klog.Errorf("Failed to collect the ACL counters: %v", err)
+			return
+		}
+		c.Lock()
+		defer c.Unlock()
+		c.stats = stats
+	}, interval, stopCh)
+}
+
+// GetACLStats returns the counters of the given ACLs at the last collection, by ACL UUID, or false
+// if they were not collected yet. ACLs without flows on this node are not returned.
+func (c *ACLStatsCollector) GetACLStats(aclUUIDs []string) (map[string]ACLStats, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	if c.stats == nil {
+		return nil, false
+	}
+	aclStats := map[string]ACLStats{}
+	for _, aclUUID := range aclUUIDs {
+		if len(aclUUID) < 8 {
+			continue
+		}
+		if stats, ok := c.stats[aclUUID[:8]]; ok {
+			aclStats[aclUUID] = stats
+		}
+	}
+	return aclStats, true
+}
+
+// getACLStatsByStageHint returns the counters of the ACL flows in the local br-int, by stage-hint.
+// northd sets the stage-hint external ID of the logical flows generated for an ACL to the first
+// 32 bits of the ACL UUID, and ovn-controller uses the first 32 bits of the logical flow UUID as the
+// OpenFlow cookie.
+func getACLStatsByStageHint() (map[string]ACLStats, error) {
+	stageHintByCookie := map[uint64]string{}
+	for _, stageName := range aclEvalStageNames {
+		stdout, stderr, err := RunOVNSbctl("--format=csv", "--data=bare", "--no-heading",
+			"--columns=_uuid,external_ids", "find", "Logical_Flow", "external_ids:stage-name="+stageName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find ACL logical flows in stage %s, stderr: %q: %w", stageName, stderr, err)
+		}
+		if err := parseACLLogicalFlows(stdout, stageHintByCookie); err != nil {
+			return nil, err
+		}
+	}
+	if len(stageHintByCookie) == 0 {
+		return map[string]ACLStats{}, nil
+	}
+
+	stdout, stderr, err := RunOVSOfctl("dump-flows", "br-int")
+	if err != nil {
+		return nil, fmt.Errorf("failed to dump br-int flows, stderr: %q: %w", stderr, err)
+	}
+	return ParseACLFlowStats(stdout, stageHintByCookie), nil
+}
+
+// parseACLLogicalFlows adds the OpenFlow cookies of the logical flows in the ovn-sbctl csv output
+// to stageHintByCookie, with their stage-hint.
+func parseACLLogicalFlows(lflows string, stageHintByCookie map[uint64]string) error {
+	for _, line := range strings.Split(lflows, "\n") {
+		lflowUUID, externalIDs, found := strings.Cut(line, ",")
+		if !found || len(lflowUUID) < 8 {
+			continue
+		}
+		for _, externalID := range strings.Fields(strings.Trim(externalIDs, "\"")) {
+			stageHint, found := strings.CutPrefix(externalID, "stage-hint=")
+			if !found {
+				continue
+			}
+			cookie, err := strconv.ParseUint(lflowUUID[:8], 16, 64)
+			if err != nil {
+				return fmt.Errorf("failed to parse logical flow UUID %s: %w", lflowUUID, err)
+			}
+			stageHintByCookie[cookie] = strings.Trim(stageHint, "\"")
+		}
+	}
+	return nil
+}
+
+// ParseACLFlowStats sums the counters of the OpenFlow flows in the dump-flows output per ACL,
+// aclByCookie maps the flow cookies to the ACL identifiers.
+func ParseACLFlowStats(flows string, aclByCookie map[uint64]string) map[string]ACLStats {
+	stats := map[string]ACLStats{}
+	for _, flow := range strings.Split(flows, "\n") {
+		match := aclFlowStatsRegex.FindStringSubmatch(flow)
+		if match == nil {
+			continue
+		}
+		cookie, err := strconv.ParseUint(match[1], 0, 64)
+		if err != nil {
+			continue
+		}
+		aclUUID, ok := aclByCookie[cookie]
+		if !ok {
+			continue
+		}
+		packets, err := strconv.ParseInt(match[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		bytes, err := strconv.ParseInt(match[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		aclStats := stats[aclUUID]
+		aclStats.Packets += packets
+		aclStats.Bytes += bytes
+		stats[aclUUID] = aclStats
+	}
+	return stats
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: Copyright The OVN-Kubernetes Contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseACLLogicalFlows(t *testing.T) {
+	lflows := `0a1b2c3d-0000-0000-0000-000000000001,"source=northd.c:7000 stage-hint=1f2e3d4c stage-name=ls_out_acl_eval"
+0a1b2c3e-0000-0000-0000-000000000002,"source=northd.c:7000 stage-hint=99999999 stage-name=ls_out_acl_eval"
+0a1b2c3f-0000-0000-0000-000000000003,"source=northd.c:7000 stage-name=ls_out_acl_eval"
+`
+	stageHintByCookie := map[uint64]string{}
+	err := parseACLLogicalFlows(lflows, stageHintByCookie)
+	require.NoError(t, err)
+	assert.Equal(t, map[uint64]string{0x0a1b2c3d: "1f2e3d4c", 0x0a1b2c3e: "99999999"}, stageHintByCookie)
+}
+
+func TestParseACLFlowStats(t *testing.T) {
+	flows := ` cookie=0x1f2e3d4c, duration=10.5s, table=44, n_packets=10, n_bytes=840, idle_age=1, priority=1010,ip,metadata=0x1 actions=drop
+ cookie=0x1f2e3d4c, duration=10.5s, table=44, n_packets=5, n_bytes=420, idle_age=1, priority=1010,ipv6,metadata=0x1 actions=drop
+ cookie=0xab12, duration=10.5s, table=44, n_packets=7, n_bytes=588, idle_age=1, priority=1009,ip,metadata=0x1 actions=resubmit(,45)
+ cookie=0x99, duration=10.5s, table=44, n_packets=100, n_bytes=8400, idle_age=1, priority=0 actions=resubmit(,45)
+NXST_FLOW reply (xid=0x4):`
+	stats := ParseACLFlowStats(flows, map[uint64]string{
+		0x1f2e3d4c: "acl1",
+		0xab12:     "acl2",
+		0xcd34:     "acl3",
+	})
+	assert.Equal(t, map[string]ACLStats{
+		"acl1": {Packets: 15, Bytes: 1260},
+		"acl2": {Packets: 7, Bytes: 588},
+	}, stats)
+}
+
+func TestACLStatsCollectorGetACLStats(t *testing.T) {
+	c := NewACLStatsCollector()
+	_, ok := c.GetACLStats([]string{"1f2e3d4c-aaaa-bbbb-cccc-dddddddddddd"})
+	assert.False(t, ok)
+
+	c.stats = map[string]ACLStats{
+		"1f2e3d4c": {Packets: 15, Bytes: 1260},
+		"99999999": {Packets: 7, Bytes: 588},
+	}
+	aclStats, ok := c.GetACLStats([]string{"1f2e3d4c-aaaa-bbbb-cccc-dddddddddddd", "abcdef01-aaaa-bbbb-cccc-dddddddddddd"})
+	assert.True(t, ok)
+	assert.Equal(t, map[string]ACLStats{
+		"1f2e3d4c-aaaa-bbbb-cccc-dddddddddddd": {Packets: 15, Bytes: 1260},
+	}, aclStats)
+}
+
+func TestACLStatsSince(t *testing.T) {
+	assert.Equal(t, ACLStats{Packets: 5, Bytes: 420}, ACLStats{Packets: 15, Bytes: 1260}.Since(ACLStats{Packets: 10, Bytes: 840}))
+	// the counters were reset
+	assert.Equal(t, ACLStats{Packets: 3, Bytes: 252}, ACLStats{Packets: 3, Bytes: 252}.Since(ACLStats{Packets: 10, Bytes: 840}))
+}
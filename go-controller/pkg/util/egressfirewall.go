@@ -29,12 +29,12 @@ func ValidateAndGetEgressFirewallDNSDestination(egressFirewallDestination egress
 	err error) {
 	// Validate the egress firewall rule.
 	if egressFirewallDestination.DNSName != "" {
-		// Validate that DNS name is not wildcard when DNSNameResolver is not enabled.
-		if !config.OVNKubernetesFeature.EnableDNSNameResolver && IsWildcard(egressFirewallDestination.DNSName) {
+		// Validate that DNS name is not wildcard when neither DNSNameResolver nor DNS name learning is enabled.
+		if !IsWildcardDNSNameSupported() && IsWildcard(egressFirewallDestination.DNSName) {
 			return "", fmt.Errorf("wildcard dns name is not supported as rule destination, %s", egressFirewallDestination.DNSName)
 		}
-		// Validate that DNS name if DNSNameResolver is enabled.
-		if config.OVNKubernetesFeature.EnableDNSNameResolver {
+		// Validate that DNS name if DNSNameResolver is enabled, or if the DNS name is wildcard.
+		if config.OVNKubernetesFeature.EnableDNSNameResolver || IsWildcard(egressFirewallDestination.DNSName) {
 			exp := regexp.MustCompile(dnsRegex)
 			if !exp.MatchString(egressFirewallDestination.DNSName) {
 				return "", fmt.Errorf("invalid dns name used as rule destination, %s", egressFirewallDestination.DNSName)
@@ -51,6 +51,12 @@ func IsWildcard(dnsName string) bool {
 	return strings.HasPrefix(dnsName, "*.")
 }
 
+// IsWildcardDNSNameSupported returns true if wildcard DNS names can be used in EgressFirewall rules, either
+// resolved by DNSNameResolver or learned from the DNS responses sent to pods.
+func IsWildcardDNSNameSupported() bool {
+	return config.OVNKubernetesFeature.EnableDNSNameResolver || config.OVNKubernetesFeature.EgressFirewallDNSLearningAddress != ""
+}
+
 // IsDNSNameResolverEnabled retuns true if both EgressFirewall
 // and DNSNameResolver are enabled.
 func IsDNSNameResolverEnabled() bool {
@@ -38,6 +38,8 @@ import (
 	egressservicefake "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/clientset/versioned/fake"
 	networkqos "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/networkqos/v1alpha1"
 	networkqosfake "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/networkqos/v1alpha1/apis/clientset/versioned/fake"
+	observabilityv1 "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1"
+	observabilityfake "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1/apis/clientset/versioned/fake"
 	routeadvertisements "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/routeadvertisements/v1"
 	routeadvertisementsfake "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/routeadvertisements/v1/apis/clientset/versioned/fake"
 	udnv1 "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/userdefinednetwork/v1"
@@ -64,6 +66,7 @@ func GetOVNClientset(objects ...runtime.Object) *OVNClientset {
 	frrObjects := []runtime.Object{}
 	networkConnectObjects := []runtime.Object{}
 	vtepObjects := []runtime.Object{}
+	samplingConfigObjects := []runtime.Object{}
 	for _, object := range objects {
 		switch object.(type) {
 		case *egressip.EgressIP:
@@ -98,6 +101,8 @@ func GetOVNClientset(objects ...runtime.Object) *OVNClientset {
 			networkConnectObjects = append(networkConnectObjects, object)
 		case *vtepv1.VTEP:
 			vtepObjects = append(vtepObjects, object)
+		case *observabilityv1.SamplingConfig:
+			samplingConfigObjects = append(samplingConfigObjects, object)
 		default:
 			v1Objects = append(v1Objects, object)
 		}
@@ -128,6 +133,7 @@ func GetOVNClientset(objects ...runtime.Object) *OVNClientset {
 		NetworkQoSClient:          networkqosfake.NewSimpleClientset(networkQoSObjects...),
 		NetworkConnectClient:      networkconnectfake.NewSimpleClientset(networkConnectObjects...),
 		VTEPClient:                vtepfake.NewSimpleClientset(vtepObjects...),
+		ObservabilityClient:       observabilityfake.NewSimpleClientset(samplingConfigObjects...),
 	}
 }
 
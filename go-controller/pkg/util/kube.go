@@ -55,6 +55,7 @@ import (
 	egressqosclientset "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/egressqos/v1/apis/clientset/versioned"
 	egressserviceclientset "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/egressservice/v1/apis/clientset/versioned"
 	networkqosclientset "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/networkqos/v1alpha1/apis/clientset/versioned"
+	observabilityclientset "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/observability/v1/apis/clientset/versioned"
 	routeadvertisementsclientset "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/routeadvertisements/v1/apis/clientset/versioned"
 	userdefinednetworkclientset "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/userdefinednetwork/v1/apis/clientset/versioned"
 	vtepclientset "github.com/ovn-kubernetes/ovn-kubernetes/go-controller/pkg/crd/vtep/v1/apis/clientset/versioned"
@@ -80,6 +81,7 @@ type OVNClientset struct {
 	FRRClient                 frrclientset.Interface
 	NetworkQoSClient          networkqosclientset.Interface
 	VTEPClient                vtepclientset.Interface
+	ObservabilityClient       observabilityclientset.Interface
 }
 
 // OVNMasterClientset
@@ -101,6 +103,7 @@ type OVNMasterClientset struct {
 	FRRClient                 frrclientset.Interface
 	NetworkQoSClient          networkqosclientset.Interface
 	VTEPClient                vtepclientset.Interface
+	ObservabilityClient       observabilityclientset.Interface
 }
 
 // OVNKubeControllerClientset
@@ -121,6 +124,7 @@ type OVNKubeControllerClientset struct {
 	NetworkQoSClient          networkqosclientset.Interface
 	NetworkConnectClient      networkconnectclientset.Interface
 	VTEPClient                vtepclientset.Interface
+	ObservabilityClient       observabilityclientset.Interface
 }
 
 type OVNNodeClientset struct {
@@ -183,6 +187,7 @@ func (cs *OVNClientset) GetMasterClientset() *OVNMasterClientset {
 		FRRClient:                 cs.FRRClient,
 		NetworkQoSClient:          cs.NetworkQoSClient,
 		VTEPClient:                cs.VTEPClient,
+		ObservabilityClient:       cs.ObservabilityClient,
 	}
 }
 
@@ -203,6 +208,7 @@ func (cs *OVNMasterClientset) GetOVNKubeControllerClientset() *OVNKubeController
 		RouteAdvertisementsClient: cs.RouteAdvertisementsClient,
 		NetworkQoSClient:          cs.NetworkQoSClient,
 		VTEPClient:                cs.VTEPClient,
+		ObservabilityClient:       cs.ObservabilityClient,
 	}
 }
 
@@ -224,6 +230,7 @@ func (cs *OVNClientset) GetOVNKubeControllerClientset() *OVNKubeControllerClient
 		NetworkQoSClient:          cs.NetworkQoSClient,
 		NetworkConnectClient:      cs.NetworkConnectClient,
 		VTEPClient:                cs.VTEPClient,
+		ObservabilityClient:       cs.ObservabilityClient,
 	}
 }
 
@@ -567,6 +574,11 @@ func NewOVNClientset(conf *config.KubernetesConfig) (*OVNClientset, error) {
 		return nil, err
 	}
 
+	observabilityClientset, err := observabilityclientset.NewForConfig(kconfig)
+	if err != nil {
+		return nil, err
+	}
+
 	return &OVNClientset{
 		KubeClient:                kclientset,
 		ANPClient:                 anpClientset,
@@ -586,6 +598,7 @@ func NewOVNClientset(conf *config.KubernetesConfig) (*OVNClientset, error) {
 		FRRClient:                 frrClientset,
 		NetworkQoSClient:          networkqosClientset,
 		VTEPClient:                vtepClientset,
+		ObservabilityClient:       observabilityClientset,
 	}, nil
 }
 
@@ -611,7 +624,14 @@ func GetClusterIPs(service *corev1.Service) []string {
 	return []string{}
 }
 
-// GetExternalAndLBIPs returns an array with the ExternalIPs and LoadBalancer IPs present in the service
+// IsLoadBalancerIngressVIPMode returns true if traffic to the load balancer ingress IP can be delivered to the
+// service backends directly. Ingress IPs with ipMode=Proxy must be reached through the external load balancer.
+func IsLoadBalancerIngressVIPMode(ingress corev1.LoadBalancerIngress) bool {
+	return ingress.IPMode == nil || *ingress.IPMode == corev1.LoadBalancerIPModeVIP
+}
+
+// GetExternalAndLBIPs returns an array with the ExternalIPs and LoadBalancer IPs present in the service.
+// LoadBalancer IPs in Proxy mode are not VIPs and are not returned.
 func GetExternalAndLBIPs(service *corev1.Service) []string {
 	svcVIPs := []string{}
 	for _, externalIP := range service.Spec.ExternalIPs {
@@ -622,7 +642,7 @@ func GetExternalAndLBIPs(service *corev1.Service) []string {
 	}
 	if ServiceTypeHasLoadBalancer(service) {
 		for _, ingressVIP := range service.Status.LoadBalancer.Ingress {
-			if len(ingressVIP.IP) > 0 {
+			if len(ingressVIP.IP) > 0 && IsLoadBalancerIngressVIPMode(ingressVIP) {
 				parsedIngressVIP := utilnet.ParseIPSloppy(ingressVIP.IP)
 				if parsedIngressVIP != nil {
 					svcVIPs = append(svcVIPs, parsedIngressVIP.String())
@@ -677,6 +697,35 @@ func ServiceInternalTrafficPolicyLocal(service *corev1.Service) bool {
 	return service.Spec.InternalTrafficPolicy != nil && *service.Spec.InternalTrafficPolicy == corev1.ServiceInternalTrafficPolicyLocal
 }
 
+// ParseServiceAnnotation returns the value of the given annotation of the service as parsed by parse, and true if
+// the annotation is set to a valid value. Invalid values are logged and ignored.
+func ParseServiceAnnotation[T any](service *corev1.Service, annotation string, parse func(string) (T, error)) (T, bool) {
+	var parsed T
+	value, ok := service.Annotations[annotation]
+	if !ok {
+		return parsed, false
+	}
+	parsed, err := parse(value)
+	if err != nil {
+		klog.Warningf("Ignoring invalid %s annotation value %q of service %s/%s: %v",
+			annotation, value, service.Namespace, service.Name, err)
+		return parsed, false
+	}
+	return parsed, true
+}
+
+// OneOf returns a ParseServiceAnnotation parse function that only accepts the given values.
+func OneOf(values ...string) func(string) (string, error) {
+	return func(value string) (string, error) {
+		for _, valid := range values {
+			if value == valid {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("expected one of %q", values)
+	}
+}
+
 // GetClusterSubnetsWithHostPrefix returns the v4 and v6 cluster subnets, along with their host prefix,
 // in two separate slices
 func GetClusterSubnetsWithHostPrefix() ([]config.CIDRNetworkEntry, []config.CIDRNetworkEntry) {
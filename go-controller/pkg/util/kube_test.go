@@ -279,6 +279,68 @@ func TestServiceTypeHasNodePort(t *testing.T) {
 	}
 }
 
+func TestGetExternalAndLBIPs(t *testing.T) {
+	tests := []struct {
+		desc   string
+		inp    corev1.Service
+		expOut []string
+	}{
+		{
+			desc: "external IPs of a ClusterIP service",
+			inp: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type:        corev1.ServiceTypeClusterIP,
+					ExternalIPs: []string{"4.2.2.2", "42::42"},
+				},
+			},
+			expOut: []string{"4.2.2.2", "42::42"},
+		},
+		{
+			desc: "load balancer ingress IPs in VIP mode",
+			inp: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type:        corev1.ServiceTypeLoadBalancer,
+					ExternalIPs: []string{"4.2.2.2"},
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{IP: "5.5.5.5"},
+							{IP: "6.6.6.6", IPMode: ptr.To(corev1.LoadBalancerIPModeVIP)},
+							{Hostname: "lb.example.com"},
+						},
+					},
+				},
+			},
+			expOut: []string{"4.2.2.2", "5.5.5.5", "6.6.6.6"},
+		},
+		{
+			desc: "load balancer ingress IPs in Proxy mode are skipped",
+			inp: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type: corev1.ServiceTypeLoadBalancer,
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{
+							{IP: "5.5.5.5", IPMode: ptr.To(corev1.LoadBalancerIPModeProxy)},
+							{IP: "6.6.6.6"},
+						},
+					},
+				},
+			},
+			expOut: []string{"6.6.6.6"},
+		},
+	}
+
+	for i, tc := range tests {
+		t.Run(fmt.Sprintf("%d:%s", i, tc.desc), func(t *testing.T) {
+			res := GetExternalAndLBIPs(&tc.inp)
+			assert.Equal(t, tc.expOut, res)
+		})
+	}
+}
+
 func TestGetNodePrimaryIP(t *testing.T) {
 	tests := []struct {
 		desc   string
@@ -17,6 +17,12 @@ import (
 const (
 	// Annotation used to enable/disable multicast in the namespace
 	NsMulticastAnnotation = "k8s.ovn.org/multicast-enabled"
+	// Annotation used to restrict the multicast groups that the pods in the namespace can send to and receive from,
+	// a comma-separated list of multicast IPs or CIDRs
+	NsMulticastAllowedGroupsAnnotation = "k8s.ovn.org/multicast-allowed-groups"
+	// Annotation used to allow the pods in the namespace to receive multicast traffic from the pods in the
+	// namespaces matching the label selector
+	NsMulticastSourceNamespaceSelectorAnnotation = "k8s.ovn.org/multicast-source-namespace-selector"
 	// Annotations used by multiple external gateways feature
 	RoutingExternalGWsAnnotation    = "k8s.ovn.org/routing-external-gws"
 	RoutingNamespaceAnnotation      = "k8s.ovn.org/routing-namespaces"
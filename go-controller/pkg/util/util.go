@@ -892,6 +892,66 @@ func GetEndpointsForService(endpointSlices []*discoveryv1.EndpointSlice, service
 	return globalEndpoints, localEndpoints, errors.Join(validationErrors...)
 }
 
+// PortToZoneToLBEndpoints maps service port keys to topology zones and the load balancer endpoints hinted for them.
+// e.g. map["TCP/http"]["zone-a"] = LBEndpoints{Port: 8080, V4IPs: []string{"192.168.1.10"}}.
+type PortToZoneToLBEndpoints map[string]map[string]LBEndpoints
+
+// GetZoneEndpointsForService extracts the eligible endpoints of a Service per topology zone from the zone hints of
+// its EndpointSlices, set for services with trafficDistribution PreferClose or topology aware routing.
+// As with kube-proxy, zone hints are only used for a service port if all its eligible endpoints have zone hints,
+// service ports without usable zone hints are not part of the result.
+//
+// Example output:
+//
+//	{"TCP/http": {"zone-a": {Port: 8080, V4IPs: ["192.168.1.10"]}, "zone-b": {Port: 8080, V4IPs: ["192.168.1.11"]}}}
+func GetZoneEndpointsForService(endpointSlices []*discoveryv1.EndpointSlice, service *corev1.Service) PortToZoneToLBEndpoints {
+	zoneEndpoints := make(PortToZoneToLBEndpoints)
+
+	validServicePortKeys := map[string]bool{}
+	for _, servicePort := range service.Spec.Ports {
+		validServicePortKeys[GetServicePortKey(servicePort.Protocol, servicePort.Name)] = true
+	}
+
+	for portName, protocolMap := range newTargetEndpoints(endpointSlices) {
+		for protocol, portNumberMap := range protocolMap {
+			slicePortKey := GetServicePortKey(protocol, portName)
+			if !validServicePortKeys[slicePortKey] || len(portNumberMap) == 0 {
+				continue
+			}
+			// same as GetEndpointsForService, only the first target port number is supported
+			portNumbers := maps.Keys(portNumberMap)
+			slices.Sort(portNumbers)
+			targetPortNumber := portNumbers[0]
+
+			eligibleEndpoints := getEligibleEndpoints(portNumberMap[targetPortNumber], service)
+			endpointsByZone := map[string][]discoveryv1.Endpoint{}
+			for _, endpoint := range eligibleEndpoints {
+				if endpoint.Hints == nil || len(endpoint.Hints.ForZones) == 0 {
+					endpointsByZone = nil
+					break
+				}
+				for _, zone := range endpoint.Hints.ForZones {
+					endpointsByZone[zone.Name] = append(endpointsByZone[zone.Name], endpoint)
+				}
+			}
+			if len(endpointsByZone) == 0 {
+				continue
+			}
+			zoneEndpoints[slicePortKey] = map[string]LBEndpoints{}
+			for zone, endpoints := range endpointsByZone {
+				lbe, err := buildLBEndpoints(service, targetPortNumber, endpoints)
+				if err != nil {
+					klog.Warningf("Failed to build zone endpoints for zone %s port %s: %v", zone, slicePortKey, err)
+					continue
+				}
+				zoneEndpoints[slicePortKey][zone] = lbe
+			}
+		}
+	}
+	klog.V(5).Infof("Zone endpoints for %s/%s: %v", service.Namespace, service.Name, zoneEndpoints)
+	return zoneEndpoints
+}
+
 // FindServicePortForEndpointSlicePort returns the ServicePort that corresponds to an EndpointSlice port
 // by matching the port name and protocol. This is the canonical way to map EndpointSlice ports to
 // Service ports, as Kubernetes guarantees that ServicePort.Name matches EndpointPort.Name.